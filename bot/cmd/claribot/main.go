@@ -86,11 +86,19 @@ func main() {
 	}
 
 	// Initialize Claude manager
+	var transcriptMax int64
+	if cfg.Claude.TranscriptMaxMB > 0 {
+		transcriptMax = int64(cfg.Claude.TranscriptMaxMB) << 20
+	}
 	claude.Init(claude.Config{
-		Timeout:    time.Duration(cfg.Claude.Timeout) * time.Second,
-		MaxTimeout: time.Duration(cfg.Claude.MaxTimeout) * time.Second,
-		Max:        cfg.Claude.Max,
+		Timeout:            time.Duration(cfg.Claude.Timeout) * time.Second,
+		MaxTimeout:         time.Duration(cfg.Claude.MaxTimeout) * time.Second,
+		Max:                cfg.Claude.Max,
+		TranscriptMaxBytes: transcriptMax,
+		TranscriptKeepDays: cfg.Claude.TranscriptKeepDays,
+		TranscriptKeepRuns: cfg.Claude.TranscriptKeepRuns,
 	})
+	claude.SetQuotaFunc(task.ProjectQuota)
 	logger.Info("Claude manager initialized (max=%d, timeout=%ds, max_timeout=%ds)", cfg.Claude.Max, cfg.Claude.Timeout, cfg.Claude.MaxTimeout)
 
 	// Initialize Bridge manager (Agent SDK)
@@ -182,6 +190,19 @@ func main() {
 	// Initialize task notifier (reuse same notifier callback)
 	task.Init(notifier)
 
+	// Recover traversals cut short by the previous shutdown (mark interrupted or auto-resume)
+	// and watch task files so external edits reach the DB
+	if projects, err := project.ListAll(); err != nil {
+		logger.Error("Failed to list projects for traversal recovery: %v", err)
+	} else {
+		for _, p := range projects {
+			task.RecoverTraversals(p.Path)
+			if err := task.Watch(p.Path); err != nil {
+				logger.Error("Failed to watch task files (%s): %v", p.ID, err)
+			}
+		}
+	}
+
 	// Setup HTTP mux
 	mux := http.NewServeMux()
 
@@ -245,6 +266,9 @@ func main() {
 	schedule.Shutdown()
 	logger.Info("Scheduler stopped")
 
+	task.StopWatchers()
+	logger.Info("Task file watchers stopped")
+
 	if bot != nil {
 		bot.Stop()
 		logger.Info("Telegram bot stopped")
@@ -323,6 +347,7 @@ func handleAPIRequest(w http.ResponseWriter, r *http.Request) {
 	}
 
 	snapshot := router.SnapshotContext()
+	snapshot.Request = r.Context()
 	result := router.Execute(snapshot, cmdStr)
 
 	if !result.Success {
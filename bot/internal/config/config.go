@@ -39,6 +39,10 @@ type ClaudeConfig struct {
 	MaxTimeout int `yaml:"max_timeout"` // absolute timeout seconds, default: 1800 (30 min)
 	Max        int `yaml:"max"`         // max concurrent, default: 3
 	ContextMax int `yaml:"context_max"` // max recent messages in context, default: 5
+
+	TranscriptMaxMB    int `yaml:"transcript_max_mb"`    // raw output cap per run transcript in MB, default: 10 (-1 = no transcripts)
+	TranscriptKeepDays int `yaml:"transcript_keep_days"` // delete run transcripts older than this, default: 14 (-1 = no age limit)
+	TranscriptKeepRuns int `yaml:"transcript_keep_runs"` // run transcripts kept per project, default: 200 (-1 = no count limit)
 }
 
 // ProjectConfig for project management
@@ -75,6 +79,9 @@ const (
 	DefaultMaxTimeout = 1800 // 30 minutes
 	DefaultMaxClaude  = 10
 	DefaultContextMax     = 5
+	DefaultTranscriptMaxMB    = 10
+	DefaultTranscriptKeepDays = 14
+	DefaultTranscriptKeepRuns = 200
 	DefaultParallelCount  = 3
 	DefaultPageSize       = 10
 	DefaultLogLevel   = "info"
@@ -120,6 +127,9 @@ func (c *Config) setDefaults() {
 	c.Claude.MaxTimeout = DefaultMaxTimeout
 	c.Claude.Max = DefaultMaxClaude
 	c.Claude.ContextMax = DefaultContextMax
+	c.Claude.TranscriptMaxMB = DefaultTranscriptMaxMB
+	c.Claude.TranscriptKeepDays = DefaultTranscriptKeepDays
+	c.Claude.TranscriptKeepRuns = DefaultTranscriptKeepRuns
 	c.Bridge.Path = DefaultBridgePath
 	c.Bridge.NodePath = DefaultBridgeNodePath
 	c.Bridge.IdleTimeout = DefaultBridgeIdleTimeout
@@ -148,6 +158,15 @@ func (c *Config) applyDefaults() {
 	if c.Claude.ContextMax == 0 {
 		c.Claude.ContextMax = DefaultContextMax
 	}
+	if c.Claude.TranscriptMaxMB == 0 {
+		c.Claude.TranscriptMaxMB = DefaultTranscriptMaxMB
+	}
+	if c.Claude.TranscriptKeepDays == 0 {
+		c.Claude.TranscriptKeepDays = DefaultTranscriptKeepDays
+	}
+	if c.Claude.TranscriptKeepRuns == 0 {
+		c.Claude.TranscriptKeepRuns = DefaultTranscriptKeepRuns
+	}
 	if c.Bridge.Path == "" {
 		c.Bridge.Path = DefaultBridgePath
 	}
@@ -214,6 +233,22 @@ func (c *Config) Validate() []string {
 		c.Claude.ContextMax = 20
 	}
 
+	if c.Claude.TranscriptMaxMB > 100 {
+		warnings = append(warnings, fmt.Sprintf("transcript_max_mb %d too high, using 100", c.Claude.TranscriptMaxMB))
+		c.Claude.TranscriptMaxMB = 100
+	}
+
+	// 0 means unset (default); -1 turns the limit off
+	if c.Claude.TranscriptKeepDays < -1 {
+		warnings = append(warnings, fmt.Sprintf("transcript_keep_days %d invalid, using default %d", c.Claude.TranscriptKeepDays, DefaultTranscriptKeepDays))
+		c.Claude.TranscriptKeepDays = DefaultTranscriptKeepDays
+	}
+
+	if c.Claude.TranscriptKeepRuns < -1 {
+		warnings = append(warnings, fmt.Sprintf("transcript_keep_runs %d invalid, using default %d", c.Claude.TranscriptKeepRuns, DefaultTranscriptKeepRuns))
+		c.Claude.TranscriptKeepRuns = DefaultTranscriptKeepRuns
+	}
+
 	if c.Project.DefaultParallel < 1 {
 		warnings = append(warnings, fmt.Sprintf("default_parallel %d invalid, using default %d", c.Project.DefaultParallel, DefaultParallelCount))
 		c.Project.DefaultParallel = DefaultParallelCount
@@ -40,8 +40,12 @@ func TestValidate(t *testing.T) {
 		{
 			name: "valid config",
 			cfg: Config{
-				Service:    ServiceConfig{Host: "127.0.0.1", Port: 8080},
-				Claude:     ClaudeConfig{Timeout: 600, MaxTimeout: 1800, Max: 3},
+				Service: ServiceConfig{Host: "127.0.0.1", Port: 8080},
+				Claude: ClaudeConfig{
+					Timeout: 600, MaxTimeout: 1800, Max: 3, ContextMax: 5,
+					TranscriptKeepDays: 14, TranscriptKeepRuns: 200,
+				},
+				Project:    ProjectConfig{DefaultParallel: 3},
 				Pagination: PaginationConfig{PageSize: 10},
 			},
 			wantWarnings: 0,
@@ -49,8 +53,12 @@ func TestValidate(t *testing.T) {
 		{
 			name: "invalid port",
 			cfg: Config{
-				Service:    ServiceConfig{Host: "127.0.0.1", Port: 99999},
-				Claude:     ClaudeConfig{Timeout: 600, MaxTimeout: 1800, Max: 3},
+				Service: ServiceConfig{Host: "127.0.0.1", Port: 99999},
+				Claude: ClaudeConfig{
+					Timeout: 600, MaxTimeout: 1800, Max: 3, ContextMax: 5,
+					TranscriptKeepDays: 14, TranscriptKeepRuns: 200,
+				},
+				Project:    ProjectConfig{DefaultParallel: 3},
 				Pagination: PaginationConfig{PageSize: 10},
 			},
 			wantWarnings: 1,
@@ -58,8 +66,12 @@ func TestValidate(t *testing.T) {
 		{
 			name: "timeout too low",
 			cfg: Config{
-				Service:    ServiceConfig{Host: "127.0.0.1", Port: 8080},
-				Claude:     ClaudeConfig{Timeout: 10, MaxTimeout: 1800, Max: 3},
+				Service: ServiceConfig{Host: "127.0.0.1", Port: 8080},
+				Claude: ClaudeConfig{
+					Timeout: 10, MaxTimeout: 1800, Max: 3, ContextMax: 5,
+					TranscriptKeepDays: 14, TranscriptKeepRuns: 200,
+				},
+				Project:    ProjectConfig{DefaultParallel: 3},
 				Pagination: PaginationConfig{PageSize: 10},
 			},
 			wantWarnings: 1,
@@ -67,8 +79,12 @@ func TestValidate(t *testing.T) {
 		{
 			name: "max claude too high",
 			cfg: Config{
-				Service:    ServiceConfig{Host: "127.0.0.1", Port: 8080},
-				Claude:     ClaudeConfig{Timeout: 600, MaxTimeout: 1800, Max: 100},
+				Service: ServiceConfig{Host: "127.0.0.1", Port: 8080},
+				Claude: ClaudeConfig{
+					Timeout: 600, MaxTimeout: 1800, Max: 100, ContextMax: 5,
+					TranscriptKeepDays: 14, TranscriptKeepRuns: 200,
+				},
+				Project:    ProjectConfig{DefaultParallel: 3},
 				Pagination: PaginationConfig{PageSize: 10},
 			},
 			wantWarnings: 1,
@@ -76,12 +92,42 @@ func TestValidate(t *testing.T) {
 		{
 			name: "page size too high",
 			cfg: Config{
-				Service:    ServiceConfig{Host: "127.0.0.1", Port: 8080},
-				Claude:     ClaudeConfig{Timeout: 600, MaxTimeout: 1800, Max: 3},
+				Service: ServiceConfig{Host: "127.0.0.1", Port: 8080},
+				Claude: ClaudeConfig{
+					Timeout: 600, MaxTimeout: 1800, Max: 3, ContextMax: 5,
+					TranscriptKeepDays: 14, TranscriptKeepRuns: 200,
+				},
+				Project:    ProjectConfig{DefaultParallel: 3},
 				Pagination: PaginationConfig{PageSize: 500},
 			},
 			wantWarnings: 1,
 		},
+		{
+			name: "transcript keep days invalid",
+			cfg: Config{
+				Service: ServiceConfig{Host: "127.0.0.1", Port: 8080},
+				Claude: ClaudeConfig{
+					Timeout: 600, MaxTimeout: 1800, Max: 3, ContextMax: 5,
+					TranscriptKeepDays: -2, TranscriptKeepRuns: 200,
+				},
+				Project:    ProjectConfig{DefaultParallel: 3},
+				Pagination: PaginationConfig{PageSize: 10},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name: "transcript limits off",
+			cfg: Config{
+				Service: ServiceConfig{Host: "127.0.0.1", Port: 8080},
+				Claude: ClaudeConfig{
+					Timeout: 600, MaxTimeout: 1800, Max: 3, ContextMax: 5,
+					TranscriptKeepDays: -1, TranscriptKeepRuns: -1,
+				},
+				Project:    ProjectConfig{DefaultParallel: 3},
+				Pagination: PaginationConfig{PageSize: 10},
+			},
+			wantWarnings: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -131,6 +131,7 @@ CREATE TABLE IF NOT EXISTS schedule_runs (
         CHECK(status IN ('running', 'done', 'failed')),
     result TEXT DEFAULT '',
     error TEXT DEFAULT '',
+    run_id TEXT DEFAULT '',
     started_at TEXT NOT NULL,
     completed_at TEXT,
     FOREIGN KEY (schedule_id) REFERENCES schedules(id) ON DELETE CASCADE
@@ -149,6 +150,7 @@ CREATE TABLE IF NOT EXISTS messages (
         CHECK(status IN ('pending', 'processing', 'done', 'failed')),
     result TEXT DEFAULT '',
     error TEXT DEFAULT '',
+    run_id TEXT DEFAULT '',
     created_at TEXT NOT NULL,
     completed_at TEXT
 );
@@ -187,6 +189,8 @@ CREATE TABLE IF NOT EXISTS auth (
 		// Create indexes for new columns (must be after ALTER TABLE)
 		`CREATE INDEX IF NOT EXISTS idx_projects_category ON projects(category)`,
 		`CREATE INDEX IF NOT EXISTS idx_projects_pinned ON projects(pinned)`,
+		// Add run_id (transcript link) to schedule_runs
+		`ALTER TABLE schedule_runs ADD COLUMN run_id TEXT DEFAULT ''`,
 	}
 
 	// Recreate projects table to remove type column
@@ -260,10 +264,13 @@ CREATE TABLE IF NOT EXISTS auth (
 		}
 	}
 
+	// Add run_id (transcript link) to messages (after the rebuild above, which copies columns positionally)
+	db.Exec(`ALTER TABLE messages ADD COLUMN run_id TEXT DEFAULT ''`)
+
 	return nil
 }
 
-// MigrateLocal creates local DB schema (tasks, traversals, config)
+// MigrateLocal creates local DB schema (tasks, task_labels, saved_queries, task_events, traversals, traversal_items, cycle_state, config)
 func (db *DB) MigrateLocal() error {
 	schema := `
 CREATE TABLE IF NOT EXISTS tasks (
@@ -271,10 +278,11 @@ CREATE TABLE IF NOT EXISTS tasks (
     parent_id INTEGER,
     title TEXT NOT NULL,
     status TEXT DEFAULT 'todo'
-        CHECK(status IN ('todo', 'split', 'planned', 'done', 'failed')),
+        CHECK(status IN ('todo', 'split', 'planned', 'review', 'done', 'failed')),
     priority INTEGER DEFAULT 0,
     is_leaf INTEGER DEFAULT 1,
     depth INTEGER DEFAULT 0,
+    replans INTEGER DEFAULT 0,
     created_at TEXT NOT NULL,
     updated_at TEXT NOT NULL,
     FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE
@@ -284,12 +292,67 @@ CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks(parent_id);
 CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
 CREATE INDEX IF NOT EXISTS idx_tasks_leaf ON tasks(is_leaf);
 
+CREATE TABLE IF NOT EXISTS task_deps (
+    task_id INTEGER NOT NULL,
+    depends_on INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (task_id, depends_on),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (depends_on) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_deps_depends_on ON task_deps(depends_on);
+
+CREATE TABLE IF NOT EXISTS graph_nodes (
+    task_id INTEGER PRIMARY KEY,
+    node_id TEXT NOT NULL UNIQUE,
+    feature_id TEXT NOT NULL,
+    layer TEXT NOT NULL,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_graph_nodes_layer ON graph_nodes(layer);
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    PRIMARY KEY (task_id, label),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_labels_label ON task_labels(label);
+
+CREATE TABLE IF NOT EXISTS saved_queries (
+    name TEXT PRIMARY KEY,
+    query TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS task_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER NOT NULL,
+    phase TEXT NOT NULL CHECK(phase IN ('plan', 'run')),
+    attempt INTEGER NOT NULL,
+    status TEXT DEFAULT 'running' CHECK(status IN ('running', 'done', 'failed', 'cancelled')),
+    exit_code INTEGER,
+    error_type TEXT DEFAULT '',
+    error TEXT DEFAULT '',
+    output_path TEXT DEFAULT '',
+    run_id TEXT DEFAULT '',
+    started_at TEXT NOT NULL,
+    finished_at TEXT,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_attempts_task ON task_attempts(task_id);
+
 CREATE TABLE IF NOT EXISTS traversals (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     type TEXT NOT NULL CHECK(type IN ('plan', 'run', 'cycle')),
     target_id INTEGER,
     trigger TEXT DEFAULT '',
-    status TEXT DEFAULT 'running' CHECK(status IN ('running', 'done', 'failed', 'cancelled')),
+    status TEXT DEFAULT 'running' CHECK(status IN ('running', 'done', 'failed', 'cancelled', 'interrupted')),
     total INTEGER DEFAULT 0,
     success INTEGER DEFAULT 0,
     failed INTEGER DEFAULT 0,
@@ -301,6 +364,49 @@ CREATE TABLE IF NOT EXISTS traversals (
 CREATE INDEX IF NOT EXISTS idx_traversals_type ON traversals(type);
 CREATE INDEX IF NOT EXISTS idx_traversals_status ON traversals(status);
 
+CREATE TABLE IF NOT EXISTS traversal_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    traversal_id INTEGER NOT NULL,
+    task_id INTEGER NOT NULL,
+    title TEXT DEFAULT '',
+    phase TEXT NOT NULL CHECK(phase IN ('plan', 'run')),
+    result TEXT NOT NULL,
+    error_type TEXT DEFAULT '',
+    exit_code INTEGER,
+    duration_ms INTEGER DEFAULT 0,
+    started_at TEXT NOT NULL,
+    finished_at TEXT NOT NULL,
+    FOREIGN KEY (traversal_id) REFERENCES traversals(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_traversal_items_traversal ON traversal_items(traversal_id);
+CREATE INDEX IF NOT EXISTS idx_traversal_items_task ON traversal_items(task_id);
+
+CREATE TABLE IF NOT EXISTS task_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER NOT NULL,
+    from_status TEXT DEFAULT '',
+    to_status TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    traversal_id INTEGER,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (traversal_id) REFERENCES traversals(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_task ON task_events(task_id);
+
+CREATE TABLE IF NOT EXISTS cycle_state (
+    traversal_id INTEGER PRIMARY KEY,
+    type TEXT NOT NULL,
+    root_id INTEGER DEFAULT 0,
+    phase TEXT DEFAULT '',
+    target_total INTEGER DEFAULT 0,
+    completed INTEGER DEFAULT 0,
+    current_task_id INTEGER DEFAULT 0,
+    started_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS config (
     key TEXT PRIMARY KEY,
     value TEXT NOT NULL,
@@ -338,7 +444,7 @@ CREATE INDEX IF NOT EXISTS idx_specs_status ON specs(status);
 			parent_id INTEGER,
 			title TEXT NOT NULL,
 			status TEXT DEFAULT 'todo'
-				CHECK(status IN ('todo', 'split', 'planned', 'done', 'failed')),
+				CHECK(status IN ('todo', 'split', 'planned', 'review', 'done', 'failed')),
 			priority INTEGER DEFAULT 0,
 			is_leaf INTEGER DEFAULT 1,
 			depth INTEGER DEFAULT 0,
@@ -374,7 +480,34 @@ CREATE INDEX IF NOT EXISTS idx_specs_status ON specs(status);
 			parent_id INTEGER,
 			title TEXT NOT NULL,
 			status TEXT DEFAULT 'todo'
-				CHECK(status IN ('todo', 'split', 'planned', 'done', 'failed')),
+				CHECK(status IN ('todo', 'split', 'planned', 'review', 'done', 'failed')),
+			priority INTEGER DEFAULT 0,
+			is_leaf INTEGER DEFAULT 1,
+			depth INTEGER DEFAULT 0,
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE
+		)`)
+		db.Exec(`INSERT INTO tasks_new (id, parent_id, title, status, priority, is_leaf, depth, created_at, updated_at)
+			SELECT id, parent_id, title, status, COALESCE(priority,0), COALESCE(is_leaf,1), COALESCE(depth,0), created_at, updated_at
+			FROM tasks`)
+		db.Exec(`DROP TABLE tasks`)
+		db.Exec(`ALTER TABLE tasks_new RENAME TO tasks`)
+		db.Exec(`CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks(parent_id)`)
+		db.Exec(`CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status)`)
+		db.Exec(`CREATE INDEX IF NOT EXISTS idx_tasks_leaf ON tasks(is_leaf)`)
+		db.Exec(`PRAGMA foreign_keys=ON`)
+	}
+
+	// Add 'review' status (plan approval gate) to the CHECK constraint of existing DBs
+	if err == nil && !strings.Contains(taskSQL, "spec_ready") && !strings.Contains(taskSQL, "spec TEXT") && !strings.Contains(taskSQL, "'review'") {
+		db.Exec(`PRAGMA foreign_keys=OFF`)
+		db.Exec(`CREATE TABLE tasks_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			parent_id INTEGER,
+			title TEXT NOT NULL,
+			status TEXT DEFAULT 'todo'
+				CHECK(status IN ('todo', 'split', 'planned', 'review', 'done', 'failed')),
 			priority INTEGER DEFAULT 0,
 			is_leaf INTEGER DEFAULT 1,
 			depth INTEGER DEFAULT 0,
@@ -393,11 +526,40 @@ CREATE INDEX IF NOT EXISTS idx_specs_status ON specs(status);
 		db.Exec(`PRAGMA foreign_keys=ON`)
 	}
 
+	// Add 'interrupted' status (traversal cut short by a restart) to existing traversals tables
+	var travSQL string
+	if db.QueryRow(`SELECT sql FROM sqlite_master WHERE type='table' AND name='traversals'`).Scan(&travSQL) == nil && !strings.Contains(travSQL, "'interrupted'") {
+		db.Exec(`PRAGMA foreign_keys=OFF`)
+		db.Exec(`CREATE TABLE traversals_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type TEXT NOT NULL CHECK(type IN ('plan', 'run', 'cycle')),
+			target_id INTEGER,
+			trigger TEXT DEFAULT '',
+			status TEXT DEFAULT 'running' CHECK(status IN ('running', 'done', 'failed', 'cancelled', 'interrupted')),
+			total INTEGER DEFAULT 0,
+			success INTEGER DEFAULT 0,
+			failed INTEGER DEFAULT 0,
+			started_at TEXT NOT NULL,
+			finished_at TEXT,
+			FOREIGN KEY (target_id) REFERENCES tasks(id) ON DELETE SET NULL
+		)`)
+		db.Exec(`INSERT INTO traversals_new (id, type, target_id, trigger, status, total, success, failed, started_at, finished_at)
+			SELECT id, type, target_id, trigger, status, total, success, failed, started_at, finished_at
+			FROM traversals`)
+		db.Exec(`DROP TABLE traversals`)
+		db.Exec(`ALTER TABLE traversals_new RENAME TO traversals`)
+		db.Exec(`CREATE INDEX IF NOT EXISTS idx_traversals_type ON traversals(type)`)
+		db.Exec(`CREATE INDEX IF NOT EXISTS idx_traversals_status ON traversals(status)`)
+		db.Exec(`PRAGMA foreign_keys=ON`)
+	}
+
 	// Run migrations for existing tables (errors ignored: column/index already exists)
 	migrations := []string{
 		`ALTER TABLE tasks ADD COLUMN is_leaf INTEGER DEFAULT 1`,
 		`ALTER TABLE tasks ADD COLUMN depth INTEGER DEFAULT 0`,
 		`ALTER TABLE tasks ADD COLUMN priority INTEGER DEFAULT 0`,
+		`ALTER TABLE tasks ADD COLUMN replans INTEGER DEFAULT 0`,
+		`ALTER TABLE task_attempts ADD COLUMN run_id TEXT DEFAULT ''`,
 		`CREATE INDEX IF NOT EXISTS idx_tasks_leaf ON tasks(is_leaf)`,
 		// Migrate status values: spec_ready→todo, plan_ready→planned, subdivided→split
 		`UPDATE tasks SET status = 'todo' WHERE status = 'spec_ready'`,
@@ -410,5 +572,9 @@ CREATE INDEX IF NOT EXISTS idx_specs_status ON specs(status);
 		db.Exec(migration)
 	}
 
+	// Full-text index of task files (spec, plan, report, error).
+	// Needs FTS5 (build with -tags sqlite_fts5); without it the table is missing and search is off.
+	db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS task_search USING fts5(task_id UNINDEXED, kind UNINDEXED, content, tokenize = 'unicode61')`)
+
 	return nil
 }
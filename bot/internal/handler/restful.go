@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,6 +10,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -56,7 +59,18 @@ func decodeBody(r *http.Request, v interface{}) error {
 
 // getContextFromRequest returns context based on X-Clari-Cwd header or falls back to global context.
 // If cwd is inside a registered project path, use that project.
+// Requests carrying X-Clari-Cwd come from the CLI, the others from the Web UI.
 func (r *Router) getContextFromRequest(req *http.Request) *Context {
+	ctx := r.projectContextFromRequest(req)
+	ctx.Actor = task.ActorGUI
+	if req.Header.Get("X-Clari-Cwd") != "" {
+		ctx.Actor = task.ActorCLI
+	}
+	ctx.Request = req.Context()
+	return ctx
+}
+
+func (r *Router) projectContextFromRequest(req *http.Request) *Context {
 	cwd := req.Header.Get("X-Clari-Cwd")
 	if cwd == "" {
 		return r.SnapshotContext()
@@ -316,16 +330,48 @@ func (r *Router) HandleListTasks(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	page, pageSize := r.parsePage(req)
+	q := req.URL.Query()
+	if name := q.Get("query"); name != "" {
+		writeResult(w, task.RunQuery(ctx.ProjectPath, name, pagination.NewPageRequest(page, pageSize)))
+		return
+	}
+	filter, err := task.NewListFilter(q.Get("label"), q.Get("status"), q.Get("depth"), q.Get("root"), q.Get("text"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !filter.IsEmpty() {
+		writeResult(w, task.ListFiltered(ctx.ProjectPath, filter, "task list "+filter.String(), pagination.NewPageRequest(page, pageSize)))
+		return
+	}
+
 	var parentID *int
-	if p := req.URL.Query().Get("parent_id"); p != "" {
+	if p := q.Get("parent_id"); p != "" {
 		if pid, err := strconv.Atoi(p); err == nil {
 			parentID = &pid
 		}
 	}
-	page, pageSize := r.parsePage(req)
 	writeResult(w, task.List(ctx.ProjectPath, parentID, pagination.NewPageRequest(page, pageSize)))
 }
 
+// HandleSearchTasks handles GET /api/tasks/search?q=...
+// Full-text search over task specs, plans, reports and errors with ranked snippets.
+func (r *Router) HandleSearchTasks(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	q := req.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		writeError(w, http.StatusBadRequest, "q required")
+		return
+	}
+	page, pageSize := r.parsePage(req)
+	writeResult(w, task.Search(ctx.ProjectPath, q, pagination.NewPageRequest(page, pageSize)))
+}
+
 // HandleAddTask handles POST /api/tasks
 func (r *Router) HandleAddTask(w http.ResponseWriter, req *http.Request) {
 	ctx := r.getContextFromRequest(req)
@@ -334,9 +380,10 @@ func (r *Router) HandleAddTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	var body struct {
-		Title    string `json:"title"`
-		ParentID *int   `json:"parent_id"`
-		Spec     string `json:"spec"`
+		Title     string `json:"title"`
+		ParentID  *int   `json:"parent_id"`
+		Spec      string `json:"spec"`
+		DependsOn []int  `json:"depends_on"`
 	}
 	if err := decodeBody(req, &body); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
@@ -346,7 +393,42 @@ func (r *Router) HandleAddTask(w http.ResponseWriter, req *http.Request) {
 		writeError(w, http.StatusBadRequest, "title or spec required")
 		return
 	}
-	result := task.Add(ctx.ProjectPath, body.Title, body.ParentID, body.Spec)
+	result := task.AddWithDeps(ctx.ProjectPath, body.Title, body.ParentID, body.Spec, body.DependsOn)
+	status := http.StatusCreated
+	if !result.Success {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, result)
+}
+
+// HandleGetGraph handles GET /api/tasks/graph
+func (r *Router) HandleGetGraph(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	writeResult(w, task.ListGraph(ctx.ProjectPath))
+}
+
+// HandleBuildGraph handles POST /api/tasks/graph
+// Body: {"layers": [...], "features": [{"id", "title", "spec", "spec_file", "layers", "refs"}]}
+func (r *Router) HandleBuildGraph(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	var body task.GraphSpec
+	if err := decodeBody(req, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if len(body.Features) == 0 {
+		writeError(w, http.StatusBadRequest, "features required")
+		return
+	}
+	result := task.BuildGraph(ctx.ProjectPath, &body)
 	status := http.StatusCreated
 	if !result.Success {
 		status = http.StatusBadRequest
@@ -369,7 +451,40 @@ func (r *Router) HandleGetTask(w http.ResponseWriter, req *http.Request) {
 	writeResult(w, task.Get(ctx.ProjectPath, id))
 }
 
+// HandleGetTaskDiff handles GET /api/tasks/{id}/diff
+func (r *Router) HandleGetTaskDiff(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	id := req.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "task id required")
+		return
+	}
+	writeResult(w, task.GetDiff(ctx.ProjectPath, id))
+}
+
+// HandleGetTaskEvents handles GET /api/tasks/{id}/events
+func (r *Router) HandleGetTaskEvents(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	id := req.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "task id required")
+		return
+	}
+	writeResult(w, task.ListEvents(ctx.ProjectPath, id))
+}
+
 // HandleUpdateTask handles PATCH /api/tasks/{id}
+// Supports two formats:
+// 1. Single field: {"field": "priority", "value": "2"}
+// 2. Dependencies: {"depends_on": [3, 5]} (empty array clears)
 func (r *Router) HandleUpdateTask(w http.ResponseWriter, req *http.Request) {
 	ctx := r.getContextFromRequest(req)
 	if ctx.ProjectPath == "" {
@@ -382,18 +497,32 @@ func (r *Router) HandleUpdateTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	var body struct {
-		Field string `json:"field"`
-		Value string `json:"value"`
+		Field     string    `json:"field"`
+		Value     string    `json:"value"`
+		DependsOn *[]int    `json:"depends_on"`
+		Labels    *[]string `json:"labels"`
 	}
 	if err := decodeBody(req, &body); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
 		return
 	}
+	if body.Field == "" && body.DependsOn != nil {
+		ids := make([]string, len(*body.DependsOn))
+		for i, dep := range *body.DependsOn {
+			ids[i] = strconv.Itoa(dep)
+		}
+		writeResult(w, task.Set(ctx.ProjectPath, id, "depends_on", strings.Join(ids, ","), ctx.Actor))
+		return
+	}
+	if body.Field == "" && body.Labels != nil {
+		writeResult(w, task.Set(ctx.ProjectPath, id, "labels", strings.Join(*body.Labels, ","), ctx.Actor))
+		return
+	}
 	if body.Field == "" {
 		writeError(w, http.StatusBadRequest, "field required")
 		return
 	}
-	writeResult(w, task.Set(ctx.ProjectPath, id, body.Field, body.Value))
+	writeResult(w, task.Set(ctx.ProjectPath, id, body.Field, body.Value, ctx.Actor))
 }
 
 // HandleDeleteTask handles DELETE /api/tasks/{id}
@@ -419,7 +548,7 @@ func (r *Router) HandlePlanTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	id := req.PathValue("id")
-	writeResult(w, task.Plan(ctx.ProjectPath, id))
+	writeResult(w, task.Plan(ctx.ProjectPath, id, ctx.Actor))
 }
 
 // HandleRunTask handles POST /api/tasks/{id}/run
@@ -430,7 +559,71 @@ func (r *Router) HandleRunTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	id := req.PathValue("id")
-	writeResult(w, task.Run(ctx.ProjectPath, id))
+	writeResult(w, task.Run(ctx.ProjectPath, id, ctx.Actor))
+}
+
+// HandleReplanTask handles POST /api/tasks/{id}/replan
+func (r *Router) HandleReplanTask(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	id := req.PathValue("id")
+	writeResult(w, task.Replan(ctx.ProjectPath, id, ctx.Actor))
+}
+
+// HandleMoveTask handles POST /api/tasks/{id}/move
+// Body: {"parent_id": 3} (null or omitted = top level)
+func (r *Router) HandleMoveTask(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	id := req.PathValue("id")
+	var body struct {
+		ParentID *int `json:"parent_id"`
+	}
+	if err := decodeBody(req, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	writeResult(w, task.Move(ctx.ProjectPath, id, body.ParentID, ctx.Actor))
+}
+
+// HandleApproveTask handles POST /api/tasks/{id}/approve
+func (r *Router) HandleApproveTask(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	id := req.PathValue("id")
+	writeResult(w, task.Approve(ctx.ProjectPath, id, ctx.Actor))
+}
+
+// HandleRejectTask handles POST /api/tasks/{id}/reject
+// Body: {"comment": "..."} (required, carried into the re-plan)
+func (r *Router) HandleRejectTask(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	id := req.PathValue("id")
+	var body struct {
+		Comment string `json:"comment"`
+	}
+	if err := decodeBody(req, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(body.Comment) == "" {
+		writeError(w, http.StatusBadRequest, "comment required")
+		return
+	}
+	writeResult(w, task.Reject(ctx.ProjectPath, id, body.Comment, ctx.Actor))
 }
 
 // HandlePlanAllTasks handles POST /api/tasks/plan-all
@@ -485,12 +678,117 @@ func (r *Router) HandleCycleTasks(w http.ResponseWriter, req *http.Request) {
 	writeResult(w, task.Cycle(projectPath))
 }
 
+// HandleCycleSubtree handles POST /api/tasks/{id}/cycle
+// Runs the full cycle on the task and its descendants only.
+func (r *Router) HandleCycleSubtree(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	id := req.PathValue("id")
+	writeResult(w, task.CycleSubtree(ctx.ProjectPath, id))
+}
+
 // HandleStopTask handles POST /api/tasks/stop
+// Stops the selected project's traversal, or every project's
+// (?all=true, or when the selected project has none running).
 func (r *Router) HandleStopTask(w http.ResponseWriter, req *http.Request) {
-	msg, running := task.Stop()
+	ctx := r.getContextFromRequest(req)
+	if !task.IsCycleRunning(ctx.ProjectPath) || req.URL.Query().Get("all") == "true" {
+		msg, running := task.Stop()
+		writeResult(w, types.Result{Success: running, Message: msg})
+		return
+	}
+	msg, running := task.StopProject(ctx.ProjectPath)
 	writeResult(w, types.Result{Success: running, Message: msg})
 }
 
+// HandleCancelTask handles POST /api/tasks/{id}/cancel
+// Cancels one running task without stopping the traversal.
+func (r *Router) HandleCancelTask(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	msg, ok := task.CancelTask(ctx.ProjectPath, req.PathValue("id"))
+	writeResult(w, types.Result{Success: ok, Message: msg})
+}
+
+// --- Traversal handlers ---
+
+// HandleListTraversals handles GET /api/traversals
+func (r *Router) HandleListTraversals(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	page, pageSize := r.parsePage(req)
+	writeResult(w, task.ListTraversals(ctx.ProjectPath, pagination.NewPageRequest(page, pageSize)))
+}
+
+// HandleGetTraversal handles GET /api/traversals/{id}
+// Returns the traversal with one item per task it processed.
+func (r *Router) HandleGetTraversal(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	writeResult(w, task.GetTraversal(ctx.ProjectPath, req.PathValue("id")))
+}
+
+// --- Saved query handlers ---
+
+// HandleListQueries handles GET /api/queries
+func (r *Router) HandleListQueries(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	writeResult(w, task.ListQueries(ctx.ProjectPath))
+}
+
+// HandleSaveQuery handles PUT /api/queries/{name}
+// Body uses the same fields as the GET /api/tasks filter parameters.
+func (r *Router) HandleSaveQuery(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	var body struct {
+		Label  string `json:"label"`
+		Status string `json:"status"`
+		Depth  string `json:"depth"`
+		Root   string `json:"root"`
+		Text   string `json:"text"`
+	}
+	if err := decodeBody(req, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	filter, err := task.NewListFilter(body.Label, body.Status, body.Depth, body.Root, body.Text)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeResult(w, task.SaveQuery(ctx.ProjectPath, req.PathValue("name"), filter))
+}
+
+// HandleDeleteQuery handles DELETE /api/queries/{name}
+func (r *Router) HandleDeleteQuery(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	writeResult(w, task.DeleteQuery(ctx.ProjectPath, req.PathValue("name")))
+}
+
 // --- Message handlers ---
 
 // HandleListMessages handles GET /api/messages
@@ -854,6 +1152,196 @@ type UsageResponse struct {
 	UpdatedAt string `json:"updated_at,omitempty"` // When live data was last fetched
 }
 
+// HandleGetClaudeQueue handles GET /api/claude/queue
+func (r *Router) HandleGetClaudeQueue(w http.ResponseWriter, req *http.Request) {
+	writeResult(w, r.handleQueue("list", nil))
+}
+
+// HandleCancelClaudeQueue handles DELETE /api/claude/queue/{id}
+func (r *Router) HandleCancelClaudeQueue(w http.ResponseWriter, req *http.Request) {
+	writeResult(w, r.handleQueue("cancel", []string{req.PathValue("id")}))
+}
+
+// HandleGetRunLog handles GET /api/runs/{id}/log
+// Serves the ANSI-stripped transcript of a Claude run (?raw=true: the raw terminal output).
+// Range requests are supported, so clients can page through or follow large logs.
+func (r *Router) HandleGetRunLog(w http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+	if !claude.ValidRunID(id) {
+		writeError(w, http.StatusBadRequest, "invalid run id")
+		return
+	}
+	base := r.runProjectPath(req, id)
+	if base == "" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("실행 로그를 찾을 수 없습니다: %s", id))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if req.URL.Query().Get("raw") == "true" {
+		f, err := os.Open(claude.TranscriptPath(base, id, true))
+		if err != nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("실행 로그를 찾을 수 없습니다: %s", id))
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		http.ServeContent(w, req, id+".raw.log", info.ModTime(), f)
+		return
+	}
+
+	data, modTime, err := claude.ReadTranscript(base, id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("실행 로그를 찾을 수 없습니다: %s", id))
+		return
+	}
+	http.ServeContent(w, req, id+".log", modTime, bytes.NewReader(data))
+}
+
+// liveKeepalive is how often an idle output stream sends a comment line
+var liveKeepalive = 15 * time.Second
+
+// HandleGetLiveRuns handles GET /api/runs/live
+func (r *Router) HandleGetLiveRuns(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, types.Result{Success: true, Data: claude.GetLiveRuns()})
+}
+
+// HandleStreamRun handles GET /api/runs/{id}/stream
+func (r *Router) HandleStreamRun(w http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+	if !claude.ValidRunID(id) {
+		writeError(w, http.StatusBadRequest, "invalid run id")
+		return
+	}
+	serveLiveStream(w, req, claude.GetLive(id))
+}
+
+// HandleStreamTask handles GET /api/tasks/{id}/stream
+func (r *Router) HandleStreamTask(w http.ResponseWriter, req *http.Request) {
+	ctx := r.getContextFromRequest(req)
+	if ctx.ProjectPath == "" {
+		writeError(w, http.StatusBadRequest, "프로젝트를 먼저 선택하세요")
+		return
+	}
+	id, err := strconv.Atoi(req.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid task id")
+		return
+	}
+	serveLiveStream(w, req, claude.GetLiveForTask(ctx.ProjectPath, id))
+}
+
+// HandleStreamMessage handles GET /api/messages/{id}/stream
+func (r *Router) HandleStreamMessage(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid message id")
+		return
+	}
+	serveLiveStream(w, req, claude.GetLiveForMessage(id))
+}
+
+// serveLiveStream sends the output of a live run as server-sent events:
+// "output" events ({"offset", "data"}) until the run ends, then one "done"
+// event with the run info. ?offset= resumes after a reconnect (default: the
+// whole buffered output), ?raw=true sends the output with ANSI sequences.
+func serveLiveStream(w http.ResponseWriter, req *http.Request, live *claude.LiveOutput) {
+	if live == nil {
+		writeError(w, http.StatusNotFound, "실행 중인 출력이 없습니다")
+		return
+	}
+	var offset int64
+	if v := req.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid offset")
+			return
+		}
+		offset = n
+	}
+	raw := req.URL.Query().Get("raw") == "true"
+
+	// A run can outlive the server write timeout
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event string, v interface{}) bool {
+		data, _ := json.Marshal(v)
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	for {
+		var chunk string
+		var next int64
+		var done bool
+		if raw {
+			var data []byte
+			data, next, done = live.Since(offset)
+			chunk = string(data)
+		} else {
+			chunk, next, done = live.Text(offset)
+		}
+		if next > offset || chunk != "" {
+			if !send("output", map[string]interface{}{"offset": next, "data": chunk}) {
+				return
+			}
+			offset = next
+		}
+		if done {
+			send("done", live.Info())
+			return
+		}
+
+		waitCtx, cancel := context.WithTimeout(req.Context(), liveKeepalive)
+		woke := live.Wait(waitCtx, offset)
+		cancel()
+		if req.Context().Err() != nil {
+			return
+		}
+		if !woke {
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil || rc.Flush() != nil {
+				return
+			}
+		}
+	}
+}
+
+// runProjectPath finds the project holding a run transcript: the request's
+// project first, then every registered project and the default project path
+func (r *Router) runProjectPath(req *http.Request, runID string) string {
+	candidates := []string{r.getContextFromRequest(req).ProjectPath}
+	if projects, err := project.ListAll(); err == nil {
+		for _, p := range projects {
+			candidates = append(candidates, p.Path)
+		}
+	}
+	candidates = append(candidates, project.DefaultPath)
+
+	for _, base := range candidates {
+		if base == "" {
+			continue
+		}
+		for _, raw := range []bool{false, true} {
+			if _, err := os.Stat(claude.TranscriptPath(base, runID, raw)); err == nil {
+				return base
+			}
+		}
+	}
+	return ""
+}
+
 // HandleGetUsage handles GET /api/usage
 func (r *Router) HandleGetUsage(w http.ResponseWriter, req *http.Request) {
 	// Get stats from stats-cache.json
@@ -895,6 +1383,7 @@ type CycleStatusJSON struct {
 	Status        string `json:"status"`
 	Type          string `json:"type,omitempty"`
 	ProjectID     string `json:"project_id,omitempty"`
+	RootID        int    `json:"root_id,omitempty"`
 	StartedAt     string `json:"started_at,omitempty"`
 	CurrentTaskID int    `json:"current_task_id,omitempty"`
 	ActiveWorkers int    `json:"active_workers,omitempty"`
@@ -921,6 +1410,7 @@ func (r *Router) HandleStatus(w http.ResponseWriter, req *http.Request) {
 			csJSON.StartedAt = cycleInfo.StartedAt.Format(time.RFC3339)
 			csJSON.ElapsedSec = int(time.Since(cycleInfo.StartedAt).Seconds())
 		}
+		csJSON.RootID = cycleInfo.RootID
 		csJSON.CurrentTaskID = cycleInfo.CurrentTaskID
 		csJSON.ActiveWorkers = cycleInfo.ActiveWorkers
 		csJSON.Phase = cycleInfo.Phase
@@ -936,6 +1426,7 @@ func (r *Router) HandleStatus(w http.ResponseWriter, req *http.Request) {
 			Status:        cs.Status,
 			Type:          cs.Type,
 			ProjectID:     cs.ProjectID,
+			RootID:        cs.RootID,
 			CurrentTaskID: cs.CurrentTaskID,
 			ActiveWorkers: cs.ActiveWorkers,
 			Phase:         cs.Phase,
@@ -1489,6 +1980,11 @@ func (r *Router) RegisterRESTfulRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/status", r.HandleStatus)
 	mux.HandleFunc("GET /api/usage", r.HandleGetUsage)
 	mux.HandleFunc("POST /api/usage/refresh", r.HandleRefreshUsage)
+	mux.HandleFunc("GET /api/claude/queue", r.HandleGetClaudeQueue)
+	mux.HandleFunc("DELETE /api/claude/queue/{id}", r.HandleCancelClaudeQueue)
+	mux.HandleFunc("GET /api/runs/live", r.HandleGetLiveRuns)
+	mux.HandleFunc("GET /api/runs/{id}/log", r.HandleGetRunLog)
+	mux.HandleFunc("GET /api/runs/{id}/stream", r.HandleStreamRun)
 
 	// Projects - specific routes before parameterized
 	mux.HandleFunc("GET /api/projects/stats", r.HandleProjectsStats)
@@ -1504,13 +2000,34 @@ func (r *Router) RegisterRESTfulRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/tasks/run-all", r.HandleRunAllTasks)
 	mux.HandleFunc("POST /api/tasks/cycle", r.HandleCycleTasks)
 	mux.HandleFunc("POST /api/tasks/stop", r.HandleStopTask)
+	mux.HandleFunc("GET /api/tasks/graph", r.HandleGetGraph)
+	mux.HandleFunc("POST /api/tasks/graph", r.HandleBuildGraph)
 	mux.HandleFunc("GET /api/tasks", r.HandleListTasks)
 	mux.HandleFunc("POST /api/tasks", r.HandleAddTask)
+	mux.HandleFunc("GET /api/tasks/search", r.HandleSearchTasks)
 	mux.HandleFunc("GET /api/tasks/{id}", r.HandleGetTask)
+	mux.HandleFunc("GET /api/tasks/{id}/diff", r.HandleGetTaskDiff)
+	mux.HandleFunc("GET /api/tasks/{id}/events", r.HandleGetTaskEvents)
+	mux.HandleFunc("GET /api/tasks/{id}/stream", r.HandleStreamTask)
 	mux.HandleFunc("PATCH /api/tasks/{id}", r.HandleUpdateTask)
 	mux.HandleFunc("DELETE /api/tasks/{id}", r.HandleDeleteTask)
 	mux.HandleFunc("POST /api/tasks/{id}/plan", r.HandlePlanTask)
 	mux.HandleFunc("POST /api/tasks/{id}/run", r.HandleRunTask)
+	mux.HandleFunc("POST /api/tasks/{id}/cycle", r.HandleCycleSubtree)
+	mux.HandleFunc("POST /api/tasks/{id}/move", r.HandleMoveTask)
+	mux.HandleFunc("POST /api/tasks/{id}/cancel", r.HandleCancelTask)
+	mux.HandleFunc("POST /api/tasks/{id}/replan", r.HandleReplanTask)
+	mux.HandleFunc("POST /api/tasks/{id}/approve", r.HandleApproveTask)
+	mux.HandleFunc("POST /api/tasks/{id}/reject", r.HandleRejectTask)
+
+	// Traversals
+	mux.HandleFunc("GET /api/traversals", r.HandleListTraversals)
+	mux.HandleFunc("GET /api/traversals/{id}", r.HandleGetTraversal)
+
+	// Saved queries
+	mux.HandleFunc("GET /api/queries", r.HandleListQueries)
+	mux.HandleFunc("PUT /api/queries/{name}", r.HandleSaveQuery)
+	mux.HandleFunc("DELETE /api/queries/{name}", r.HandleDeleteQuery)
 
 	// Messages - specific routes before parameterized
 	mux.HandleFunc("GET /api/messages/status", r.HandleMessageStatus)
@@ -1518,6 +2035,7 @@ func (r *Router) RegisterRESTfulRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/messages", r.HandleListMessages)
 	mux.HandleFunc("POST /api/messages", r.HandleSendMessage)
 	mux.HandleFunc("GET /api/messages/{id}", r.HandleGetMessage)
+	mux.HandleFunc("GET /api/messages/{id}/stream", r.HandleStreamMessage)
 
 	// Configs
 	mux.HandleFunc("GET /api/configs", r.HandleListConfigs)
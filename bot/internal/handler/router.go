@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,6 +27,8 @@ type Context struct {
 	ProjectID          string
 	ProjectPath        string
 	ProjectDescription string
+	Actor              string          // who sent the command, recorded on task status changes (default: cli)
+	Request            context.Context // lifetime of the HTTP request (nil = not bound to one, e.g. Telegram)
 }
 
 // Router handles command routing
@@ -150,6 +153,9 @@ func (r *Router) Execute(ctx *Context, input string) types.Result {
 	if len(parts) == 0 {
 		return types.Result{Success: false, Message: "empty command"}
 	}
+	if ctx.Actor == "" {
+		ctx.Actor = task.ActorCLI
+	}
 
 	category := parts[0]
 	var cmd string
@@ -183,6 +189,8 @@ func (r *Router) Execute(ctx *Context, input string) types.Result {
 		return r.handleStatus(ctx)
 	case "usage":
 		return r.handleUsage()
+	case "queue":
+		return r.handleQueue(cmd, args)
 	default:
 		return r.handleClaude(ctx, input)
 	}
@@ -194,6 +202,9 @@ func (r *Router) handleClaude(ctx *Context, input string) types.Result {
 		UserPrompt:   input,
 		SystemPrompt: "",
 		WorkDir:      ctx.ProjectPath,
+		Agent:        task.ProjectAgent(ctx.ProjectPath),
+		Priority:     claude.PriorityInteractive,
+		Origin:       claude.Origin{Project: ctx.ProjectPath},
 	}
 
 	result, err := claude.Run(opts)
@@ -376,8 +387,14 @@ func (r *Router) handleTask(ctx *Context, cmd string, args []string) types.Resul
 	}
 
 	// stop doesn't require project selection
+	// task stop [--all]: the selected project's traversal, or every project
+	// (--all, or when the selected project has none running)
 	if cmd == "stop" {
-		msg, running := task.Stop()
+		if !task.IsCycleRunning(ctx.ProjectPath) || (len(args) > 0 && args[0] == "--all") {
+			msg, running := task.Stop()
+			return types.Result{Success: running, Message: msg}
+		}
+		msg, running := task.StopProject(ctx.ProjectPath)
 		return types.Result{Success: running, Message: msg}
 	}
 
@@ -396,8 +413,9 @@ func (r *Router) handleTask(ctx *Context, cmd string, args []string) types.Resul
 				Context:    "task add",
 			}
 		}
-		// Parse --parent, --spec-file options
+		// Parse --parent, --depends, --spec-file options
 		var parentID *int
+		var dependsOn []int
 		var specFile string
 		var specParts []string
 		for i := 0; i < len(args); i++ {
@@ -408,6 +426,13 @@ func (r *Router) handleTask(ctx *Context, cmd string, args []string) types.Resul
 				}
 				parentID = &pid
 				i++ // skip next arg
+			} else if args[i] == "--depends" && i+1 < len(args) {
+				ids, err := task.ParseDependsOn(args[i+1])
+				if err != nil {
+					return types.Result{Success: false, Message: fmt.Sprintf("잘못된 depends 값: %v", err)}
+				}
+				dependsOn = ids
+				i++ // skip next arg
 			} else if args[i] == "--spec-file" && i+1 < len(args) {
 				specFile = args[i+1]
 				i++ // skip next arg
@@ -437,9 +462,18 @@ func (r *Router) handleTask(ctx *Context, cmd string, args []string) types.Resul
 				Context:    "task add",
 			}
 		}
-		return task.Add(ctx.ProjectPath, "", parentID, spec)
+		return task.AddWithDeps(ctx.ProjectPath, "", parentID, spec, dependsOn)
 	case "list":
 		// task list [parent_id] [-p page] [-n pageSize] [--tree]
+		// task list [--label l] [--status s] [--depth n] [--root id] [--text t] - filtered, whole tree
+		filter, args, err := task.ParseListFilter(args)
+		if err != nil {
+			return types.Result{Success: false, Message: fmt.Sprintf("필터 오류: %v", err)}
+		}
+		if !filter.IsEmpty() {
+			page, pageSize := r.parsePagination(args)
+			return task.ListFiltered(ctx.ProjectPath, filter, "task list "+filter.String(), pagination.NewPageRequest(page, pageSize))
+		}
 		// Check for --tree flag
 		for _, arg := range args {
 			if arg == "--tree" {
@@ -478,12 +512,104 @@ func (r *Router) handleTask(ctx *Context, cmd string, args []string) types.Resul
 			return task.List(ctx.ProjectPath, nil, pagination.NewPageRequest(1, r.pageSize)) // show list if no id
 		}
 		return task.Get(ctx.ProjectPath, args[0])
+	case "diff":
+		// task diff <id> - code change recorded by the last run
+		if len(args) < 1 {
+			return types.Result{Success: false, Message: "usage: task diff <id>"}
+		}
+		return task.GetDiff(ctx.ProjectPath, args[0])
+	case "events":
+		// task events <id> - status timeline (who changed the status, and when)
+		if len(args) < 1 {
+			return types.Result{Success: false, Message: "usage: task events <id>"}
+		}
+		return task.ListEvents(ctx.ProjectPath, args[0])
+	case "tail":
+		// task tail <id> [offset] - output of the task's Claude run; with an offset,
+		// waits for output past it (call again with the returned offset to follow).
+		// Only HTTP requests wait: other callers (Telegram) get the output right away.
+		if len(args) < 1 {
+			return types.Result{Success: false, Message: "usage: task tail <id> [offset]"}
+		}
+		offset := ""
+		if len(args) > 1 {
+			offset = args[1]
+		}
+		if ctx.Request == nil {
+			return task.Tail(context.Background(), ctx.ProjectPath, args[0], offset, false)
+		}
+		return task.Tail(ctx.Request, ctx.ProjectPath, args[0], offset, true)
 	case "set":
 		if len(args) < 3 {
 			return types.Result{Success: false, Message: "usage: task set <id> <field> <value>"}
 		}
 		value := strings.Join(args[2:], " ")
-		return task.Set(ctx.ProjectPath, args[0], args[1], value)
+		return task.Set(ctx.ProjectPath, args[0], args[1], value, ctx.Actor)
+	case "move":
+		// task move <id> <parent-id|root> - reparent a task with its subtree
+		if len(args) < 2 {
+			return types.Result{Success: false, Message: "usage: task move <id> <parent-id|root>"}
+		}
+		parent, err := task.ParseParent(args[1])
+		if err != nil {
+			return types.Result{Success: false, Message: err.Error()}
+		}
+		return task.Move(ctx.ProjectPath, args[0], parent, ctx.Actor)
+	case "cancel":
+		// task cancel <id> - cancel one running task, the traversal goes on
+		if len(args) < 1 {
+			return types.Result{Success: false, Message: "usage: task cancel <id>"}
+		}
+		msg, ok := task.CancelTask(ctx.ProjectPath, args[0])
+		return types.Result{Success: ok, Message: msg}
+	case "search":
+		// task search <query> [-p page] [-n pageSize] - full-text search of task files
+		var words []string
+		for i := 0; i < len(args); i++ {
+			if args[i] == "-p" || args[i] == "-n" {
+				i++ // skip next value
+				continue
+			}
+			words = append(words, args[i])
+		}
+		if len(words) == 0 {
+			return types.Result{Success: false, Message: "usage: task search <query>"}
+		}
+		page, pageSize := r.parsePagination(args)
+		return task.Search(ctx.ProjectPath, strings.Join(words, " "), pagination.NewPageRequest(page, pageSize))
+	case "query":
+		// task query save <name> <filters> | task query list | task query delete <name> | task query <name> [-p page]
+		if len(args) == 0 || args[0] == "list" {
+			return task.ListQueries(ctx.ProjectPath)
+		}
+		switch args[0] {
+		case "save":
+			if len(args) < 3 {
+				return types.Result{Success: false, Message: "usage: task query save <name> [--label l] [--status s] [--depth n] [--root id] [--text t]"}
+			}
+			filter, rest, err := task.ParseListFilter(args[2:])
+			if err != nil {
+				return types.Result{Success: false, Message: fmt.Sprintf("필터 오류: %v", err)}
+			}
+			if len(rest) > 0 {
+				return types.Result{Success: false, Message: fmt.Sprintf("알 수 없는 인자: %s", strings.Join(rest, " "))}
+			}
+			return task.SaveQuery(ctx.ProjectPath, args[1], filter)
+		case "delete":
+			if len(args) < 2 {
+				return types.Result{Success: false, Message: "usage: task query delete <name>"}
+			}
+			return task.DeleteQuery(ctx.ProjectPath, args[1])
+		}
+		page, pageSize := r.parsePagination(args[1:])
+		return task.RunQuery(ctx.ProjectPath, args[0], pagination.NewPageRequest(page, pageSize))
+	case "history":
+		// task history [traversal-id] [-p page] [-n pageSize]
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			return task.GetTraversal(ctx.ProjectPath, args[0])
+		}
+		page, pageSize := r.parsePagination(args)
+		return task.ListTraversals(ctx.ProjectPath, pagination.NewPageRequest(page, pageSize))
 	case "delete":
 		if len(args) < 1 {
 			return types.Result{Success: false, Message: "usage: task delete <id>"}
@@ -494,28 +620,77 @@ func (r *Router) handleTask(ctx *Context, cmd string, args []string) types.Resul
 		}
 		return task.Delete(ctx.ProjectPath, args[0], confirmed)
 	case "plan":
-		// task plan [id] [--all]
+		// task plan [id] [--all [root-id]]
 		if len(args) > 0 && args[0] == "--all" {
+			if len(args) > 1 {
+				return task.PlanSubtree(ctx.ProjectPath, args[1])
+			}
 			return task.PlanAll(ctx.ProjectPath)
 		}
 		var id string
 		if len(args) > 0 {
 			id = args[0]
 		}
-		return task.Plan(ctx.ProjectPath, id)
+		return task.Plan(ctx.ProjectPath, id, ctx.Actor)
 	case "run":
-		// task run [id] [--all]
+		// task run [id] [--all [root-id]]
 		if len(args) > 0 && args[0] == "--all" {
+			if len(args) > 1 {
+				return task.RunSubtree(ctx.ProjectPath, args[1])
+			}
 			return task.RunAll(ctx.ProjectPath)
 		}
 		var id string
 		if len(args) > 0 {
 			id = args[0]
 		}
-		return task.Run(ctx.ProjectPath, id)
+		return task.Run(ctx.ProjectPath, id, ctx.Actor)
 	case "cycle":
-		// task cycle - 1회차 + 2회차 자동 실행
+		// task cycle [root-id] - 1회차 + 2회차 자동 실행 (root-id: 해당 작업 하위만)
+		if len(args) > 0 {
+			return task.CycleSubtree(ctx.ProjectPath, args[0])
+		}
 		return task.Cycle(ctx.ProjectPath)
+	case "replan":
+		// task replan <id> - re-plan a failed task with its error report
+		if len(args) < 1 {
+			return types.Result{Success: false, Message: "usage: task replan <id>"}
+		}
+		return task.Replan(ctx.ProjectPath, args[0], ctx.Actor)
+	case "approve":
+		// task approve <id> - review → planned
+		if len(args) < 1 {
+			return types.Result{Success: false, Message: "usage: task approve <id>"}
+		}
+		return task.Approve(ctx.ProjectPath, args[0], ctx.Actor)
+	case "reject":
+		// task reject <id> <comment> - review → todo, comment carried into the re-plan
+		if len(args) < 1 {
+			return types.Result{Success: false, Message: "usage: task reject <id> <comment>"}
+		}
+		if len(args) < 2 {
+			return types.Result{
+				Success:    true,
+				Message:    fmt.Sprintf("작업 #%s의 반려 사유를 입력하세요:", args[0]),
+				NeedsInput: true,
+				Prompt:     "Comment: ",
+				Context:    fmt.Sprintf("task reject %s", args[0]),
+			}
+		}
+		return task.Reject(ctx.ProjectPath, args[0], strings.Join(args[1:], " "), ctx.Actor)
+	case "graph":
+		// task graph [build <file>]
+		if len(args) == 0 {
+			return task.ListGraph(ctx.ProjectPath)
+		}
+		if args[0] != "build" || len(args) < 2 {
+			return types.Result{Success: false, Message: "usage: task graph [build <file>]"}
+		}
+		spec, err := task.LoadGraphSpec(args[1])
+		if err != nil {
+			return types.Result{Success: false, Message: err.Error()}
+		}
+		return task.BuildGraph(ctx.ProjectPath, spec)
 	case "migrate":
 		count, err := task.MigrateContentToFiles(ctx.ProjectPath)
 		if err != nil {
@@ -542,7 +717,11 @@ func (r *Router) taskStatsHeader(projectPath string) string {
 	if err != nil || stats.Leaf == 0 {
 		return ""
 	}
-	return fmt.Sprintf("📊 전체: %d | 📝todo: %d | 📋planned: %d | ✅done: %d | ❌failed: %d\n\n", stats.Leaf, stats.Todo, stats.Planned, stats.Done, stats.Failed)
+	header := fmt.Sprintf("📊 전체: %d | 📝todo: %d | 📋planned: %d", stats.Leaf, stats.Todo, stats.Planned)
+	if stats.Review > 0 {
+		header += fmt.Sprintf(" | 🔍review: %d", stats.Review)
+	}
+	return header + fmt.Sprintf(" | ✅done: %d | ❌failed: %d\n\n", stats.Done, stats.Failed)
 }
 
 func (r *Router) handleMessage(ctx *Context, cmd string, args []string) types.Result {
@@ -613,6 +792,9 @@ func (r *Router) handleStatus(ctx *Context) types.Result {
 	if claudeStatus.Available == 0 {
 		sb.WriteString(" (대기열 가득)")
 	}
+	if claudeStatus.Queued > 0 {
+		sb.WriteString(fmt.Sprintf(" · 대기 %d [대기열:queue]", claudeStatus.Queued))
+	}
 	sb.WriteString("\n")
 
 	// Cycle status - show all running cycles
@@ -624,6 +806,9 @@ func (r *Router) handleStatus(ctx *Context) types.Result {
 			if typeLabel == "" {
 				typeLabel = cycleStatus.Type
 			}
+			if cycleStatus.RootID > 0 {
+				typeLabel = fmt.Sprintf("#%d 하위 %s", cycleStatus.RootID, typeLabel)
+			}
 			// Phase label
 			phaseLabel := ""
 			if cycleStatus.Phase == "plan" {
@@ -716,6 +901,43 @@ func (r *Router) handleUsage() types.Result {
 	}
 }
 
+// handleQueue lists the Claude queue ("queue") or cancels a waiting entry ("queue cancel <id>")
+func (r *Router) handleQueue(cmd string, args []string) types.Result {
+	switch cmd {
+	case "", "list":
+		q := claude.GetQueue()
+		if len(q.Running) == 0 && len(q.Waiting) == 0 {
+			return types.Result{Success: true, Message: fmt.Sprintf("Claude 대기열이 비어 있습니다 (0/%d)", q.Max), Data: q}
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("📋 Claude 대기열 (실행 %d/%d, 대기 %d)\n", len(q.Running), q.Max, len(q.Waiting)))
+		for _, e := range q.Running {
+			sb.WriteString(fmt.Sprintf("▶️ #%d %s %s (%s)\n", e.ID, e.Priority, e.Origin, time.Since(*e.StartedAt).Round(time.Second)))
+		}
+		var buttons []string
+		for _, e := range q.Waiting {
+			sb.WriteString(fmt.Sprintf("⏳ %d. #%d %s %s (대기 %s)\n", e.Position, e.ID, e.Priority, e.Origin, time.Since(e.EnqueuedAt).Round(time.Second)))
+			buttons = append(buttons, fmt.Sprintf("[취소 #%d:queue cancel %d]", e.ID, e.ID))
+		}
+		sb.WriteString(strings.Join(buttons, ""))
+		return types.Result{Success: true, Message: strings.TrimRight(sb.String(), "\n"), Data: q}
+	case "cancel":
+		if len(args) < 1 {
+			return types.Result{Success: false, Message: "usage: queue cancel <id>"}
+		}
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return types.Result{Success: false, Message: fmt.Sprintf("잘못된 대기열 ID: %s", args[0])}
+		}
+		if err := claude.CancelQueued(id); err != nil {
+			return types.Result{Success: false, Message: fmt.Sprintf("대기열 취소 실패: %v", err)}
+		}
+		return types.Result{Success: true, Message: fmt.Sprintf("✅ 대기열 #%d 취소됨", id)}
+	default:
+		return types.Result{Success: false, Message: fmt.Sprintf("unknown queue command: %s", cmd)}
+	}
+}
+
 // parsePagination extracts -p (page), -n (pageSize), --all from args
 func (r *Router) parsePagination(args []string) (page, pageSize int) {
 	page = 1
@@ -1,8 +1,16 @@
 package handler
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"parkjunwoo.com/claribot/pkg/claude"
 )
 
 func TestParseArgs(t *testing.T) {
@@ -192,3 +200,94 @@ func TestRouterSetProject(t *testing.T) {
 		t.Errorf("ProjectDescription = %q, want %q", r.ctx.ProjectDescription, "Test Description")
 	}
 }
+
+func TestHandleGetRunLog(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectPath := t.TempDir()
+	r := NewRouter()
+	r.SetProject("test-project", projectPath, "")
+
+	runID := claude.NewRunID()
+	os.MkdirAll(claude.RunDir(projectPath), 0755)
+	os.WriteFile(claude.TranscriptPath(projectPath, runID, false), []byte("0123456789"), 0644)
+	os.WriteFile(claude.TranscriptPath(projectPath, runID, true), []byte("\x1b[1mraw\x1b[0m"), 0644)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/runs/{id}/log", r.HandleGetRunLog)
+
+	get := func(path, rangeHeader string) *http.Response {
+		req := httptest.NewRequest("GET", path, nil)
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	resp := get("/api/runs/"+runID+"/log", "")
+	if body, _ := io.ReadAll(resp.Body); resp.StatusCode != http.StatusOK || string(body) != "0123456789" {
+		t.Errorf("Unexpected log: %d %q", resp.StatusCode, body)
+	}
+
+	resp = get("/api/runs/"+runID+"/log", "bytes=7-")
+	if body, _ := io.ReadAll(resp.Body); resp.StatusCode != http.StatusPartialContent || string(body) != "789" {
+		t.Errorf("Unexpected range response: %d %q", resp.StatusCode, body)
+	}
+
+	resp = get("/api/runs/"+runID+"/log?raw=true", "")
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), "\x1b[1m") {
+		t.Errorf("Expected raw output, got %q", body)
+	}
+
+	if resp = get("/api/runs/20200101-000000-000000/log", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown run, got %d", resp.StatusCode)
+	}
+	if resp = get("/api/runs/bad-id/log", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid run id, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandleStreamMessage(t *testing.T) {
+	claude.RegisterAgent(claude.NewFakeAgent("fake-stream",
+		claude.FakeStep{Output: "\x1b[1mhello\x1b[0m\n", Delay: 50 * time.Millisecond},
+	))
+	defer claude.UnregisterAgent("fake-stream")
+
+	done := make(chan struct{})
+	go func() {
+		claude.Run(claude.Options{Agent: "fake-stream", Origin: claude.Origin{Project: t.TempDir(), MessageID: 42}})
+		close(done)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for claude.GetLiveForMessage(42) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Run was not published")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	r := NewRouter()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/messages/{id}/stream", r.HandleStreamMessage)
+
+	// The stream waits for the output and ends with the run
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/messages/42/stream", nil))
+	<-done
+	body := w.Body.String()
+	if w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Unexpected content type: %s", w.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{"event: output\ndata: {\"data\":\"hello\\n\",\"offset\":", "event: done\ndata: {\"run_id\":"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in stream:\n%s", want, body)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/messages/43/stream", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a live run, got %d", w.Code)
+	}
+}
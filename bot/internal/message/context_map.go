@@ -149,7 +149,7 @@ func buildTaskStats(localDB *db.DB) string {
 	}
 
 	var parts []string
-	for _, s := range []string{"todo", "planned", "split", "done", "failed"} {
+	for _, s := range []string{"todo", "planned", "review", "split", "done", "failed"} {
 		if c, ok := statusCounts[s]; ok && c > 0 {
 			parts = append(parts, fmt.Sprintf("%s:%d", s, c))
 		}
@@ -30,9 +30,9 @@ func Get(projectPath, idStr string) types.Result {
 	var m Message
 	var completedAt *string
 	err = globalDB.QueryRow(`
-		SELECT id, project_id, content, source, status, result, error, created_at, completed_at
+		SELECT id, project_id, content, source, status, result, error, COALESCE(run_id, ''), created_at, completed_at
 		FROM messages WHERE id = ?
-	`, id).Scan(&m.ID, &m.ProjectID, &m.Content, &m.Source, &m.Status, &m.Result, &m.Error, &m.CreatedAt, &completedAt)
+	`, id).Scan(&m.ID, &m.ProjectID, &m.Content, &m.Source, &m.Status, &m.Result, &m.Error, &m.RunID, &m.CreatedAt, &completedAt)
 	if err != nil {
 		return types.Result{
 			Success: false,
@@ -50,6 +50,9 @@ func Get(projectPath, idStr string) types.Result {
 	if m.Error != "" {
 		msg += fmt.Sprintf("\n\n오류:\n%s", m.Error)
 	}
+	if m.RunID != "" {
+		msg += fmt.Sprintf("\n\n실행 로그: run %s", m.RunID)
+	}
 
 	return types.Result{
 		Success: true,
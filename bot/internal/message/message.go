@@ -9,6 +9,7 @@ type Message struct {
 	Status      string  `json:"status"`
 	Result      string  `json:"result"`
 	Error       string  `json:"error,omitempty"`
+	RunID       string  `json:"run_id,omitempty"` // Claude run transcript (GET /api/runs/{id}/log)
 	CreatedAt   string  `json:"created_at"`
 	CompletedAt *string `json:"completed_at,omitempty"`
 }
@@ -12,6 +12,7 @@ import (
 	"parkjunwoo.com/claribot/internal/config"
 	"parkjunwoo.com/claribot/internal/db"
 	"parkjunwoo.com/claribot/internal/prompts"
+	"parkjunwoo.com/claribot/internal/task"
 	"parkjunwoo.com/claribot/internal/terminal"
 	"parkjunwoo.com/claribot/internal/types"
 	"parkjunwoo.com/claribot/pkg/claude"
@@ -54,8 +55,9 @@ func SendWithProject(projectID *string, projectPath, content, source string) typ
 		}
 	}
 
-	// Update status to processing
-	_, err = globalDB.Exec(`UPDATE messages SET status = 'processing' WHERE id = ?`, msgID)
+	// Update status to processing (linking the run transcript up front, so failed runs keep it too)
+	runID := claude.NewRunID()
+	_, err = globalDB.Exec(`UPDATE messages SET status = 'processing', run_id = ? WHERE id = ?`, runID, msgID)
 	if err != nil {
 		return types.Result{
 			Success: false,
@@ -96,6 +98,10 @@ func SendWithProject(projectID *string, projectPath, content, source string) typ
 		SystemPrompt: systemPrompt,
 		WorkDir:      projectPath,
 		ReportPath:   reportPath,
+		Agent:        task.ProjectAgent(projectPath),
+		Priority:     claude.PriorityInteractive,
+		Origin:       claude.Origin{Project: projectPath, MessageID: msgID},
+		RunID:        runID,
 	}
 
 	claudeResult, err := claude.Run(opts)
@@ -148,6 +154,7 @@ func SendWithProject(projectID *string, projectPath, content, source string) typ
 			Source:      source,
 			Status:      "done",
 			Result:      claudeResult.Output,
+			RunID:       claudeResult.RunID,
 			CreatedAt:   now,
 			CompletedAt: &completedAt,
 		},
@@ -0,0 +1,103 @@
+package message
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+// setupProject creates a global DB in a temporary HOME and a project whose
+// local config selects a scripted fake agent.
+func setupProject(t *testing.T, steps ...claude.FakeStep) (string, *claude.FakeAgent) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	globalDB, err := db.OpenGlobal()
+	if err != nil {
+		t.Fatalf("OpenGlobal failed: %v", err)
+	}
+	defer globalDB.Close()
+	if err := globalDB.MigrateGlobal(); err != nil {
+		t.Fatalf("MigrateGlobal failed: %v", err)
+	}
+
+	projectPath := t.TempDir()
+	fake := claude.NewFakeAgent("fake-"+t.Name(), steps...)
+	claude.RegisterAgent(fake)
+	t.Cleanup(func() { claude.UnregisterAgent(fake.Name()) })
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+	localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES ('agent', ?, ?)", fake.Name(), db.TimeNow())
+
+	return projectPath, fake
+}
+
+func TestSend(t *testing.T) {
+	projectPath, fake := setupProject(t,
+		claude.FakeStep{Output: "읽는 중\n", Report: "## 요약\nREADME 정리 완료"},
+		claude.FakeStep{Report: "## 요약\n오타 수정 완료"},
+	)
+
+	r := Send(projectPath, "README 정리해줘", "cli")
+	if !r.Success || r.Message != "## 요약\nREADME 정리 완료" {
+		t.Fatalf("Send failed: %s", r.Message)
+	}
+	msg := r.Data.(*Message)
+	if msg.Status != "done" || msg.Source != "cli" || msg.RunID == "" {
+		t.Errorf("Unexpected message: %+v", msg)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 agent call, got %d", len(calls))
+	}
+	opts := calls[0]
+	if opts.UserPrompt != "README 정리해줘" || opts.WorkDir != projectPath || opts.Priority != claude.PriorityInteractive || opts.RunID != msg.RunID {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	if !strings.Contains(opts.SystemPrompt, opts.ReportPath) {
+		t.Error("Expected report path in the system prompt")
+	}
+	if _, err := os.Stat(opts.ReportPath); !os.IsNotExist(err) {
+		t.Errorf("Expected report removed: %s", opts.ReportPath)
+	}
+
+	// The stored message and its report are part of the next context map
+	got := Get(projectPath, "1")
+	if !got.Success || got.Data.(*Message).Result != "## 요약\nREADME 정리 완료" {
+		t.Errorf("Unexpected stored message: %+v", got.Data)
+	}
+	if r := Send(projectPath, "오타도 고쳐줘", "cli"); !r.Success {
+		t.Fatalf("Second send failed: %s", r.Message)
+	}
+	if prompt := fake.Calls()[1].SystemPrompt; !strings.Contains(prompt, "README 정리해줘") || !strings.Contains(prompt, "README 정리 완료") {
+		t.Errorf("Expected previous message in the context map:\n%s", prompt)
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	projectPath, _ := setupProject(t, claude.FakeStep{Err: errors.New("agent crashed")})
+
+	r := Send(projectPath, "빌드해줘", "telegram")
+	if r.Success || !strings.Contains(r.Message, "agent crashed") {
+		t.Fatalf("Expected failure, got %s", r.Message)
+	}
+
+	globalDB, err := db.OpenGlobal()
+	if err != nil {
+		t.Fatalf("OpenGlobal failed: %v", err)
+	}
+	defer globalDB.Close()
+	var status, errText string
+	var runID *string
+	globalDB.QueryRow(`SELECT status, COALESCE(error, ''), run_id FROM messages WHERE id = 1`).Scan(&status, &errText, &runID)
+	if status != "failed" || !strings.Contains(errText, "agent crashed") || runID == nil {
+		t.Errorf("Unexpected stored message: status=%s error=%s run_id=%v", status, errText, runID)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"parkjunwoo.com/claribot/internal/config"
 	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/task"
 	"parkjunwoo.com/claribot/internal/types"
 )
 
@@ -137,6 +138,11 @@ func Add(path, description string) types.Result {
 		log.Printf("[project] GitHub 연결 실패 (무시): %v", err)
 	}
 
+	// Keep the DB in sync with edits to task files (best-effort)
+	if err := task.Watch(absPath); err != nil {
+		log.Printf("[project] 작업 파일 감시 실패 (무시): %v", err)
+	}
+
 	return types.Result{
 		Success: true,
 		Message: fmt.Sprintf("프로젝트 추가됨: %s\nPath: %s\n%s\n[전환:project switch %s][삭제:project delete %s]", id, absPath, description, id, id),
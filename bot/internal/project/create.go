@@ -11,6 +11,7 @@ import (
 
 	"parkjunwoo.com/claribot/internal/config"
 	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/task"
 	"parkjunwoo.com/claribot/internal/types"
 )
 
@@ -113,6 +114,11 @@ func Create(id, description string) types.Result {
 		log.Printf("[project] GitHub 연결 실패 (무시): %v", err)
 	}
 
+	// Keep the DB in sync with edits to task files (best-effort)
+	if err := task.Watch(projectPath); err != nil {
+		log.Printf("[project] 작업 파일 감시 실패 (무시): %v", err)
+	}
+
 	return types.Result{
 		Success: true,
 		Message: fmt.Sprintf("프로젝트 생성됨: %s\n%s\n[삭제:project delete %s]", id, description, id),
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/task"
 	"parkjunwoo.com/claribot/internal/types"
 )
 
@@ -50,6 +51,8 @@ func Delete(id string, confirmed bool) types.Result {
 		}
 	}
 
+	task.Unwatch(p.Path)
+
 	return types.Result{
 		Success: true,
 		Message: fmt.Sprintf("프로젝트 삭제됨: %s\nPath: %s", id, p.Path),
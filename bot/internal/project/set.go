@@ -4,8 +4,11 @@ import (
 	"database/sql"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/task"
 	"parkjunwoo.com/claribot/internal/types"
 	"parkjunwoo.com/claribot/pkg/claude"
 )
@@ -28,8 +31,44 @@ func Set(id, field, value string) types.Result {
 		return setCategory(id, value)
 	case "pinned":
 		return setPinned(id, value)
+	case "graph_layers":
+		return setGraphLayers(id, value)
+	case "retry_max_attempts":
+		return setRetryMaxAttempts(id, value)
+	case "retry_backoff":
+		return setRetryBackoff(id, value)
+	case "retry_on":
+		return setRetryOn(id, value)
+	case "verify_build", "verify_test", "verify_lint":
+		return setVerifyCommand(id, field, value)
+	case "verify_fix":
+		return setVerifyFix(id, value)
+	case "verify_timeout":
+		return setVerifyTimeout(id, value)
+	case "worktree":
+		return setWorktree(id, value)
+	case "model":
+		return setModel(id, value)
+	case "allowed_tools":
+		return setAllowedTools(id, value)
+	case "timeout":
+		return setTimeout(id, value)
+	case "approval":
+		return setApproval(id, value)
+	case "replan_max":
+		return setReplanMax(id, value)
+	case "replan_auto":
+		return setReplanAuto(id, value)
+	case "auto_resume":
+		return setAutoResume(id, value)
+	case "context_map_limit":
+		return setContextMapLimit(id, value)
+	case "agent":
+		return setAgent(id, value)
+	case "quota":
+		return setQuota(id, value)
 	default:
-		return types.Result{Success: false, Message: fmt.Sprintf("알 수 없는 필드: %s (지원: parallel, description, category, pinned)", field)}
+		return types.Result{Success: false, Message: fmt.Sprintf("알 수 없는 필드: %s (지원: parallel, description, category, pinned, graph_layers, retry_max_attempts, retry_backoff, retry_on, verify_build, verify_test, verify_lint, verify_fix, verify_timeout, worktree, model, allowed_tools, timeout, approval, replan_max, replan_auto, auto_resume, context_map_limit, agent, quota)", field)}
 	}
 }
 
@@ -141,40 +180,393 @@ func setParallel(id, value string) types.Result {
 		}
 	}
 
+	if err := setLocalConfig(id, "parallel", strconv.Itoa(n)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' parallel = %d", id, n),
+	}
+}
+
+// setGraphLayers sets the Node Graph layer ordering for a project
+func setGraphLayers(id, value string) types.Result {
+	layers, err := task.ParseLayers(value)
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	if err := setLocalConfig(id, "graph_layers", strings.Join(layers, ",")); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' graph_layers = %s", id, strings.Join(layers, " → ")),
+	}
+}
+
+// setRetryMaxAttempts sets the total attempts per task in one traversal (1 = no retry)
+func setRetryMaxAttempts(id, value string) types.Result {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("숫자를 입력하세요: %s", value)}
+	}
+	if n < 1 || n > 10 {
+		return types.Result{Success: false, Message: "범위 오류: retry_max_attempts는 1~10 사이여야 합니다"}
+	}
+
+	if err := setLocalConfig(id, "retry_max_attempts", strconv.Itoa(n)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' retry_max_attempts = %d", id, n),
+	}
+}
+
+// setRetryBackoff sets the delay before the first retry (doubled on each retry)
+func setRetryBackoff(id, value string) types.Result {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return types.Result{Success: false, Message: fmt.Sprintf("기간 형식이 아닙니다: %s (예: 30s, 2m)", value)}
+	}
+
+	if err := setLocalConfig(id, "retry_backoff", d.String()); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' retry_backoff = %s", id, d),
+	}
+}
+
+// setRetryOn sets which error types are retried (empty = none)
+func setRetryOn(id, value string) types.Result {
+	errTypes, err := task.ParseRetryOn(value)
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	if err := setLocalConfig(id, "retry_on", strings.Join(errTypes, ",")); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	display := strings.Join(errTypes, ", ")
+	if display == "" {
+		display = "(없음)"
+	}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' retry_on = %s", id, display),
+	}
+}
+
+// setVerifyCommand sets a post-run verification command ("none" or empty disables the stage)
+func setVerifyCommand(id, field, value string) types.Result {
+	value = strings.TrimSpace(value)
+	if value == "none" {
+		value = ""
+	}
+	if err := setLocalConfig(id, field, value); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	if value == "" {
+		return types.Result{
+			Success: true,
+			Message: fmt.Sprintf("✅ 프로젝트 '%s' %s 해제", id, field),
+		}
+	}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' %s = %s", id, field, value),
+	}
+}
+
+// setVerifyFix enables/disables the fix-up run after a verification failure
+func setVerifyFix(id, value string) types.Result {
+	enabled := value == "1" || value == "true"
+
+	if err := setLocalConfig(id, "verify_fix", strconv.FormatBool(enabled)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' verify_fix = %t", id, enabled),
+	}
+}
+
+// setVerifyTimeout sets the timeout of each verification command
+func setVerifyTimeout(id, value string) types.Result {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return types.Result{Success: false, Message: fmt.Sprintf("기간 형식이 아닙니다: %s (예: 10m, 90s)", value)}
+	}
+
+	if err := setLocalConfig(id, "verify_timeout", d.String()); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' verify_timeout = %s", id, d),
+	}
+}
+
+// setWorktree enables/disables git worktree isolation for parallel workers
+func setWorktree(id, value string) types.Result {
+	enabled := value == "1" || value == "true"
+
+	if err := setLocalConfig(id, "worktree", strconv.FormatBool(enabled)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' worktree = %t", id, enabled),
+	}
+}
+
+// setModel sets the default Claude model for plan/run ("none" or empty uses the CLI default)
+func setModel(id, value string) types.Result {
+	value = strings.TrimSpace(value)
+	if value == "none" {
+		value = ""
+	}
+	if err := setLocalConfig(id, "model", value); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	if value == "" {
+		return types.Result{Success: true, Message: fmt.Sprintf("✅ 프로젝트 '%s' model 해제", id)}
+	}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' model = %s", id, value),
+	}
+}
+
+// setAllowedTools sets the default tool restriction for plan/run ("none" or empty allows all tools)
+func setAllowedTools(id, value string) types.Result {
+	if strings.TrimSpace(value) == "none" {
+		value = ""
+	}
+	tools := task.ParseAllowedTools(value)
+	if err := setLocalConfig(id, "allowed_tools", strings.Join(tools, ",")); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	if len(tools) == 0 {
+		return types.Result{Success: true, Message: fmt.Sprintf("✅ 프로젝트 '%s' allowed_tools 해제", id)}
+	}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' allowed_tools = %s", id, strings.Join(tools, ", ")),
+	}
+}
+
+// setTimeout sets the default Claude idle timeout for plan/run ("none" or empty uses the global config)
+func setTimeout(id, value string) types.Result {
+	if strings.TrimSpace(value) == "none" {
+		value = ""
+	}
+	d, err := task.ParseRunTimeout(value)
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	stored := ""
+	if d > 0 {
+		stored = d.String()
+	}
+	if err := setLocalConfig(id, "timeout", stored); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	if d == 0 {
+		return types.Result{Success: true, Message: fmt.Sprintf("✅ 프로젝트 '%s' timeout 해제", id)}
+	}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' timeout = %s", id, d),
+	}
+}
+
+// setApproval enables/disables the human approval gate between plan and run
+func setApproval(id, value string) types.Result {
+	enabled := value == "1" || value == "true"
+
+	if err := setLocalConfig(id, "approval", strconv.FormatBool(enabled)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' approval = %t", id, enabled),
+	}
+}
+
+// setReplanMax sets how many times a failed task may be re-planned (0 = never)
+func setReplanMax(id, value string) types.Result {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("숫자를 입력하세요: %s", value)}
+	}
+	if n < 0 || n > 10 {
+		return types.Result{Success: false, Message: "범위 오류: replan_max는 0~10 사이여야 합니다"}
+	}
+
+	if err := setLocalConfig(id, "replan_max", strconv.Itoa(n)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' replan_max = %d", id, n),
+	}
+}
+
+// setReplanAuto enables/disables re-planning of failed tasks inside cycle
+func setReplanAuto(id, value string) types.Result {
+	enabled := value == "1" || value == "true"
+
+	if err := setLocalConfig(id, "replan_auto", strconv.FormatBool(enabled)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' replan_auto = %t", id, enabled),
+	}
+}
+
+// setAutoResume enables/disables resuming an interrupted traversal when claribot restarts
+func setAutoResume(id, value string) types.Result {
+	enabled := value == "1" || value == "true"
+
+	if err := setLocalConfig(id, "auto_resume", strconv.FormatBool(enabled)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' auto_resume = %t", id, enabled),
+	}
+}
+
+// setContextMapLimit sets the task count above which a labeled task's context map
+// is limited to its labels (0 = always the whole tree)
+func setContextMapLimit(id, value string) types.Result {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("숫자를 입력하세요: %s", value)}
+	}
+	if n < 0 {
+		return types.Result{Success: false, Message: "범위 오류: context_map_limit는 0 이상이어야 합니다"}
+	}
+
+	if err := setLocalConfig(id, "context_map_limit", strconv.Itoa(n)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' context_map_limit = %d", id, n),
+	}
+}
+
+// setAgent selects the agent backend for plan/run, messages and schedules ("none" or empty uses the default)
+func setAgent(id, value string) types.Result {
+	value = strings.TrimSpace(value)
+	if value == "none" {
+		value = ""
+	}
+	if value != "" {
+		if _, err := claude.LookupAgent(value); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("알 수 없는 agent: %s (지원: %s)", value, strings.Join(claude.AgentNames(), ", ")),
+			}
+		}
+	}
+	if err := setLocalConfig(id, "agent", value); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	if value == "" {
+		return types.Result{Success: true, Message: fmt.Sprintf("✅ 프로젝트 '%s' agent 해제 (%s)", id, claude.DefaultAgent)}
+	}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' agent = %s", id, value),
+	}
+}
+
+// setQuota sets the max concurrent Claude runs of a project across task, message and schedule runs (0 = no limit)
+func setQuota(id, value string) types.Result {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("숫자를 입력하세요: %s", value)}
+	}
+
+	maxClaude := claude.GetStatus().Max
+	if n < 0 || n > maxClaude {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("범위 오류: quota는 0~%d 사이여야 합니다 (0 = 제한 없음, 현재 claude.max=%d)", maxClaude, maxClaude),
+		}
+	}
+
+	if err := setLocalConfig(id, "quota", strconv.Itoa(n)); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	if n == 0 {
+		return types.Result{Success: true, Message: fmt.Sprintf("✅ 프로젝트 '%s' quota 해제", id)}
+	}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 프로젝트 '%s' quota = %d", id, n),
+	}
+}
+
+// setLocalConfig upserts a key in the project's local DB config table
+func setLocalConfig(id, key, value string) error {
 	// Get project path from global DB
 	globalDB, err := db.OpenGlobal()
 	if err != nil {
-		return types.Result{Success: false, Message: fmt.Sprintf("전역 DB 열기 실패: %v", err)}
+		return fmt.Errorf("전역 DB 열기 실패: %v", err)
 	}
 	defer globalDB.Close()
 
 	var projectPath string
 	err = globalDB.QueryRow("SELECT path FROM projects WHERE id = ?", id).Scan(&projectPath)
 	if err == sql.ErrNoRows {
-		return types.Result{Success: false, Message: fmt.Sprintf("프로젝트를 찾을 수 없습니다: %s", id)}
+		return fmt.Errorf("프로젝트를 찾을 수 없습니다: %s", id)
 	}
 	if err != nil {
-		return types.Result{Success: false, Message: fmt.Sprintf("프로젝트 조회 실패: %v", err)}
+		return fmt.Errorf("프로젝트 조회 실패: %v", err)
 	}
 
 	// Open local DB and upsert config
 	localDB, err := db.OpenLocal(projectPath)
 	if err != nil {
-		return types.Result{Success: false, Message: fmt.Sprintf("로컬 DB 열기 실패: %v", err)}
+		return fmt.Errorf("로컬 DB 열기 실패: %v", err)
 	}
 	defer localDB.Close()
 
 	now := db.TimeNow()
 	_, err = localDB.Exec(
 		"INSERT OR REPLACE INTO config (key, value, updated_at) VALUES (?, ?, ?)",
-		"parallel", strconv.Itoa(n), now,
+		key, value, now,
 	)
 	if err != nil {
-		return types.Result{Success: false, Message: fmt.Sprintf("설정 저장 실패: %v", err)}
-	}
-
-	return types.Result{
-		Success: true,
-		Message: fmt.Sprintf("✅ 프로젝트 '%s' parallel = %d", id, n),
+		return fmt.Errorf("설정 저장 실패: %v", err)
 	}
+	return nil
 }
@@ -39,7 +39,7 @@ func Runs(scheduleID string, req pagination.PageRequest) types.Result {
 	}
 
 	rows, err := globalDB.Query(`
-		SELECT id, schedule_id, status, result, error, started_at, completed_at
+		SELECT id, schedule_id, status, result, error, COALESCE(run_id, ''), started_at, completed_at
 		FROM schedule_runs
 		WHERE schedule_id = ?
 		ORDER BY id DESC
@@ -56,7 +56,7 @@ func Runs(scheduleID string, req pagination.PageRequest) types.Result {
 	var runs []ScheduleRun
 	for rows.Next() {
 		var r ScheduleRun
-		if err := rows.Scan(&r.ID, &r.ScheduleID, &r.Status, &r.Result, &r.Error, &r.StartedAt, &r.CompletedAt); err != nil {
+		if err := rows.Scan(&r.ID, &r.ScheduleID, &r.Status, &r.Result, &r.Error, &r.RunID, &r.StartedAt, &r.CompletedAt); err != nil {
 			return types.Result{
 				Success: false,
 				Message: fmt.Sprintf("스캔 실패: %v", err),
@@ -112,9 +112,9 @@ func Run(runID string) types.Result {
 
 	var r ScheduleRun
 	err = globalDB.QueryRow(`
-		SELECT id, schedule_id, status, result, error, started_at, completed_at
+		SELECT id, schedule_id, status, result, error, COALESCE(run_id, ''), started_at, completed_at
 		FROM schedule_runs WHERE id = ?
-	`, runID).Scan(&r.ID, &r.ScheduleID, &r.Status, &r.Result, &r.Error, &r.StartedAt, &r.CompletedAt)
+	`, runID).Scan(&r.ID, &r.ScheduleID, &r.Status, &r.Result, &r.Error, &r.RunID, &r.StartedAt, &r.CompletedAt)
 
 	if err == sql.ErrNoRows {
 		return types.Result{
@@ -145,6 +145,10 @@ func Run(runID string) types.Result {
 		msg += fmt.Sprintf("\n\n❌ 에러:\n%s", r.Error)
 	}
 
+	if r.RunID != "" {
+		msg += fmt.Sprintf("\n\n📜 실행 로그: run %s", r.RunID)
+	}
+
 	msg += fmt.Sprintf("\n\n[스케줄 보기:schedule get %d][실행 기록:schedule runs %d]", r.ScheduleID, r.ScheduleID)
 
 	return types.Result{
@@ -22,6 +22,7 @@ type ScheduleRun struct {
 	Status      string  `json:"status"`       // running, done, failed
 	Result      string  `json:"result"`       // Claude Code 실행 결과
 	Error       string  `json:"error,omitempty"`
+	RunID       string  `json:"run_id,omitempty"` // Claude run transcript (GET /api/runs/{id}/log)
 	StartedAt   string  `json:"started_at"`
 	CompletedAt *string `json:"completed_at,omitempty"`
 }
@@ -16,6 +16,7 @@ import (
 	"parkjunwoo.com/claribot/internal/db"
 	"parkjunwoo.com/claribot/internal/project"
 	"parkjunwoo.com/claribot/internal/prompts"
+	"parkjunwoo.com/claribot/internal/task"
 	"parkjunwoo.com/claribot/pkg/claude"
 )
 
@@ -281,6 +282,13 @@ func (s *Scheduler) execute(scheduleID int, msg string, projectID *string, runOn
 			SystemPrompt: systemPrompt,
 			WorkDir:      projectPath,
 			ReportPath:   reportPath,
+			Agent:        task.ProjectAgent(projectPath),
+			Priority:     claude.PrioritySchedule,
+			Origin:       claude.Origin{Project: projectPath, ScheduleID: scheduleID},
+			RunID:        claude.NewRunID(),
+		}
+		if _, err := globalDB.Exec(`UPDATE schedule_runs SET run_id = ? WHERE id = ?`, opts.RunID, runID); err != nil {
+			log.Printf("Scheduler: schedule_run 로그 연결 실패 (run #%d): %v", runID, err)
 		}
 
 		claudeResult, claudeErr := claude.Run(opts)
@@ -305,6 +313,9 @@ func (s *Scheduler) execute(scheduleID int, msg string, projectID *string, runOn
 		}
 	}
 
+	// Task files the run edited are synced as changed by the schedule
+	syncScheduleEdits(projectPath, scheduleID)
+
 	// Update schedule_run with result
 	_, err = globalDB.Exec(`
 		UPDATE schedule_runs
@@ -369,6 +380,27 @@ func (s *Scheduler) execute(scheduleID int, msg string, projectID *string, runOn
 	}
 }
 
+// syncScheduleEdits syncs the project's task files after a scheduled run, recording
+// their status changes with the schedule actor. A running traversal commits the task
+// files itself, so the edits are left to the file watcher then.
+func syncScheduleEdits(projectPath string, scheduleID int) {
+	if _, err := os.Stat(filepath.Join(projectPath, ".claribot", "db.clt")); err != nil {
+		return
+	}
+	if task.IsCycleRunning(projectPath) {
+		log.Printf("Scheduler: 스케줄 #%d 작업 파일 동기화 건너뜀 (순회 중)", scheduleID)
+		return
+	}
+	result, err := task.SyncWithContext(task.WithActor(context.Background(), task.ActorSchedule), projectPath)
+	if err != nil {
+		log.Printf("Scheduler: 스케줄 #%d 작업 파일 동기화 실패: %v", scheduleID, err)
+		return
+	}
+	if changes := result.Inserted + result.Updated + result.Deleted; changes > 0 {
+		log.Printf("Scheduler: 스케줄 #%d 작업 파일 동기화: 추가 %d, 수정 %d, 삭제 %d", scheduleID, result.Inserted, result.Updated, result.Deleted)
+	}
+}
+
 // updateRunTimes updates last_run and next_run for a schedule
 func (s *Scheduler) updateRunTimes(scheduleID int, globalDB *db.DB) {
 	// Get cron expression
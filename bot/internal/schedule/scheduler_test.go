@@ -0,0 +1,163 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robfig/cron/v3"
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/task"
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+// setupProject creates a global DB in a temporary HOME and registers a project
+// whose local config selects a scripted fake agent.
+func setupProject(t *testing.T, steps ...claude.FakeStep) (string, string, *claude.FakeAgent) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	globalDB, err := db.OpenGlobal()
+	if err != nil {
+		t.Fatalf("OpenGlobal failed: %v", err)
+	}
+	defer globalDB.Close()
+	if err := globalDB.MigrateGlobal(); err != nil {
+		t.Fatalf("MigrateGlobal failed: %v", err)
+	}
+
+	projectPath := t.TempDir()
+	now := db.TimeNow()
+	if _, err := globalDB.Exec(`
+		INSERT INTO projects (id, name, path, description, status, category, pinned, last_accessed, created_at, updated_at)
+		VALUES ('demo', 'demo', ?, 'test', 'active', '', 0, '', ?, ?)
+	`, projectPath, now, now); err != nil {
+		t.Fatalf("Project insert failed: %v", err)
+	}
+
+	fake := claude.NewFakeAgent("fake-"+t.Name(), steps...)
+	claude.RegisterAgent(fake)
+	t.Cleanup(func() { claude.UnregisterAgent(fake.Name()) })
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+	localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES ('agent', ?, ?)", fake.Name(), now)
+
+	return "demo", projectPath, fake
+}
+
+// runSchedule adds a schedule and executes it once, returning its run and the notification.
+func runSchedule(t *testing.T, projectID, msg, scheduleType string) (*ScheduleRun, string) {
+	t.Helper()
+	r := Add("0 9 * * *", msg, &projectID, false, scheduleType)
+	if !r.Success {
+		t.Fatalf("Add failed: %s", r.Message)
+	}
+	id := r.Data.(*Schedule).ID
+
+	var notification string
+	s := &Scheduler{
+		cron:          cron.New(),
+		jobs:          make(map[int]cron.EntryID),
+		failureCounts: make(map[int]int),
+		notifier:      func(_ *string, msg string) { notification = msg },
+	}
+	s.execute(id, msg, &projectID, false, scheduleType)
+
+	globalDB, err := db.OpenGlobal()
+	if err != nil {
+		t.Fatalf("OpenGlobal failed: %v", err)
+	}
+	defer globalDB.Close()
+	var run ScheduleRun
+	var runID *string
+	err = globalDB.QueryRow(`
+		SELECT id, status, COALESCE(result, ''), COALESCE(error, ''), run_id
+		FROM schedule_runs WHERE schedule_id = ? ORDER BY id DESC LIMIT 1
+	`, id).Scan(&run.ID, &run.Status, &run.Result, &run.Error, &runID)
+	if err != nil {
+		t.Fatalf("No schedule run: %v", err)
+	}
+	if runID != nil {
+		run.RunID = *runID
+	}
+	return &run, notification
+}
+
+func TestExecuteClaude(t *testing.T) {
+	projectID, projectPath, fake := setupProject(t,
+		claude.FakeStep{Output: "확인 중\n", Report: "## 요약\n의존성 최신"},
+	)
+
+	run, notification := runSchedule(t, projectID, "의존성 점검", "claude")
+	if run.Status != "done" || run.Result != "## 요약\n의존성 최신" || run.RunID == "" {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+	if !strings.Contains(notification, "스케줄 실행 완료") || !strings.Contains(notification, "의존성 최신") {
+		t.Errorf("Unexpected notification: %s", notification)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 agent call, got %d", len(calls))
+	}
+	opts := calls[0]
+	if opts.UserPrompt != "의존성 점검" || opts.WorkDir != projectPath || opts.Priority != claude.PrioritySchedule || opts.RunID != run.RunID {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	// The report file is removed once saved
+	if _, err := os.Stat(opts.ReportPath); !os.IsNotExist(err) {
+		t.Errorf("Expected report removed: %s", opts.ReportPath)
+	}
+	if !strings.HasPrefix(opts.ReportPath, filepath.Join(projectPath, ".claribot")) {
+		t.Errorf("Unexpected report path: %s", opts.ReportPath)
+	}
+}
+
+func TestExecuteClaudeFailure(t *testing.T) {
+	projectID, _, _ := setupProject(t, claude.FakeStep{Output: "boom", ExitCode: 2})
+
+	run, notification := runSchedule(t, projectID, "빌드", "claude")
+	if run.Status != "failed" || run.Error != "exit code: 2" || run.Result != "boom" {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+	if !strings.Contains(notification, "스케줄 실행 실패") {
+		t.Errorf("Unexpected notification: %s", notification)
+	}
+}
+
+func TestExecuteBash(t *testing.T) {
+	projectID, projectPath, fake := setupProject(t)
+
+	run, _ := runSchedule(t, projectID, "pwd", "bash")
+	if run.Status != "done" || strings.TrimSpace(run.Result) != projectPath {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+	if len(fake.Calls()) != 0 {
+		t.Error("Expected no agent call for a bash schedule")
+	}
+}
+
+func TestExecuteSyncsTaskFiles(t *testing.T) {
+	projectID, projectPath, _ := setupProject(t)
+	if r := task.Add(projectPath, "의존성 업데이트", nil, ""); !r.Success {
+		t.Fatalf("Add failed: %s", r.Message)
+	}
+
+	// A task file edited by the run is synced with the schedule actor
+	run, _ := runSchedule(t, projectID, "sed -i 's/^status: todo$/status: done/' .claribot/tasks/1.md", "bash")
+	if run.Status != "done" {
+		t.Fatalf("Unexpected run: %+v", run)
+	}
+	r := task.ListEvents(projectPath, "1")
+	if !r.Success {
+		t.Fatalf("ListEvents failed: %s", r.Message)
+	}
+	events := r.Data.([]task.TaskEvent)
+	last := events[len(events)-1]
+	if last.FromStatus != "todo" || last.ToStatus != "done" || last.Actor != task.ActorSchedule {
+		t.Errorf("Unexpected event: %+v", last)
+	}
+}
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -11,6 +12,12 @@ import (
 
 // Add adds a new task with optional parent and spec
 func Add(projectPath, title string, parentID *int, spec string) types.Result {
+	return AddWithDeps(projectPath, title, parentID, spec, nil)
+}
+
+// AddWithDeps adds a new task with optional parent, spec and dependencies.
+// Dependencies must reference existing tasks; a new task cannot form a cycle.
+func AddWithDeps(projectPath, title string, parentID *int, spec string, dependsOn []int) types.Result {
 	// Auto-generate title from spec first line if empty
 	if title == "" && spec != "" {
 		firstLine := strings.SplitN(spec, "\n", 2)[0]
@@ -30,8 +37,9 @@ func Add(projectPath, title string, parentID *int, spec string) types.Result {
 	}
 	defer localDB.Close()
 
-	// Calculate depth from parent
+	// Calculate depth from parent (children inherit the parent's labels)
 	depth := 0
+	var labels []string
 	if parentID != nil {
 		var parentDepth int
 		err := localDB.QueryRow("SELECT depth FROM tasks WHERE id = ?", *parentID).Scan(&parentDepth)
@@ -42,6 +50,19 @@ func Add(projectPath, title string, parentID *int, spec string) types.Result {
 			}
 		}
 		depth = parentDepth + 1
+		if labels, err = loadLabels(localDB, *parentID); err != nil {
+			log.Printf("[Task] 부모 라벨 조회 실패 (#%d): %v", *parentID, err)
+		}
+	}
+
+	// Validate dependencies before insert (new task has no dependents, so only existence matters)
+	if len(dependsOn) > 0 {
+		if err := validateDependencies(localDB, 0, dependsOn); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("의존성 오류: %v", err),
+			}
+		}
 	}
 
 	now := db.TimeNow()
@@ -64,16 +85,36 @@ func Add(projectPath, title string, parentID *int, spec string) types.Result {
 		}
 	}
 
+	if len(dependsOn) > 0 {
+		if err := SetDependencies(localDB, int(id), dependsOn); err != nil {
+			// Undo the insert: the task would have no file and no dependencies
+			if _, delErr := localDB.Exec(`DELETE FROM tasks WHERE id = ?`, id); delErr != nil {
+				log.Printf("[Task] 추가 취소 실패 (#%d): %v", id, delErr)
+			}
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("의존성 저장 실패: %v", err),
+			}
+		}
+	}
+
+	if len(labels) > 0 {
+		if err := SetLabels(localDB, int(id), labels); err != nil {
+			log.Printf("[Task] 라벨 저장 실패 (#%d): %v", id, err)
+		}
+	}
+
 	// Dual-write: create task file
-	fm := Frontmatter{Status: "todo", Parent: parentID}
+	fm := Frontmatter{Status: "todo", Parent: parentID, DependsOn: dependsOn, Labels: labels}
 	if err := WriteTaskContent(projectPath, int(id), fm, title, spec); err != nil {
 		log.Printf("[Task] task 파일 생성 실패 (#%d): %v", id, err)
 	}
-	gitCommitTask(projectPath, int(id), "created")
+	gitCommitTask(context.Background(), projectPath, int(id), "created")
 
 	msg := fmt.Sprintf("작업 추가됨: #%d %s", id, title)
 	if parentID != nil {
 		// Update parent: set status to 'split' and is_leaf to 0
+		parentStatus := taskStatus(localDB, *parentID)
 		_, err = localDB.Exec(`
 			UPDATE tasks SET status = 'split', is_leaf = 0, updated_at = ? WHERE id = ?
 		`, now, *parentID)
@@ -83,8 +124,12 @@ func Add(projectPath, title string, parentID *int, spec string) types.Result {
 				Message: fmt.Sprintf("부모 작업 상태 업데이트 실패: %v", err),
 			}
 		}
+		recordEvent(localDB, *parentID, parentStatus, "split", ActorRollup, 0)
 		msg += fmt.Sprintf(" (부모: #%d → split, depth: %d)", *parentID, depth)
 	}
+	if len(dependsOn) > 0 {
+		msg += fmt.Sprintf("\n선행 작업: %s", formatDependsOn(dependsOn))
+	}
 
 	return types.Result{
 		Success: true,
@@ -97,8 +142,72 @@ func Add(projectPath, title string, parentID *int, spec string) types.Result {
 			Status:    "todo",
 			IsLeaf:    true,
 			Depth:     depth,
+			DependsOn: dependsOn,
+			Labels:    labels,
 			CreatedAt: now,
 			UpdatedAt: now,
 		},
 	}
 }
+
+// addChildren creates the children of a structured split under parent, in order.
+// Each child's After (earlier siblings, 1-based) and DependsOn (existing tasks) become
+// its dependencies. Existing-task dependencies are checked before anything is created.
+// Returns the IDs of the created children.
+func addChildren(ctx context.Context, localDB *db.DB, projectPath string, parent *Task, children []Child) ([]int, error) {
+	for i, c := range children {
+		if len(c.DependsOn) > 0 {
+			if err := validateDependencies(localDB, 0, c.DependsOn); err != nil {
+				return nil, fmt.Errorf("하위 작업 %d (%s) 의존성 오류: %w", i+1, c.Title, err)
+			}
+		}
+	}
+
+	depth := parent.Depth + 1
+	labels, err := loadLabels(localDB, parent.ID)
+	if err != nil {
+		log.Printf("[Task] 부모 라벨 조회 실패 (#%d): %v", parent.ID, err)
+	}
+	ids := make([]int, 0, len(children))
+	for i, c := range children {
+		deps := append([]int(nil), c.DependsOn...)
+		for _, a := range c.After {
+			deps = append(deps, ids[a-1])
+		}
+
+		now := db.TimeNow()
+		res, err := localDB.Exec(`
+			INSERT INTO tasks (parent_id, title, status, priority, is_leaf, depth, created_at, updated_at)
+			VALUES (?, ?, 'todo', ?, 1, ?, ?, ?)
+		`, parent.ID, c.Title, c.Priority, depth, now, now)
+		if err != nil {
+			return ids, fmt.Errorf("하위 작업 %d (%s) 추가 실패: %w", i+1, c.Title, err)
+		}
+		id64, err := res.LastInsertId()
+		if err != nil {
+			return ids, fmt.Errorf("하위 작업 %d (%s) ID 획득 실패: %w", i+1, c.Title, err)
+		}
+		id := int(id64)
+		ids = append(ids, id)
+
+		if len(deps) > 0 {
+			if err := SetDependencies(localDB, id, deps); err != nil {
+				return ids, fmt.Errorf("하위 작업 #%d 의존성 저장 실패: %w", id, err)
+			}
+		}
+		if len(labels) > 0 {
+			if err := SetLabels(localDB, id, labels); err != nil {
+				log.Printf("[Task] 라벨 저장 실패 (#%d): %v", id, err)
+			}
+		}
+
+		// Dual-write: create task file
+		parentID := parent.ID
+		fm := Frontmatter{Status: "todo", Parent: &parentID, Priority: c.Priority, DependsOn: deps, Labels: labels}
+		if err := WriteTaskContent(projectPath, id, fm, c.Title, c.Spec); err != nil {
+			log.Printf("[Task] task 파일 생성 실패 (#%d): %v", id, err)
+		}
+		gitCommitTask(ctx, projectPath, id, "created")
+	}
+	return ids, nil
+}
@@ -0,0 +1,98 @@
+package task
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+// useFakeAgent registers a scripted agent and selects it for the project.
+func useFakeAgent(t *testing.T, projectPath string, steps ...claude.FakeStep) *claude.FakeAgent {
+	t.Helper()
+	fake := claude.NewFakeAgent("fake-"+t.Name(), steps...)
+	claude.RegisterAgent(fake)
+	t.Cleanup(func() { claude.UnregisterAgent(fake.Name()) })
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+	localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES ('agent', ?, ?)", fake.Name(), db.TimeNow())
+	return fake
+}
+
+func TestAgentPipeline(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	fake := useFakeAgent(t, projectPath,
+		claude.FakeStep{Report: "[PLANNED]\n## 구현 방향\n간단한 수정"},
+		claude.FakeStep{Report: "로그인 구현 완료"},
+	)
+	if ProjectAgent(projectPath) != fake.Name() {
+		t.Fatalf("Expected project agent %s, got %q", fake.Name(), ProjectAgent(projectPath))
+	}
+
+	if r := Plan(projectPath, "1", ActorCLI); !r.Success {
+		t.Fatalf("Plan failed: %s", r.Message)
+	}
+	if r := Run(projectPath, "1", ActorCLI); !r.Success {
+		t.Fatalf("Run failed: %s", r.Message)
+	}
+
+	got := Get(projectPath, "1")
+	task := got.Data.(*Task)
+	if task.Status != "done" || task.Report != "로그인 구현 완료" {
+		t.Errorf("Expected done with fake report, got %s %q", task.Status, task.Report)
+	}
+	if !strings.Contains(got.Message, "agent="+fake.Name()) {
+		t.Errorf("Expected agent in task get: %s", got.Message)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected plan + run calls, got %d", len(calls))
+	}
+
+	// Each attempt links the transcript of its run
+	localDB, _ := db.OpenLocal(projectPath)
+	attempts, _ := loadAttempts(localDB, 1)
+	localDB.Close()
+	if len(attempts) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(attempts))
+	}
+	for i, a := range attempts {
+		if a.RunID == "" || a.RunID != calls[i].RunID {
+			t.Errorf("Expected attempt %d linked to its run, got %q", a.ID, a.RunID)
+		}
+		if _, err := os.Stat(claude.TranscriptPath(projectPath, a.RunID, false)); err != nil {
+			t.Errorf("Expected transcript for attempt %d: %v", a.ID, err)
+		}
+	}
+	if calls[1].ReportPath == "" || !strings.Contains(calls[1].UserPrompt, "Auth") {
+		t.Errorf("Unexpected run options: %+v", calls[1])
+	}
+}
+
+func TestAgentAuthError(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	Set(projectPath, "1", "plan", "간단한 수정", ActorCLI)
+	Set(projectPath, "1", "status", "planned", ActorCLI)
+	useFakeAgent(t, projectPath, claude.FakeStep{Output: "Please login again.", ExitCode: 1})
+
+	r := Run(projectPath, "1", ActorCLI)
+	if r.Success || r.ErrorType != "auth_error" {
+		t.Errorf("Expected auth_error, got %v %s", r.Success, r.ErrorType)
+	}
+	if task := Get(projectPath, "1").Data.(*Task); task.Status != "failed" {
+		t.Errorf("Expected failed, got %s", task.Status)
+	}
+}
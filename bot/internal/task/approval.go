@@ -0,0 +1,157 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+)
+
+// maxReviewButtons caps the approve/reject buttons listed in a cycle summary.
+const maxReviewButtons = 10
+
+// getApprovalMode reads the plan approval config from project local DB.
+// When enabled, planned leaves stop at 'review' until a human approves them.
+func getApprovalMode(localDB *db.DB) bool {
+	var val string
+	if err := localDB.QueryRow("SELECT value FROM config WHERE key = 'approval'").Scan(&val); err != nil {
+		return false
+	}
+	return val == "true"
+}
+
+// reviewButtons returns the approve/reject buttons of a task waiting for review.
+func reviewButtons(taskID int) string {
+	return fmt.Sprintf("[승인:task approve %d][반려:task reject %d]", taskID, taskID)
+}
+
+// loadReviewTask loads a task and checks that it is waiting for review.
+func loadReviewTask(localDB *db.DB, id string) (*Task, *types.Result) {
+	var t Task
+	err := localDB.QueryRow("SELECT id, title, status FROM tasks WHERE id = ?", strings.TrimPrefix(id, "#")).Scan(&t.ID, &t.Title, &t.Status)
+	if err == sql.ErrNoRows {
+		return nil, &types.Result{Success: false, Message: fmt.Sprintf("작업을 찾을 수 없습니다: #%s", id)}
+	}
+	if err != nil {
+		return nil, &types.Result{Success: false, Message: fmt.Sprintf("조회 실패: %v", err)}
+	}
+	if t.Status != "review" {
+		return nil, &types.Result{
+			Success: false,
+			Message: fmt.Sprintf("작업 #%d은(는) %s 상태입니다. (review 상태만 승인/반려 가능)", t.ID, t.Status),
+		}
+	}
+	return &t, nil
+}
+
+// Approve accepts the plan of a task in review (review → planned).
+// actor is recorded on the status change (see task_events).
+func Approve(projectPath, id, actor string) types.Result {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("DB 열기 실패: %v", err)}
+	}
+	defer localDB.Close()
+
+	t, errResult := loadReviewTask(localDB, id)
+	if errResult != nil {
+		return *errResult
+	}
+
+	if _, err := localDB.Exec("UPDATE tasks SET status = 'planned', updated_at = ? WHERE id = ?", db.TimeNow(), t.ID); err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("상태 업데이트 실패: %v", err)}
+	}
+	recordEvent(localDB, t.ID, t.Status, "planned", actor, 0)
+	if err := updateTaskFileStatus(projectPath, t.ID, "planned"); err != nil {
+		log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", t.ID, err)
+	}
+	gitCommitTask(context.Background(), projectPath, t.ID, "approved")
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("✅ 작업 #%d Plan 승인됨: %s\n[조회:task get %d][실행:task run %d]", t.ID, t.Title, t.ID, t.ID),
+	}
+}
+
+// Reject sends the plan of a task in review back to todo (review → todo).
+// The reviewer's comment is saved as {id}.review.md and included in the next plan prompt.
+// actor is recorded on the status change (see task_events).
+func Reject(projectPath, id, comment, actor string) types.Result {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return types.Result{Success: false, Message: "반려 사유를 입력하세요"}
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("DB 열기 실패: %v", err)}
+	}
+	defer localDB.Close()
+
+	t, errResult := loadReviewTask(localDB, id)
+	if errResult != nil {
+		return *errResult
+	}
+
+	if err := WriteReviewContent(projectPath, t.ID, comment); err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("반려 코멘트 저장 실패: %v", err)}
+	}
+	if _, err := localDB.Exec("UPDATE tasks SET status = 'todo', updated_at = ? WHERE id = ?", db.TimeNow(), t.ID); err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("상태 업데이트 실패: %v", err)}
+	}
+	recordEvent(localDB, t.ID, t.Status, "todo", actor, 0)
+	if err := updateTaskFileStatus(projectPath, t.ID, "todo"); err != nil {
+		log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", t.ID, err)
+	}
+	gitCommitTask(context.Background(), projectPath, t.ID, "rejected")
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("↩️ 작업 #%d Plan 반려됨: %s\n반려 사유는 다음 Plan 생성에 반영됩니다.\n[Plan 재생성:task plan %d][조회:task get %d]", t.ID, t.Title, t.ID, t.ID),
+	}
+}
+
+// clearReview removes the reviewer comment once a new plan has addressed it.
+func clearReview(projectPath string, taskID int) {
+	if err := os.Remove(ReviewFilePath(projectPath, taskID)); err != nil && !os.IsNotExist(err) {
+		log.Printf("[Task] review 파일 삭제 실패 (#%d): %v", taskID, err)
+	}
+}
+
+// formatPendingReviews lists the tasks waiting for review with approve/reject buttons,
+// limited to the subtree of rootID (0 = whole project).
+// Returns the count and the list ("" if none).
+func formatPendingReviews(localDB *db.DB, rootID int) (int, string) {
+	scope, scopeArgs := scopeFilter(rootID)
+	rows, err := localDB.Query("SELECT id, title FROM tasks WHERE status = 'review'"+scope+" ORDER BY id ASC", scopeArgs...)
+	if err != nil {
+		return 0, ""
+	}
+	defer rows.Close()
+
+	var sb strings.Builder
+	count := 0
+	for rows.Next() {
+		var id int
+		var title string
+		if rows.Scan(&id, &title) != nil {
+			continue
+		}
+		count++
+		if count <= maxReviewButtons {
+			sb.WriteString(fmt.Sprintf("\n  🔍 #%d %s [#%d 승인:task approve %d][#%d 반려:task reject %d]", id, title, id, id, id, id))
+		}
+	}
+	if count == 0 {
+		return 0, ""
+	}
+	if count > maxReviewButtons {
+		sb.WriteString(fmt.Sprintf("\n  ... 외 %d개", count-maxReviewButtons))
+	}
+	return count, fmt.Sprintf("🔍 승인 대기: %d개 작업%s", count, sb.String())
+}
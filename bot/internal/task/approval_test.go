@@ -0,0 +1,91 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestApproveReject(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Add login", nil, "spec")
+	WritePlanContent(projectPath, 1, "use sessions")
+	if r := Set(projectPath, "1", "status", "review", ActorCLI); !r.Success {
+		t.Fatalf("Set status review failed: %s", r.Message)
+	}
+
+	// Review tasks are not executable
+	if r := Run(projectPath, "1", ActorCLI); r.Success || !strings.Contains(r.Message, "task approve 1") {
+		t.Errorf("Expected run to be refused with approve button: %s", r.Message)
+	}
+	if got := Get(projectPath, "1"); !strings.Contains(got.Message, "[승인:task approve 1][반려:task reject 1]") {
+		t.Errorf("Expected review buttons in task get: %s", got.Message)
+	}
+
+	// Reject needs a comment, then carries it into the next plan prompt
+	if r := Reject(projectPath, "1", " ", ActorCLI); r.Success {
+		t.Error("Expected reject without comment to fail")
+	}
+	if r := Reject(projectPath, "1", "use JWT instead", ActorCLI); !r.Success {
+		t.Fatalf("Reject failed: %s", r.Message)
+	}
+	task := Get(projectPath, "1").Data.(*Task)
+	if task.Status != "todo" || task.Review != "use JWT instead" {
+		t.Errorf("Expected todo with review comment, got %s %q", task.Status, task.Review)
+	}
+	if tc, _ := ReadTaskContent(projectPath, 1); tc.Frontmatter.Status != "todo" {
+		t.Errorf("Expected frontmatter status todo, got %s", tc.Frontmatter.Status)
+	}
+	prompt := BuildPlanPrompt(task, "", "/tmp/report.md")
+	if !strings.Contains(prompt, "use JWT instead") || !strings.Contains(prompt, "use sessions") {
+		t.Errorf("Expected review comment and previous plan in plan prompt")
+	}
+
+	// Only review tasks can be approved
+	if r := Approve(projectPath, "1", ActorCLI); r.Success {
+		t.Error("Expected approve of a todo task to fail")
+	}
+	Set(projectPath, "1", "status", "review", ActorCLI)
+	if r := Approve(projectPath, "1", ActorCLI); !r.Success {
+		t.Fatalf("Approve failed: %s", r.Message)
+	}
+	if task := Get(projectPath, "1").Data.(*Task); task.Status != "planned" {
+		t.Errorf("Expected planned after approve, got %s", task.Status)
+	}
+}
+
+func TestPendingReviews(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	if getApprovalMode(localDB) {
+		t.Error("Expected approval off by default")
+	}
+	localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES ('approval', 'true', ?)", db.TimeNow())
+	if !getApprovalMode(localDB) {
+		t.Error("Expected approval on")
+	}
+
+	Add(projectPath, "A", nil, "spec")
+	Add(projectPath, "B", nil, "spec")
+	Set(projectPath, "2", "status", "review", ActorCLI)
+
+	count, msg := formatPendingReviews(localDB, 0)
+	if count != 1 || !strings.Contains(msg, "[#2 승인:task approve 2]") {
+		t.Errorf("Unexpected pending reviews: %d %s", count, msg)
+	}
+
+	stats, err := GetStats(projectPath)
+	if err != nil || stats.Review != 1 {
+		t.Errorf("Expected 1 review in stats, got %+v (%v)", stats, err)
+	}
+}
@@ -0,0 +1,364 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+// Retry policy defaults: retries are off (1 attempt) until retry_max_attempts is raised;
+// then transient failures are retried after 30s, doubled on each retry
+const (
+	defaultRetryMaxAttempts = 1
+	defaultRetryBackoff     = 30 * time.Second
+	defaultRetryOn          = "timeout,exec_error"
+)
+
+// RetryableErrorTypes are the error types a retry policy may select.
+// timeout: idle/absolute timeout, exec_error: Claude failed to start or crashed,
+// exit_error: Claude exited with a non-zero code, verify_failed: verification commands failed,
+// merge_conflict: the task branch could not be merged (worktree mode).
+var RetryableErrorTypes = []string{"timeout", "exec_error", "exit_error", "verify_failed", "merge_conflict"}
+
+// attemptDirName is the directory (under .claribot) holding attempt outputs.
+const attemptDirName = "attempts"
+
+// Attempt is one plan/run execution of a task.
+type Attempt struct {
+	ID         int    `json:"id"`
+	TaskID     int    `json:"task_id"`
+	Phase      string `json:"phase"`   // plan | run
+	Attempt    int    `json:"attempt"` // 1-based, per task and phase
+	Status     string `json:"status"`  // running | done | failed | cancelled
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	ErrorType  string `json:"error_type,omitempty"`
+	Error      string `json:"error,omitempty"`
+	OutputPath string `json:"output_path,omitempty"` // relative to project root
+	RunID      string `json:"run_id,omitempty"`      // full transcript (GET /api/runs/{id}/log)
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at,omitempty"`
+}
+
+// RetryPolicy controls automatic re-queueing of failed runs.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts per traversal, 1 = no retry
+	Backoff     time.Duration // delay before the 1st retry, doubled each retry
+	RetryOn     map[string]bool
+}
+
+// ParseRetryOn parses a comma-separated list of retryable error types.
+// An empty string disables retries by error type.
+func ParseRetryOn(value string) ([]string, error) {
+	var errTypes []string
+	for _, f := range strings.Split(value, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		valid := false
+		for _, t := range RetryableErrorTypes {
+			if f == t {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("알 수 없는 에러 유형: %s (지원: %s)", f, strings.Join(RetryableErrorTypes, ", "))
+		}
+		errTypes = append(errTypes, f)
+	}
+	return errTypes, nil
+}
+
+// getRetryPolicy reads the retry policy from project local DB config.
+// Invalid values fall back to the defaults.
+func getRetryPolicy(localDB *db.DB) RetryPolicy {
+	config := make(map[string]string)
+	rows, err := localDB.Query(`SELECT key, value FROM config WHERE key IN ('retry_max_attempts', 'retry_backoff', 'retry_on')`)
+	if err == nil {
+		for rows.Next() {
+			var k, v string
+			if rows.Scan(&k, &v) == nil {
+				config[k] = v
+			}
+		}
+		rows.Close()
+	}
+
+	policy := RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		Backoff:     defaultRetryBackoff,
+		RetryOn:     make(map[string]bool),
+	}
+	if v, ok := config["retry_max_attempts"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 {
+			policy.MaxAttempts = n
+		}
+	}
+	if v, ok := config["retry_backoff"]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			policy.Backoff = d
+		}
+	}
+	retryOn := defaultRetryOn
+	if v, ok := config["retry_on"]; ok {
+		retryOn = v
+	}
+	if errTypes, err := ParseRetryOn(retryOn); err == nil {
+		for _, t := range errTypes {
+			policy.RetryOn[t] = true
+		}
+	}
+	return policy
+}
+
+// shouldRetry reports whether a failure after the given number of attempts is retried.
+func (p RetryPolicy) shouldRetry(errorType string, attempts int) bool {
+	return p.RetryOn[errorType] && attempts < p.MaxAttempts
+}
+
+// delay returns the backoff before the next attempt (exponential: Backoff × 2^(attempts-1)).
+func (p RetryPolicy) delay(attempts int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+	}
+	return d
+}
+
+// classifyRunError maps a Claude execution error to an error type.
+// A cancelled parent context is "cancelled"; absolute and idle timeouts are "timeout".
+func classifyRunError(ctx context.Context, err error) string {
+	if ctx.Err() != nil {
+		return "cancelled"
+	}
+	if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timeout") {
+		return "timeout"
+	}
+	return "exec_error"
+}
+
+// startAttempt records the start of a plan/run attempt and returns its ID and number.
+// runID links the attempt to its run transcript (.claribot/runs/{run-id}.log).
+func startAttempt(localDB *db.DB, taskID int, phase, runID string) (int64, int, error) {
+	var n int
+	if err := localDB.QueryRow(`SELECT COUNT(*) FROM task_attempts WHERE task_id = ? AND phase = ?`, taskID, phase).Scan(&n); err != nil {
+		return 0, 0, fmt.Errorf("task_attempts 조회 실패: %w", err)
+	}
+	n++
+	result, err := localDB.Exec(`
+		INSERT INTO task_attempts (task_id, phase, attempt, status, run_id, started_at)
+		VALUES (?, ?, ?, 'running', ?, ?)
+	`, taskID, phase, n, runID, db.TimeNow())
+	if err != nil {
+		return 0, 0, fmt.Errorf("task_attempts INSERT 실패: %w", err)
+	}
+	id, err := result.LastInsertId()
+	return id, n, err
+}
+
+// finishAttempt records the outcome of an attempt. The Claude output (if any) is
+// saved to .claribot/attempts/{task}-{phase}-{n}.log so every attempt keeps its own output.
+func finishAttempt(localDB *db.DB, projectPath string, attemptID int64, status string, exitCode *int, errorType, errMsg, output string) {
+	var taskID, n int
+	var phase string
+	if err := localDB.QueryRow(`SELECT task_id, phase, attempt FROM task_attempts WHERE id = ?`, attemptID).Scan(&taskID, &phase, &n); err != nil {
+		log.Printf("[Task] attempt 조회 실패 (id=%d): %v", attemptID, err)
+		return
+	}
+
+	outputPath := ""
+	if output != "" {
+		rel := filepath.Join(".claribot", attemptDirName, fmt.Sprintf("%d-%s-%d.log", taskID, phase, n))
+		full := filepath.Join(projectPath, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			log.Printf("[Task] attempt 디렉토리 생성 실패: %v", err)
+		} else if err := os.WriteFile(full, []byte(output), 0644); err != nil {
+			log.Printf("[Task] attempt 출력 저장 실패 (#%d): %v", taskID, err)
+		} else {
+			outputPath = rel
+		}
+	}
+
+	if _, err := localDB.Exec(`
+		UPDATE task_attempts SET status = ?, exit_code = ?, error_type = ?, error = ?, output_path = ?, finished_at = ?
+		WHERE id = ?
+	`, status, exitCode, errorType, errMsg, outputPath, db.TimeNow(), attemptID); err != nil {
+		log.Printf("[Task] attempt 갱신 실패 (id=%d): %v", attemptID, err)
+	}
+}
+
+// loadAttempts returns the attempt history of a task, oldest first.
+func loadAttempts(localDB *db.DB, taskID int) ([]Attempt, error) {
+	rows, err := localDB.Query(`
+		SELECT id, task_id, phase, attempt, status, exit_code, error_type, error, output_path, COALESCE(run_id, ''), started_at, COALESCE(finished_at, '')
+		FROM task_attempts WHERE task_id = ? ORDER BY id ASC
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task_attempts 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []Attempt
+	for rows.Next() {
+		var a Attempt
+		if err := rows.Scan(&a.ID, &a.TaskID, &a.Phase, &a.Attempt, &a.Status, &a.ExitCode, &a.ErrorType, &a.Error, &a.OutputPath, &a.RunID, &a.StartedAt, &a.FinishedAt); err != nil {
+			return nil, fmt.Errorf("task_attempts 스캔 실패: %w", err)
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+// formatAttempts renders the attempt history for task get.
+func formatAttempts(attempts []Attempt) string {
+	var sb strings.Builder
+	for _, a := range attempts {
+		sb.WriteString(fmt.Sprintf("\n  %s %s #%d %s", statusToIcon(a.Status), a.Phase, a.Attempt, a.StartedAt))
+		if a.FinishedAt != "" {
+			sb.WriteString(" → " + a.FinishedAt)
+		}
+		if a.ExitCode != nil && *a.ExitCode != 0 {
+			sb.WriteString(fmt.Sprintf(" (exit %d)", *a.ExitCode))
+		}
+		if a.ErrorType != "" {
+			sb.WriteString(" [" + a.ErrorType + "]")
+		}
+		if a.Error != "" {
+			sb.WriteString(": " + truncateLine(a.Error, 80))
+		}
+		if a.OutputPath != "" {
+			sb.WriteString("\n      " + a.OutputPath)
+		}
+		if a.RunID != "" {
+			sb.WriteString("\n      run " + a.RunID)
+		}
+	}
+	return sb.String()
+}
+
+// truncateLine returns the first line of s, cut to max runes.
+func truncateLine(s string, max int) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(s), "\n", 2)[0])
+	runes := []rune(line)
+	if len(runes) > max {
+		return string(runes[:max]) + "…"
+	}
+	return line
+}
+
+// retryEntry is a failed task waiting for its backoff to elapse.
+type retryEntry struct {
+	task Task
+	at   time.Time
+}
+
+// retryQueue tracks attempts per task within one traversal and holds
+// failed tasks until they are due for re-dispatch.
+type retryQueue struct {
+	policy   RetryPolicy
+	attempts map[int]int
+	waiting  []retryEntry
+}
+
+func newRetryQueue(policy RetryPolicy) *retryQueue {
+	return &retryQueue{policy: policy, attempts: make(map[int]int)}
+}
+
+// schedule counts a failed attempt and, if the policy allows, resets the task to
+// planned and queues it for retry. Returns the backoff and whether it was queued.
+func (q *retryQueue) schedule(ctx context.Context, projectPath string, t Task, errorType string) (time.Duration, bool) {
+	q.attempts[t.ID]++
+	if !q.policy.shouldRetry(errorType, q.attempts[t.ID]) {
+		return 0, false
+	}
+	if err := resetForRetry(projectPath, t.ID, traversalFromContext(ctx)); err != nil {
+		log.Printf("[Task] 재시도 준비 실패 (#%d): %v", t.ID, err)
+		return 0, false
+	}
+	d := q.policy.delay(q.attempts[t.ID])
+	q.waiting = append(q.waiting, retryEntry{task: t, at: time.Now().Add(d)})
+	return d, true
+}
+
+// succeeded counts a successful attempt.
+func (q *retryQueue) succeeded(taskID int) {
+	q.attempts[taskID]++
+}
+
+// due removes and returns the queued tasks whose backoff has elapsed.
+func (q *retryQueue) due(now time.Time) []Task {
+	var ready []Task
+	var rest []retryEntry
+	for _, e := range q.waiting {
+		if !e.at.After(now) {
+			ready = append(ready, e.task)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+	q.waiting = rest
+	return ready
+}
+
+// next returns the time the earliest queued task becomes due.
+func (q *retryQueue) next() (time.Time, bool) {
+	if len(q.waiting) == 0 {
+		return time.Time{}, false
+	}
+	earliest := q.waiting[0].at
+	for _, e := range q.waiting[1:] {
+		if e.at.Before(earliest) {
+			earliest = e.at
+		}
+	}
+	return earliest, true
+}
+
+// retryMessage formats the summary line for a re-queued task.
+func (q *retryQueue) retryMessage(t Task, d time.Duration, errMsg string) string {
+	return fmt.Sprintf("🔁 #%d %s: 재시도 예정 (%d/%d, %s 후): %s", t.ID, t.Title, q.attempts[t.ID]+1, q.policy.MaxAttempts, d, truncateLine(errMsg, 80))
+}
+
+// resetForRetry moves a failed task back to planned so it can be dispatched again.
+// travID is the traversal retrying the task (recorded on the status change).
+func resetForRetry(projectPath string, taskID int, travID int64) error {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return err
+	}
+	defer localDB.Close()
+
+	res, err := localDB.Exec(`UPDATE tasks SET status = 'planned', updated_at = ? WHERE id = ? AND status = 'failed'`, db.TimeNow(), taskID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		recordEvent(localDB, taskID, "failed", "planned", ActorCycle, travID)
+	}
+	return updateTaskFileStatus(projectPath, taskID, "planned")
+}
+
+// waitUntil blocks until t or until ctx is cancelled. Returns false if cancelled.
+func waitUntil(ctx context.Context, t time.Time) bool {
+	d := time.Until(t)
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,182 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestParseRetryOn(t *testing.T) {
+	errTypes, err := ParseRetryOn("timeout, exit_error")
+	if err != nil {
+		t.Fatalf("ParseRetryOn failed: %v", err)
+	}
+	if strings.Join(errTypes, ",") != "timeout,exit_error" {
+		t.Errorf("Expected [timeout exit_error], got %v", errTypes)
+	}
+
+	if errTypes, err := ParseRetryOn(""); err != nil || len(errTypes) != 0 {
+		t.Errorf("Expected empty list, got %v (err=%v)", errTypes, err)
+	}
+	if _, err := ParseRetryOn("timeout,auth_error"); err == nil {
+		t.Error("Expected error for auth_error")
+	}
+}
+
+func TestGetRetryPolicy(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer localDB.Close()
+
+	policy := getRetryPolicy(localDB)
+	if policy.MaxAttempts != defaultRetryMaxAttempts || policy.Backoff != defaultRetryBackoff {
+		t.Errorf("Expected default policy, got %+v", policy)
+	}
+	if !policy.RetryOn["timeout"] || !policy.RetryOn["exec_error"] || policy.RetryOn["exit_error"] {
+		t.Errorf("Unexpected default retry_on: %v", policy.RetryOn)
+	}
+
+	for k, v := range map[string]string{"retry_max_attempts": "3", "retry_backoff": "1s", "retry_on": "exit_error"} {
+		localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES (?, ?, ?)", k, v, db.TimeNow())
+	}
+	policy = getRetryPolicy(localDB)
+	if policy.MaxAttempts != 3 || policy.Backoff != time.Second || !policy.RetryOn["exit_error"] || policy.RetryOn["timeout"] {
+		t.Errorf("Unexpected configured policy: %+v", policy)
+	}
+
+	// Exponential backoff
+	if policy.delay(1) != time.Second || policy.delay(3) != 4*time.Second {
+		t.Errorf("Unexpected delays: %v %v", policy.delay(1), policy.delay(3))
+	}
+	if !policy.shouldRetry("exit_error", 2) || policy.shouldRetry("exit_error", 3) || policy.shouldRetry("auth_error", 1) {
+		t.Error("Unexpected shouldRetry result")
+	}
+}
+
+func TestClassifyRunError(t *testing.T) {
+	ctx := context.Background()
+	if got := classifyRunError(ctx, fmt.Errorf("execution cancelled: %w", context.DeadlineExceeded)); got != "timeout" {
+		t.Errorf("Expected timeout, got %s", got)
+	}
+	if got := classifyRunError(ctx, errors.New("idle timeout: no output for 5m0s")); got != "timeout" {
+		t.Errorf("Expected timeout, got %s", got)
+	}
+	if got := classifyRunError(ctx, errors.New("failed to start claude")); got != "exec_error" {
+		t.Errorf("Expected exec_error, got %s", got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if got := classifyRunError(cancelled, context.Canceled); got != "cancelled" {
+		t.Errorf("Expected cancelled, got %s", got)
+	}
+}
+
+func TestAttemptHistory(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Flaky task", nil, "spec")
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer localDB.Close()
+
+	id1, n1, err := startAttempt(localDB, 1, "run", "")
+	if err != nil || n1 != 1 {
+		t.Fatalf("startAttempt failed: n=%d err=%v", n1, err)
+	}
+	exitCode := 1
+	finishAttempt(localDB, projectPath, id1, "failed", &exitCode, "exit_error", "boom", "first output")
+
+	id2, n2, _ := startAttempt(localDB, 1, "run", "")
+	if n2 != 2 {
+		t.Errorf("Expected attempt #2, got %d", n2)
+	}
+	exitCode = 0
+	finishAttempt(localDB, projectPath, id2, "done", &exitCode, "", "", "second output")
+
+	attempts, err := loadAttempts(localDB, 1)
+	if err != nil {
+		t.Fatalf("loadAttempts failed: %v", err)
+	}
+	if len(attempts) != 2 || attempts[0].Status != "failed" || attempts[1].Status != "done" {
+		t.Fatalf("Unexpected attempts: %+v", attempts)
+	}
+	if attempts[0].ErrorType != "exit_error" || attempts[0].ExitCode == nil || *attempts[0].ExitCode != 1 || attempts[0].FinishedAt == "" {
+		t.Errorf("Unexpected first attempt: %+v", attempts[0])
+	}
+
+	// Each attempt keeps its own output
+	for i, want := range []string{"first output", "second output"} {
+		data, err := os.ReadFile(filepath.Join(projectPath, attempts[i].OutputPath))
+		if err != nil || string(data) != want {
+			t.Errorf("Attempt %d output: got %q (err=%v)", i+1, data, err)
+		}
+	}
+
+	// task get shows the history
+	result := Get(projectPath, "1")
+	if got := result.Data.(*Task); len(got.Attempts) != 2 {
+		t.Errorf("Expected 2 attempts on task, got %d", len(got.Attempts))
+	}
+	if !strings.Contains(result.Message, "Attempts") || !strings.Contains(result.Message, "exit_error") {
+		t.Errorf("Expected attempt history in message: %s", result.Message)
+	}
+}
+
+func TestRetryQueue(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Flaky task", nil, "spec")
+	localDB, _ := db.OpenLocal(projectPath)
+	localDB.Exec(`UPDATE tasks SET status = 'failed' WHERE id = 1`)
+	localDB.Close()
+
+	q := newRetryQueue(RetryPolicy{MaxAttempts: 2, Backoff: time.Hour, RetryOn: map[string]bool{"timeout": true}})
+	task := Task{ID: 1, Title: "Flaky task"}
+
+	if _, ok := q.schedule(context.Background(), projectPath, task, "exit_error"); ok {
+		t.Error("exit_error should not be retried")
+	}
+
+	q = newRetryQueue(q.policy)
+	d, ok := q.schedule(context.Background(), projectPath, task, "timeout")
+	if !ok || d != time.Hour {
+		t.Fatalf("Expected retry after 1h, got %v %v", d, ok)
+	}
+	if got := Get(projectPath, "1").Data.(*Task); got.Status != "planned" {
+		t.Errorf("Expected task reset to planned, got %s", got.Status)
+	}
+
+	if due := q.due(time.Now()); len(due) != 0 {
+		t.Errorf("Expected nothing due yet, got %v", due)
+	}
+	at, waiting := q.next()
+	if !waiting {
+		t.Fatal("Expected a queued retry")
+	}
+	if due := q.due(at); len(due) != 1 || due[0].ID != 1 {
+		t.Errorf("Expected task #1 due, got %v", due)
+	}
+
+	// Attempts are exhausted after MaxAttempts
+	if _, ok := q.schedule(context.Background(), projectPath, task, "timeout"); ok {
+		t.Error("Expected no retry after max attempts")
+	}
+}
@@ -2,19 +2,55 @@ package task
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"parkjunwoo.com/claribot/internal/db"
 )
 
+// defaultContextMapLimit is the task count above which a labeled task gets a
+// context map limited to its labels (project config context_map_limit, 0 = never).
+const defaultContextMapLimit = 200
+
 // BuildContextMap builds a text summary of the entire task tree.
 // Used as lightweight context instead of injecting full related task content.
 func BuildContextMap(localDB *db.DB) (string, error) {
+	return buildContextMap(localDB, "", nil)
+}
+
+// BuildContextMapForLabels builds the tree summary limited to tasks having any of
+// the labels, plus their ancestors so the tree stays readable.
+func BuildContextMapForLabels(localDB *db.DB, labels []string) (string, error) {
+	if len(labels) == 0 {
+		return BuildContextMap(localDB)
+	}
+	args := make([]interface{}, len(labels))
+	for i, l := range labels {
+		args[i] = l
+	}
+	where := `WHERE id IN (
+		WITH RECURSIVE scope(id) AS (
+			SELECT task_id FROM task_labels WHERE label IN (?` + strings.Repeat(", ?", len(labels)-1) + `)
+			UNION
+			SELECT t.parent_id FROM tasks t JOIN scope s ON t.id = s.id WHERE t.parent_id IS NOT NULL
+		)
+		SELECT id FROM scope
+	)`
+	contextMap, err := buildContextMap(localDB, where, args)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(라벨 %s 범위만 표시)\n", formatLabels(labels)) + contextMap, nil
+}
+
+// buildContextMap formats the tasks matching where (empty = all) as an indented list.
+func buildContextMap(localDB *db.DB, where string, args []interface{}) (string, error) {
 	rows, err := localDB.Query(`
 		SELECT id, parent_id, title, status, depth
 		FROM tasks
+		`+where+`
 		ORDER BY depth ASC, id ASC
-	`)
+	`, args...)
 	if err != nil {
 		return "", fmt.Errorf("task 조회 실패: %w", err)
 	}
@@ -54,3 +90,28 @@ func BuildContextMap(localDB *db.DB) (string, error) {
 
 	return sb.String(), nil
 }
+
+// getContextMapLimit reads the context_map_limit config (default 200, 0 disables label scoping).
+func getContextMapLimit(localDB *db.DB) int {
+	var val string
+	if err := localDB.QueryRow("SELECT value FROM config WHERE key = 'context_map_limit'").Scan(&val); err == nil {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultContextMapLimit
+}
+
+// buildTaskContextMap returns the context map for a task: the whole tree, or only the
+// task's labels when the tree has more tasks than context_map_limit.
+func buildTaskContextMap(localDB *db.DB, taskID int) (string, error) {
+	if limit := getContextMapLimit(localDB); limit > 0 {
+		var count int
+		if err := localDB.QueryRow(`SELECT COUNT(*) FROM tasks`).Scan(&count); err == nil && count > limit {
+			if labels, err := loadLabels(localDB, taskID); err == nil && len(labels) > 0 {
+				return BuildContextMapForLabels(localDB, labels)
+			}
+		}
+	}
+	return BuildContextMap(localDB)
+}
@@ -15,6 +15,22 @@ const maxCycleIterations = 10
 
 // Cycle runs full cycle: 1회차 (Plan 생성, 반복) + 2회차 (실행)
 func Cycle(projectPath string) types.Result {
+	return cycle(projectPath, 0, "")
+}
+
+// CycleSubtree runs the full cycle on the subtree of a root task only
+// (the root and its descendants; the rest of the project is untouched).
+func CycleSubtree(projectPath, id string) types.Result {
+	rootID, errResult := resolveScope(projectPath, id)
+	if errResult != nil {
+		return *errResult
+	}
+	return cycle(projectPath, rootID, "")
+}
+
+// cycle runs the full cycle over the whole project (rootID 0) or a subtree.
+// trigger is recorded on the traversal ("resume:<id>" when restarted after an interruption).
+func cycle(projectPath string, rootID int, trigger string) types.Result {
 	// Check if already running for this project
 	if IsCycleRunning(projectPath) {
 		return types.Result{
@@ -23,10 +39,25 @@ func Cycle(projectPath string) types.Result {
 		}
 	}
 
-	ResetCancel()
-	SetBatchMode(true)
-	defer SetBatchMode(false)
-	ctx, cancel := context.WithCancel(context.Background())
+	// Batch mode: per-task commits are replaced by one commit at the end
+	ctx, cancel := context.WithCancel(withBatch(context.Background()))
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		cancel()
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	// Insert traversal record (progress is persisted under its ID for restart recovery)
+	travID, travErr := insertTraversal(localDB, "cycle", scopeTarget(rootID), trigger)
+	if travErr != nil {
+		log.Printf("[Task] traversal INSERT 실패: %v", travErr)
+	}
+	ctx = withTraversal(ctx, travID)
 
 	startTime := time.Now()
 	SetCycleState(projectPath, CycleState{
@@ -34,6 +65,8 @@ func Cycle(projectPath string) types.Result {
 		Type:        "cycle",
 		StartedAt:   startTime,
 		ProjectPath: projectPath,
+		RootID:      rootID,
+		TraversalID: travID,
 	})
 	SetCycleCancel(projectPath, cancel)
 	defer func() {
@@ -41,42 +74,77 @@ func Cycle(projectPath string) types.Result {
 		ClearCycleState(projectPath)
 	}()
 
-	localDB, err := db.OpenLocal(projectPath)
-	if err != nil {
-		return types.Result{
-			Success: false,
-			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+	// Update traversal record on every exit path
+	travStatus := "failed"
+	defer func() {
+		if travErr != nil {
+			return
 		}
-	}
-	defer localDB.Close()
+		status := travStatus
+		if ctx.Err() != nil {
+			status = "cancelled"
+		}
+		total, success, failed := countItems(localDB, travID)
+		finishTraversal(localDB, travID, status, total, success, failed)
+	}()
 
 	var messages []string
 	projectID := getProjectID(projectPath)
+	label := scopeLabel(rootID)
+	scope, scopeArgs := scopeFilter(rootID)
 
 	// Phase 1: Plan all todo tasks (반복 순회 - subdivide로 생성된 신규 todo 포함)
-	for i := 0; i < maxCycleIterations; i++ {
-		if IsCancelled() || ctx.Err() != nil {
-			messages = append(messages, "🛑 중단 요청으로 Plan 순회 중단")
-			break
-		}
+	// Returns false on auth error. quiet skips the "nothing to plan" line.
+	planPhase := func(quiet bool) bool {
+		for i := 0; i < maxCycleIterations; i++ {
+			if ctx.Err() != nil {
+				messages = append(messages, "🛑 중단 요청으로 Plan 순회 중단")
+				break
+			}
 
-		var todoCount int
-		localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'todo'`).Scan(&todoCount)
+			var todoCount int
+			localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'todo'`+scope, scopeArgs...).Scan(&todoCount)
 
-		if todoCount == 0 {
-			if i == 0 {
-				messages = append(messages, "📋 Plan 순회: Plan 생성할 작업 없음")
+			if todoCount == 0 {
+				if i == 0 && !quiet {
+					messages = append(messages, "📋 Plan 순회: Plan 생성할 작업 없음")
+				}
+				break
 			}
-			break
-		}
 
-		UpdatePhase(projectPath, "plan", todoCount)
-		messages = append(messages, fmt.Sprintf("📋 Plan 순회 %d회차: %d개 작업 Plan 생성 시작", i+1, todoCount))
-		planResult := planAllInternal(ctx, projectPath)
-		messages = append(messages, planResult.Message)
+			UpdatePhase(projectPath, "plan", todoCount)
+			messages = append(messages, fmt.Sprintf("📋 Plan 순회 %d회차: %d개 작업 Plan 생성 시작", i+1, todoCount))
+			planResult := planAllInternal(ctx, projectPath, rootID)
+			messages = append(messages, planResult.Message)
+
+			// Only abort on auth error; other failures (empty spec etc.) continue
+			if !planResult.Success && planResult.ErrorType == "auth_error" {
+				return false
+			}
+		}
+		return true
+	}
+	if !planPhase(false) {
+		return types.Result{
+			Success: false,
+			Message: strings.Join(messages, "\n\n"),
+		}
+	}
 
-		// Only abort on auth error; other failures (empty spec etc.) continue
-		if !planResult.Success && planResult.ErrorType == "auth_error" {
+	// Phase 1.5: re-plan failed tasks with their error report (replan_auto)
+	if getReplanConfig(localDB).Auto && ctx.Err() == nil {
+		replanned, replanResult := replanFailed(ctx, projectPath, rootID)
+		if replanResult.Message != "" {
+			messages = append(messages, replanResult.Message)
+		}
+		if !replanResult.Success && replanResult.ErrorType == "auth_error" {
+			return types.Result{
+				Success: false,
+				Message: strings.Join(messages, "\n\n"),
+			}
+		}
+		// A re-plan may have split a task: plan the new children before running
+		if replanned > 0 && !planPhase(true) {
 			return types.Result{
 				Success: false,
 				Message: strings.Join(messages, "\n\n"),
@@ -85,11 +153,11 @@ func Cycle(projectPath string) types.Result {
 	}
 
 	// Check cancel before Phase 2
-	if IsCancelled() || ctx.Err() != nil {
+	if ctx.Err() != nil {
 		messages = append(messages, "🛑 중단 요청으로 Run 순회 건너뜀")
 		if globalNotifier != nil {
-			notification := fmt.Sprintf("🛑 [%s] Cycle 중단됨\n소요: %s\n%s",
-				projectID, formatDuration(time.Since(startTime)), strings.Join(messages, "\n"))
+			notification := fmt.Sprintf("🛑 [%s] %sCycle 중단됨\n소요: %s\n%s",
+				projectID, label, formatDuration(time.Since(startTime)), strings.Join(messages, "\n"))
 			globalNotifier(nil, notification)
 		}
 		return types.Result{
@@ -98,14 +166,17 @@ func Cycle(projectPath string) types.Result {
 		}
 	}
 
+	// Plans waiting for approval are left for the reviewer (run only takes planned tasks)
+	reviewCount, pendingReviews := formatPendingReviews(localDB, rootID)
+
 	// Phase 2: Run all planned tasks
 	var plannedCount int
-	localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'planned'`).Scan(&plannedCount)
+	localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'planned'`+scope, scopeArgs...).Scan(&plannedCount)
 
 	if plannedCount > 0 {
 		UpdatePhase(projectPath, "run", plannedCount)
 		messages = append(messages, fmt.Sprintf("🔄 2회차 순회: %d개 작업 실행 시작", plannedCount))
-		runResult := runAllInternal(ctx, projectPath)
+		runResult := runAllInternal(ctx, projectPath, rootID)
 		messages = append(messages, runResult.Message)
 
 		// Only abort on auth error; individual task failures continue
@@ -120,7 +191,7 @@ func Cycle(projectPath string) types.Result {
 	}
 
 	// Auto-sync: 파일 ↔ DB 일치 확인
-	if syncResult, err := Sync(projectPath); err != nil {
+	if syncResult, err := syncTasks(ctx, projectPath, nil, ActorSync); err != nil {
 		log.Printf("[Task] auto-sync 실패: %v", err)
 	} else if syncResult.Inserted+syncResult.Updated+syncResult.Deleted > 0 {
 		log.Printf("[Task] auto-sync: +%d ~%d -%d", syncResult.Inserted, syncResult.Updated, syncResult.Deleted)
@@ -128,15 +199,23 @@ func Cycle(projectPath string) types.Result {
 
 	// Summary
 	var doneCount, failedCount int
-	localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'done'`).Scan(&doneCount)
-	localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'failed'`).Scan(&failedCount)
-	gitCommitBatch(projectPath, fmt.Sprintf("cycle: %d done, %d failed", doneCount, failedCount))
-
-	messages = append(messages, fmt.Sprintf("🏁 Cycle 완료: done %d개, failed %d개", doneCount, failedCount))
+	// Leaves only: rolled-up parents are not counted twice
+	localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'done' AND is_leaf = 1`+scope, scopeArgs...).Scan(&doneCount)
+	localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = 'failed' AND is_leaf = 1`+scope, scopeArgs...).Scan(&failedCount)
+	gitCommitBatch(projectPath, fmt.Sprintf("cycle%s: %d done, %d failed", scopeCommitSuffix(rootID), doneCount, failedCount))
+
+	travStatus = "done"
+	messages = append(messages, fmt.Sprintf("🏁 %sCycle 완료: done %d개, failed %d개", label, doneCount, failedCount))
+	if pendingReviews != "" {
+		messages = append(messages, pendingReviews)
+	}
 
 	if globalNotifier != nil {
-		notification := fmt.Sprintf("🏁 [%s] Cycle 순회 완료\n소요: %s\n결과: done %d개, failed %d개",
-			projectID, formatDuration(time.Since(startTime)), doneCount, failedCount)
+		notification := fmt.Sprintf("🏁 [%s] %sCycle 순회 완료\n소요: %s\n결과: done %d개, failed %d개",
+			projectID, label, formatDuration(time.Since(startTime)), doneCount, failedCount)
+		if reviewCount > 0 {
+			notification += fmt.Sprintf("\n🔍 승인 대기: %d개", reviewCount)
+		}
 		globalNotifier(nil, notification)
 	}
 
@@ -18,10 +18,12 @@ type CycleState struct {
 	CurrentTaskID int       `json:"current_task_id"`
 	ProjectPath   string    `json:"project_path"`
 	ProjectID     string    `json:"project_id"`
+	RootID        int       `json:"root_id,omitempty"` // subtree root (0 = whole project)
 	ActiveWorkers int       `json:"active_workers"`
-	Phase         string    `json:"phase"`        // "plan", "run"
-	TargetTotal   int       `json:"target_total"` // total number of tasks in current phase
-	Completed     int       `json:"completed"`    // number of tasks completed in current phase
+	Phase         string    `json:"phase"`                  // "plan", "run"
+	TargetTotal   int       `json:"target_total"`           // total number of tasks in current phase
+	Completed     int       `json:"completed"`              // number of tasks completed in current phase
+	TraversalID   int64     `json:"traversal_id,omitempty"` // traversals row (progress persisted in cycle_state)
 }
 
 // activeWorkers tracks the number of currently running parallel workers (global)
@@ -42,9 +44,11 @@ func getProjectID(projectPath string) string {
 // SetCycleState sets the cycle state for a specific project
 func SetCycleState(projectPath string, state CycleState) {
 	cycleMu.Lock()
-	defer cycleMu.Unlock()
 	state.ProjectID = getProjectID(projectPath)
 	projectCycleStates[projectPath] = &state
+	snapshot := state
+	cycleMu.Unlock()
+	saveCycleState(projectPath, snapshot)
 }
 
 // SetCycleCancel stores the cancel function for a specific project's cycle
@@ -83,9 +87,14 @@ func CancelAllCycles() {
 // ClearCycleState clears the cycle state for a specific project
 func ClearCycleState(projectPath string) {
 	cycleMu.Lock()
-	defer cycleMu.Unlock()
+	var travID int64
+	if state, ok := projectCycleStates[projectPath]; ok {
+		travID = state.TraversalID
+	}
 	delete(projectCycleStates, projectPath)
 	delete(projectCycleCancels, projectPath)
+	cycleMu.Unlock()
+	deleteCycleState(projectPath, travID)
 }
 
 // GetCycleState returns a copy of the cycle state for a specific project
@@ -113,31 +122,39 @@ func GetAllCycleStates() []CycleState {
 
 // UpdateCurrentTask updates only the CurrentTaskID field for a project
 func UpdateCurrentTask(projectPath string, taskID int) {
-	cycleMu.Lock()
-	defer cycleMu.Unlock()
-	if state, ok := projectCycleStates[projectPath]; ok {
+	updateCycleState(projectPath, func(state *CycleState) {
 		state.CurrentTaskID = taskID
-	}
+	})
 }
 
 // UpdatePhase updates the Phase, TargetTotal and resets Completed for a project
 func UpdatePhase(projectPath string, phase string, targetTotal int) {
-	cycleMu.Lock()
-	defer cycleMu.Unlock()
-	if state, ok := projectCycleStates[projectPath]; ok {
+	updateCycleState(projectPath, func(state *CycleState) {
 		state.Phase = phase
 		state.TargetTotal = targetTotal
 		state.Completed = 0
-	}
+	})
 }
 
 // IncrementCompleted increments the Completed counter by 1 for a project
 func IncrementCompleted(projectPath string) {
-	cycleMu.Lock()
-	defer cycleMu.Unlock()
-	if state, ok := projectCycleStates[projectPath]; ok {
+	updateCycleState(projectPath, func(state *CycleState) {
 		state.Completed++
+	})
+}
+
+// updateCycleState applies fn to a project's cycle state and persists the result.
+func updateCycleState(projectPath string, fn func(state *CycleState)) {
+	cycleMu.Lock()
+	state, ok := projectCycleStates[projectPath]
+	if !ok {
+		cycleMu.Unlock()
+		return
 	}
+	fn(state)
+	snapshot := *state
+	cycleMu.Unlock()
+	saveCycleState(projectPath, snapshot)
 }
 
 // UpdateActiveWorkers adjusts the active worker count by delta (+1 or -1)
@@ -190,6 +207,7 @@ type CycleStatusInfo struct {
 	CurrentTaskID int
 	ProjectPath   string
 	ProjectID     string
+	RootID        int // subtree root (0 = whole project)
 	ActiveWorkers int
 	Phase         string // "plan", "run"
 	TargetTotal   int    // total number of tasks in current phase
@@ -214,6 +232,7 @@ func GetCycleStatus() CycleStatusInfo {
 		CurrentTaskID: state.CurrentTaskID,
 		ProjectPath:   state.ProjectPath,
 		ProjectID:     state.ProjectID,
+		RootID:        state.RootID,
 		ActiveWorkers: int(activeWorkers.Load()),
 		Phase:         state.Phase,
 		TargetTotal:   state.TargetTotal,
@@ -251,6 +270,7 @@ func GetAllCycleStatuses() []CycleStatusInfo {
 			CurrentTaskID: state.CurrentTaskID,
 			ProjectPath:   state.ProjectPath,
 			ProjectID:     state.ProjectID,
+			RootID:        state.RootID,
 			ActiveWorkers: state.ActiveWorkers,
 			Phase:         state.Phase,
 			TargetTotal:   state.TargetTotal,
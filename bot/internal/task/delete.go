@@ -1,6 +1,7 @@
 package task
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -13,6 +14,7 @@ import (
 
 // Delete deletes a task
 func Delete(projectPath, id string, confirmed bool) types.Result {
+	ctx := context.Background() // not part of a traversal: commits on its own
 	localDB, err := db.OpenLocal(projectPath)
 	if err != nil {
 		return types.Result{
@@ -46,6 +48,18 @@ func Delete(projectPath, id string, confirmed bool) types.Result {
 		}
 	}
 
+	// Collect dependents before delete (task_deps rows cascade, frontmatter must be updated)
+	var dependents []int
+	if rows, err := localDB.Query(`SELECT task_id FROM task_deps WHERE depends_on = ?`, id); err == nil {
+		for rows.Next() {
+			var depID int
+			if rows.Scan(&depID) == nil {
+				dependents = append(dependents, depID)
+			}
+		}
+		rows.Close()
+	}
+
 	// Delete
 	_, err = localDB.Exec("DELETE FROM tasks WHERE id = ?", id)
 	if err != nil {
@@ -62,15 +76,40 @@ func Delete(projectPath, id string, confirmed bool) types.Result {
 		PlanFilePath(projectPath, taskID),
 		ReportFilePath(projectPath, taskID),
 		ErrorFilePath(projectPath, taskID),
+		VerifyFilePath(projectPath, taskID),
+		DiffFilePath(projectPath, taskID),
+		ReviewFilePath(projectPath, taskID),
 	} {
 		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
 			log.Printf("[Task] 파일 삭제 실패 (%s): %v", f, err)
 		}
 	}
-	gitCommitTask(projectPath, taskID, "deleted")
+	gitCommitTask(ctx, projectPath, taskID, "deleted")
+	// Descendants were removed by cascade: drop every index entry and event without a task
+	if searchAvailable(localDB) {
+		localDB.Exec(`DELETE FROM task_search WHERE task_id NOT IN (SELECT id FROM tasks)`)
+	}
+	localDB.Exec(`DELETE FROM task_events WHERE task_id NOT IN (SELECT id FROM tasks)`)
+
+	// Remove the deleted task from dependents' depends_on
+	for _, depID := range dependents {
+		deps, err := loadDependencies(localDB, depID)
+		if err != nil {
+			continue
+		}
+		if err := updateTaskFileDependsOn(projectPath, depID, deps); err != nil {
+			log.Printf("[Task] depends_on 파일 업데이트 실패 (#%d): %v", depID, err)
+		}
+		gitCommitTask(ctx, projectPath, depID, "depends_on updated")
+	}
+
+	msg := fmt.Sprintf("작업 삭제됨: #%s %s", id, t.Title)
+	if rollups := formatRollups(rollUp(ctx, localDB, projectPath)); rollups != "" {
+		msg += "\n" + rollups
+	}
 
 	return types.Result{
 		Success: true,
-		Message: fmt.Sprintf("작업 삭제됨: #%s %s", id, t.Title),
+		Message: msg,
 	}
 }
@@ -0,0 +1,263 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+// ParseDependsOn parses a dependency list like "3,5 7" or "#3, #5" into task IDs.
+// An empty string returns an empty (non-nil) slice, meaning "clear dependencies".
+func ParseDependsOn(value string) ([]int, error) {
+	ids := []int{}
+	seen := make(map[int]bool)
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	for _, f := range fields {
+		f = strings.TrimPrefix(strings.TrimSpace(f), "#")
+		if f == "" {
+			continue
+		}
+		id, err := strconv.Atoi(f)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("잘못된 작업 ID: %s", f)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// formatDependsOn formats task IDs as "#3, #5" for display.
+func formatDependsOn(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// loadDepMap returns all dependency edges: task ID → IDs it depends on.
+func loadDepMap(localDB *db.DB) (map[int][]int, error) {
+	rows, err := localDB.Query(`SELECT task_id, depends_on FROM task_deps ORDER BY task_id, depends_on`)
+	if err != nil {
+		return nil, fmt.Errorf("task_deps 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	depMap := make(map[int][]int)
+	for rows.Next() {
+		var taskID, dependsOn int
+		if err := rows.Scan(&taskID, &dependsOn); err != nil {
+			return nil, fmt.Errorf("task_deps 스캔 실패: %w", err)
+		}
+		depMap[taskID] = append(depMap[taskID], dependsOn)
+	}
+	return depMap, rows.Err()
+}
+
+// loadDependencies returns the IDs a single task depends on.
+func loadDependencies(localDB *db.DB, taskID int) ([]int, error) {
+	rows, err := localDB.Query(`SELECT depends_on FROM task_deps WHERE task_id = ? ORDER BY depends_on`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task_deps 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("task_deps 스캔 실패: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// findDepCycle checks whether adding edges taskID → deps would create a cycle.
+// Returns the cycle path (e.g. [3, 5, 3]) or nil if the graph stays acyclic.
+func findDepCycle(depMap map[int][]int, taskID int, deps []int) []int {
+	for _, dep := range deps {
+		if dep == taskID {
+			return []int{taskID, taskID}
+		}
+		// A cycle exists if taskID is reachable from dep
+		visited := make(map[int]bool)
+		if path := depPath(depMap, dep, taskID, visited); path != nil {
+			return append([]int{taskID}, path...)
+		}
+	}
+	return nil
+}
+
+// depPath returns the dependency path from → ... → to, or nil if unreachable.
+func depPath(depMap map[int][]int, from, to int, visited map[int]bool) []int {
+	if from == to {
+		return []int{to}
+	}
+	if visited[from] {
+		return nil
+	}
+	visited[from] = true
+	for _, next := range depMap[from] {
+		if path := depPath(depMap, next, to, visited); path != nil {
+			return append([]int{from}, path...)
+		}
+	}
+	return nil
+}
+
+// validateDependencies checks that all deps exist and that taskID → deps adds no cycle.
+func validateDependencies(localDB *db.DB, taskID int, deps []int) error {
+	for _, dep := range deps {
+		var exists int
+		if err := localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE id = ?`, dep).Scan(&exists); err != nil {
+			return fmt.Errorf("선행 작업 조회 실패: %w", err)
+		}
+		if exists == 0 {
+			return fmt.Errorf("선행 작업을 찾을 수 없습니다: #%d", dep)
+		}
+	}
+
+	depMap, err := loadDepMap(localDB)
+	if err != nil {
+		return err
+	}
+	delete(depMap, taskID) // existing edges of taskID are being replaced
+	if cycle := findDepCycle(depMap, taskID, deps); cycle != nil {
+		parts := make([]string, len(cycle))
+		for i, id := range cycle {
+			parts[i] = fmt.Sprintf("#%d", id)
+		}
+		return fmt.Errorf("순환 의존성: %s", strings.Join(parts, " → "))
+	}
+	return nil
+}
+
+// SetDependencies replaces the dependencies of a task in the DB after validation.
+// The caller is responsible for mirroring the change into the task file frontmatter.
+func SetDependencies(localDB *db.DB, taskID int, deps []int) error {
+	if err := validateDependencies(localDB, taskID, deps); err != nil {
+		return err
+	}
+
+	tx, err := localDB.Begin()
+	if err != nil {
+		return fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM task_deps WHERE task_id = ?`, taskID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("task_deps 삭제 실패: %w", err)
+	}
+	now := db.TimeNow()
+	for _, dep := range deps {
+		if _, err := tx.Exec(`INSERT INTO task_deps (task_id, depends_on, created_at) VALUES (?, ?, ?)`, taskID, dep, now); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("task_deps 저장 실패: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// updateTaskFileDependsOn reads existing task.md, updates depends_on, and writes back.
+// If file doesn't exist, does nothing (returns nil).
+func updateTaskFileDependsOn(projectPath string, id int, deps []int) error {
+	tc, err := ReadTaskContent(projectPath, id)
+	if err != nil {
+		return nil
+	}
+	tc.Frontmatter.DependsOn = deps
+	return WriteTaskContent(projectPath, id, tc.Frontmatter, tc.Title, tc.Body)
+}
+
+// sameDeps reports whether two dependency lists contain the same IDs (order-insensitive).
+func sameDeps(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := append([]int(nil), a...)
+	sb := append([]int(nil), b...)
+	sort.Ints(sa)
+	sort.Ints(sb)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// unmetDependencies returns the dependencies of taskID that are not yet done.
+func unmetDependencies(localDB *db.DB, taskID int) ([]int, error) {
+	rows, err := localDB.Query(`
+		SELECT d.depends_on FROM task_deps d
+		JOIN tasks t ON t.id = d.depends_on
+		WHERE d.task_id = ? AND t.status != 'done'
+		ORDER BY d.depends_on
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task_deps 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("task_deps 스캔 실패: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// depsSatisfied reports whether every dependency of taskID has status done.
+// statusMap holds the latest known status of each task.
+func depsSatisfied(taskID int, depMap map[int][]int, statusMap map[int]string) bool {
+	for _, dep := range depMap[taskID] {
+		if statusMap[dep] != "done" {
+			return false
+		}
+	}
+	return true
+}
+
+// loadStatusMap returns the current status of every task.
+func loadStatusMap(localDB *db.DB) (map[int]string, error) {
+	rows, err := localDB.Query(`SELECT id, status FROM tasks`)
+	if err != nil {
+		return nil, fmt.Errorf("tasks 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	statusMap := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var status string
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, fmt.Errorf("tasks 스캔 실패: %w", err)
+		}
+		statusMap[id] = status
+	}
+	return statusMap, rows.Err()
+}
+
+// blockedMessage formats a summary line for a task that was not dispatched
+// because its dependencies were never completed.
+func blockedMessage(t Task, depMap map[int][]int, statusMap map[int]string) string {
+	var unmet []int
+	for _, dep := range depMap[t.ID] {
+		if statusMap[dep] != "done" {
+			unmet = append(unmet, dep)
+		}
+	}
+	return fmt.Sprintf("⛔ #%d %s: 선행 작업 미완료 (%s)", t.ID, t.Title, formatDependsOn(unmet))
+}
@@ -0,0 +1,266 @@
+package task
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestParseDependsOn(t *testing.T) {
+	ids, err := ParseDependsOn("5, #3 5,7")
+	if err != nil {
+		t.Fatalf("ParseDependsOn failed: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 3 || ids[1] != 5 || ids[2] != 7 {
+		t.Errorf("Expected [3 5 7], got %v", ids)
+	}
+
+	ids, err = ParseDependsOn("")
+	if err != nil || ids == nil || len(ids) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %v (err=%v)", ids, err)
+	}
+
+	if _, err := ParseDependsOn("3,abc"); err == nil {
+		t.Error("Expected error for non-numeric ID")
+	}
+}
+
+func TestAddWithDeps(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Create table", nil, "spec")
+	result := AddWithDeps(projectPath, "Wire API handler", nil, "spec", []int{1})
+	if !result.Success {
+		t.Fatalf("AddWithDeps failed: %s", result.Message)
+	}
+
+	// Frontmatter mirrors the DB
+	tc, err := ReadTaskContent(projectPath, 2)
+	if err != nil {
+		t.Fatalf("ReadTaskContent failed: %v", err)
+	}
+	if len(tc.Frontmatter.DependsOn) != 1 || tc.Frontmatter.DependsOn[0] != 1 {
+		t.Errorf("Expected depends_on [1] in frontmatter, got %v", tc.Frontmatter.DependsOn)
+	}
+
+	got := Get(projectPath, "2").Data.(*Task)
+	if len(got.DependsOn) != 1 || got.DependsOn[0] != 1 {
+		t.Errorf("Expected DependsOn [1], got %v", got.DependsOn)
+	}
+
+	// Missing dependency is rejected
+	if result := AddWithDeps(projectPath, "Broken", nil, "spec", []int{99}); result.Success {
+		t.Error("Expected failure for missing dependency")
+	}
+
+	// A spec set on a task without a file keeps the DB's dependencies in the new file
+	os.Remove(TaskFilePath(projectPath, 2))
+	if r := Set(projectPath, "2", "spec", "new spec", ActorCLI); !r.Success {
+		t.Fatalf("Set spec failed: %s", r.Message)
+	}
+	tc, err = ReadTaskContent(projectPath, 2)
+	if err != nil {
+		t.Fatalf("ReadTaskContent failed: %v", err)
+	}
+	if len(tc.Frontmatter.DependsOn) != 1 || tc.Frontmatter.DependsOn[0] != 1 || tc.Title != "Wire API handler" || tc.Body != "new spec" {
+		t.Errorf("Expected the file rebuilt from the DB, got %+v", tc)
+	}
+
+	// A file that cannot be parsed is not overwritten
+	os.WriteFile(TaskFilePath(projectPath, 2), []byte("---\nstatus: [\n"), 0644)
+	if r := Set(projectPath, "2", "spec", "lost", ActorCLI); r.Success {
+		t.Error("Expected failure for an unreadable task file")
+	}
+	if data, _ := os.ReadFile(TaskFilePath(projectPath, 2)); strings.Contains(string(data), "lost") {
+		t.Error("Expected the unreadable file to be kept")
+	}
+}
+
+func TestAddWithDepsRollback(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Create table", nil, "spec")
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer localDB.Close()
+	if _, err := localDB.Exec(`CREATE TRIGGER block_deps BEFORE INSERT ON task_deps BEGIN SELECT RAISE(ABORT, 'blocked'); END`); err != nil {
+		t.Fatalf("CREATE TRIGGER failed: %v", err)
+	}
+
+	// The task is not left behind when its dependencies cannot be stored
+	if r := AddWithDeps(projectPath, "Wire API handler", nil, "spec", []int{1}); r.Success {
+		t.Fatal("Expected failure when task_deps cannot be written")
+	}
+	var count int
+	localDB.QueryRow(`SELECT COUNT(*) FROM tasks`).Scan(&count)
+	if count != 1 {
+		t.Errorf("Expected the insert rolled back, got %d tasks", count)
+	}
+
+	// Sync reports the dependencies it could not store instead of counting an update
+	id := Add(projectPath, "Wire API handler", nil, "spec").Data.(*Task).ID
+	tc, _ := ReadTaskContent(projectPath, id)
+	tc.Frontmatter.DependsOn = []int{1}
+	WriteTaskContent(projectPath, id, tc.Frontmatter, tc.Title, tc.Body)
+	result, err := Sync(projectPath)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Updated != 0 || !strings.Contains(strings.Join(result.Warnings, "\n"), "선행 작업 #1 저장 실패") {
+		t.Errorf("Expected a warning and no update, got %+v", result)
+	}
+}
+
+func TestAddChildren(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Existing", nil, "spec")
+	Add(projectPath, "Epic", nil, "spec")
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	parent := &Task{ID: 2, Depth: 0}
+	ids, err := addChildren(context.Background(), localDB, projectPath, parent, []Child{
+		{Title: "Table", Spec: "table spec", Priority: 3},
+		{Title: "API", Spec: "api spec", After: []int{1}, DependsOn: []int{1}},
+	})
+	if err != nil {
+		t.Fatalf("addChildren failed: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 4 {
+		t.Fatalf("Expected children [3 4], got %v", ids)
+	}
+
+	api := Get(projectPath, "4").Data.(*Task)
+	if api.ParentID == nil || *api.ParentID != 2 || api.Depth != 1 || api.Spec != "api spec" {
+		t.Errorf("Unexpected child: %+v", api)
+	}
+	if len(api.DependsOn) != 2 || api.DependsOn[0] != 1 || api.DependsOn[1] != 3 {
+		t.Errorf("Expected DependsOn [1 3], got %v", api.DependsOn)
+	}
+	tc, err := ReadTaskContent(projectPath, 3)
+	if err != nil {
+		t.Fatalf("ReadTaskContent failed: %v", err)
+	}
+	if tc.Frontmatter.Priority != 3 || tc.Frontmatter.Parent == nil || *tc.Frontmatter.Parent != 2 {
+		t.Errorf("Unexpected frontmatter: %+v", tc.Frontmatter)
+	}
+
+	// A missing existing-task dependency is rejected before anything is created
+	if _, err := addChildren(context.Background(), localDB, projectPath, parent, []Child{
+		{Title: "Ok", Spec: "s"},
+		{Title: "Broken", Spec: "s", DependsOn: []int{99}},
+	}); err == nil {
+		t.Error("Expected failure for missing dependency")
+	}
+	var n int
+	localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE parent_id = 2`).Scan(&n)
+	if n != 2 {
+		t.Errorf("Expected no extra children after a rejected split, got %d", n)
+	}
+}
+
+func TestSetDependsOnRejectsCycle(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "A", nil, "")
+	Add(projectPath, "B", nil, "")
+	Add(projectPath, "C", nil, "")
+
+	if result := Set(projectPath, "2", "depends_on", "1", ActorCLI); !result.Success {
+		t.Fatalf("Set depends_on failed: %s", result.Message)
+	}
+	if result := Set(projectPath, "3", "depends_on", "2", ActorCLI); !result.Success {
+		t.Fatalf("Set depends_on failed: %s", result.Message)
+	}
+
+	// 1 → 3 → 2 → 1
+	if result := Set(projectPath, "1", "depends_on", "3", ActorCLI); result.Success {
+		t.Error("Expected cycle to be rejected")
+	}
+	if result := Set(projectPath, "1", "depends_on", "1", ActorCLI); result.Success {
+		t.Error("Expected self dependency to be rejected")
+	}
+
+	// Clearing is always allowed
+	if result := Set(projectPath, "3", "depends_on", "", ActorCLI); !result.Success {
+		t.Errorf("Clear depends_on failed: %s", result.Message)
+	}
+	if tc, _ := ReadTaskContent(projectPath, 3); tc != nil && len(tc.Frontmatter.DependsOn) != 0 {
+		t.Errorf("Expected depends_on cleared in frontmatter, got %v", tc.Frontmatter.DependsOn)
+	}
+}
+
+func TestTakeReadyTopologicalOrder(t *testing.T) {
+	// #1 has the highest priority but depends on #3
+	pending := []Task{{ID: 1}, {ID: 2}, {ID: 3}}
+	depMap := map[int][]int{1: {3}, 2: {1}}
+	statusMap := map[int]string{1: "planned", 2: "planned", 3: "planned"}
+
+	var order []int
+	for len(pending) > 0 {
+		next, rest, ok := takeReady(pending, depMap, statusMap)
+		if !ok {
+			t.Fatalf("Expected a ready task, pending=%v", rest)
+		}
+		pending = rest
+		statusMap[next.ID] = "done"
+		order = append(order, next.ID)
+	}
+
+	if len(order) != 3 || order[0] != 3 || order[1] != 1 || order[2] != 2 {
+		t.Errorf("Expected order [3 1 2], got %v", order)
+	}
+
+	// Failed dependency blocks the dependent
+	pending = []Task{{ID: 2}}
+	statusMap = map[int]string{1: "failed", 2: "planned"}
+	if _, _, ok := takeReady(pending, depMap, statusMap); ok {
+		t.Error("Expected task with failed dependency to stay blocked")
+	}
+}
+
+func TestSyncMirrorsDependsOn(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "A", nil, "spec")
+	Add(projectPath, "B", nil, "spec")
+
+	// Edit the file directly, then sync
+	tc, _ := ReadTaskContent(projectPath, 2)
+	tc.Frontmatter.DependsOn = []int{1}
+	if err := WriteTaskContent(projectPath, 2, tc.Frontmatter, tc.Title, tc.Body); err != nil {
+		t.Fatalf("WriteTaskContent failed: %v", err)
+	}
+	if _, err := Sync(projectPath); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer localDB.Close()
+
+	deps, err := loadDependencies(localDB, 2)
+	if err != nil {
+		t.Fatalf("loadDependencies failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0] != 1 {
+		t.Errorf("Expected deps [1] after sync, got %v", deps)
+	}
+}
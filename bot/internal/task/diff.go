@@ -0,0 +1,238 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/types"
+)
+
+// diffExclude keeps claribot's own files (task files, reports, worktrees) out of task diffs.
+const diffExclude = ":(exclude).claribot"
+
+// FileChange is the per-file summary of a task diff.
+type FileChange struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"` // added | modified | deleted | renamed
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+// TaskDiff is the code change made by a task run.
+type TaskDiff struct {
+	TaskID int          `json:"task_id"`
+	Files  []FileChange `json:"files"`
+	Diff   string       `json:"diff"`
+}
+
+// DiffFilePath returns the diff file path: {projectPath}/.claribot/tasks/{id}.diff
+func DiffFilePath(projectPath string, id int) string {
+	return filepath.Join(TaskDir(projectPath), fmt.Sprintf("%d.diff", id))
+}
+
+// ReadDiffContent reads a task diff file. Returns ("", nil) if file doesn't exist.
+func ReadDiffContent(projectPath string, id int) (string, error) {
+	data, err := os.ReadFile(DiffFilePath(projectPath, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read diff file: %w", err)
+	}
+	return string(data), nil
+}
+
+// snapshotTree records the working tree of dir (tracked and untracked files,
+// respecting .gitignore) as a git tree object without touching the real index.
+func snapshotTree(dir string) (string, error) {
+	indexPath, err := runGit(dir, "rev-parse", "--path-format=absolute", "--git-path", "index")
+	if err != nil {
+		return "", err
+	}
+
+	// Start from a copy of the real index so unchanged files are not re-hashed
+	tmp, err := os.CreateTemp("", "claribot-index-*")
+	if err != nil {
+		return "", fmt.Errorf("임시 index 생성 실패: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	if src, err := os.Open(indexPath); err == nil {
+		io.Copy(tmp, src)
+		src.Close()
+	}
+	tmp.Close()
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+tmpPath)
+	if _, err := runGitEnv(dir, env, "add", "-A", "--", ".", diffExclude); err != nil {
+		return "", err
+	}
+	return runGitEnv(dir, env, "write-tree")
+}
+
+// runGitEnv runs a git command with a custom environment.
+func runGitEnv(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	outStr := strings.TrimSpace(string(out))
+	if err != nil {
+		return outStr, fmt.Errorf("git %s failed: %s: %w", args[0], outStr, err)
+	}
+	return outStr, nil
+}
+
+// saveTaskDiff diffs the working tree of dir against the snapshot taken before the
+// run and saves it as {id}.diff. An empty diff removes any previous diff file.
+func saveTaskDiff(projectPath, dir string, taskID int, before string) {
+	if before == "" {
+		return
+	}
+	after, err := snapshotTree(dir)
+	if err != nil {
+		log.Printf("[Task] 실행 후 스냅샷 실패 (#%d): %v", taskID, err)
+		return
+	}
+
+	cmd := exec.Command("git", "-C", dir, "diff", "--no-color", "--no-ext-diff", "--find-renames", before, after, "--", ".", diffExclude)
+	out, err := cmd.Output()
+	if err != nil {
+		log.Printf("[Task] diff 생성 실패 (#%d): %v", taskID, err)
+		return
+	}
+
+	path := DiffFilePath(projectPath, taskID)
+	if len(out) == 0 {
+		removeTaskDiff(projectPath, taskID)
+		return
+	}
+	if err := EnsureTaskDir(projectPath); err != nil {
+		log.Printf("[Task] diff 파일 생성 실패 (#%d): %v", taskID, err)
+		return
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		log.Printf("[Task] diff 파일 생성 실패 (#%d): %v", taskID, err)
+	}
+}
+
+// removeTaskDiff deletes the diff of a previous run, so no stale diff is shown.
+func removeTaskDiff(projectPath string, taskID int) {
+	if err := os.Remove(DiffFilePath(projectPath, taskID)); err != nil && !os.IsNotExist(err) {
+		log.Printf("[Task] diff 파일 삭제 실패 (#%d): %v", taskID, err)
+	}
+}
+
+// ParseDiffSummary builds the per-file change summary of a unified git diff.
+func ParseDiffSummary(diff string) []FileChange {
+	var files []FileChange
+	var cur *FileChange
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, FileChange{Status: "modified"})
+			cur = &files[len(files)-1]
+			inHunk = false
+			// "diff --git a/path b/path": the b/ side is the new path
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				cur.Path = line[i+3:]
+			}
+		case cur == nil:
+			continue
+		case strings.HasPrefix(line, "new file mode"):
+			cur.Status = "added"
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Status = "deleted"
+		case strings.HasPrefix(line, "rename to "):
+			cur.Status = "renamed"
+			cur.Path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "Binary files "):
+			cur.Binary = true
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk && (strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- ")):
+			// File headers; inside a hunk these are added/removed lines like "-- x"
+			continue
+		case strings.HasPrefix(line, "+"):
+			cur.Added++
+		case strings.HasPrefix(line, "-"):
+			cur.Deleted++
+		}
+	}
+	return files
+}
+
+// formatDiffSummary renders the per-file summary, one line per file.
+func formatDiffSummary(files []FileChange) string {
+	var added, deleted int
+	var sb strings.Builder
+	for _, f := range files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	sb.WriteString(fmt.Sprintf("%d개 파일 (+%d -%d)", len(files), added, deleted))
+	for _, f := range files {
+		stat := fmt.Sprintf("+%d -%d", f.Added, f.Deleted)
+		if f.Binary {
+			stat = "binary"
+		}
+		sb.WriteString(fmt.Sprintf("\n  %s %s (%s)", diffStatusIcon(f.Status), f.Path, stat))
+	}
+	return sb.String()
+}
+
+// diffStatusIcon returns the git-style status letter of a changed file.
+func diffStatusIcon(status string) string {
+	switch status {
+	case "added":
+		return "A"
+	case "deleted":
+		return "D"
+	case "renamed":
+		return "R"
+	default:
+		return "M"
+	}
+}
+
+// taskButtons returns the report buttons of a finished task ([Diff] only if a diff was recorded).
+func taskButtons(projectPath string, taskID int) string {
+	buttons := fmt.Sprintf("[조회:task get %d]", taskID)
+	if _, err := os.Stat(DiffFilePath(projectPath, taskID)); err == nil {
+		buttons += fmt.Sprintf("[Diff:task diff %d]", taskID)
+	}
+	return buttons
+}
+
+// GetDiff returns the code diff recorded for a task run.
+func GetDiff(projectPath, id string) types.Result {
+	taskID, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("잘못된 작업 ID: %s", id)}
+	}
+
+	diff, err := ReadDiffContent(projectPath, taskID)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("diff 조회 실패: %v", err)}
+	}
+	if diff == "" {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("작업 #%d의 diff가 없습니다. (실행 전이거나 변경 없음)\n[조회:task get %d]", taskID, taskID),
+		}
+	}
+
+	d := &TaskDiff{TaskID: taskID, Files: ParseDiffSummary(diff), Diff: diff}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("🔀 작업 #%d 변경: %s\n\n```diff\n%s```", taskID, formatDiffSummary(d.Files), diff),
+		Data:    d,
+	}
+}
@@ -0,0 +1,135 @@
+package task
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+func TestParseDiffSummary(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ package main
+-func old() {}
++func a() {}
++func b() {}
+@@ -9,1 +10,1 @@
+--- SQL comment removed
++++ counter incremented
+diff --git a/api.go b/api.go
+new file mode 100644
+--- /dev/null
++++ b/api.go
+@@ -0,0 +1 @@
++package main
+diff --git a/old.go b/new.go
+similarity index 100%
+rename from old.go
+rename to new.go
+diff --git a/logo.png b/logo.png
+deleted file mode 100644
+Binary files a/logo.png and /dev/null differ
+`
+	files := ParseDiffSummary(diff)
+	if len(files) != 4 {
+		t.Fatalf("Expected 4 files, got %+v", files)
+	}
+	expect := []FileChange{
+		{Path: "main.go", Status: "modified", Added: 3, Deleted: 2},
+		{Path: "api.go", Status: "added", Added: 1},
+		{Path: "new.go", Status: "renamed"},
+		{Path: "logo.png", Status: "deleted", Binary: true},
+	}
+	for i, want := range expect {
+		if files[i] != want {
+			t.Errorf("file %d: expected %+v, got %+v", i, want, files[i])
+		}
+	}
+}
+
+func TestSaveTaskDiff(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	setupGitRepo(t, projectPath)
+
+	Add(projectPath, "Add handler", nil, "spec")
+	before, err := snapshotTree(projectPath)
+	if err != nil {
+		t.Fatalf("snapshotTree failed: %v", err)
+	}
+
+	// Tracked edit, untracked new file, and claribot's own files
+	os.WriteFile(filepath.Join(projectPath, "main.go"), []byte("package main\n\nfunc handler() {}\n"), 0644)
+	os.WriteFile(filepath.Join(projectPath, "handler.go"), []byte("package main\n"), 0644)
+	WriteReportContent(projectPath, 1, "report")
+
+	saveTaskDiff(projectPath, projectPath, 1, before)
+
+	result := GetDiff(projectPath, "1")
+	if !result.Success {
+		t.Fatalf("GetDiff failed: %s", result.Message)
+	}
+	d := result.Data.(*TaskDiff)
+	if len(d.Files) != 2 {
+		t.Fatalf("Expected main.go and handler.go, got %+v", d.Files)
+	}
+	if strings.Contains(d.Diff, ".claribot") {
+		t.Error("Diff should not include .claribot files")
+	}
+	if !strings.Contains(d.Diff, "+func handler() {}") {
+		t.Errorf("Expected code change in diff: %s", d.Diff)
+	}
+
+	// The real index is untouched
+	if out, _ := runGit(projectPath, "diff", "--cached", "--name-only"); out != "" {
+		t.Errorf("Expected nothing staged, got %q", out)
+	}
+
+	// task get shows the summary and the diff button
+	got := Get(projectPath, "1")
+	if task := got.Data.(*Task); len(task.Changes) != 2 {
+		t.Errorf("Expected 2 changes on task, got %+v", task.Changes)
+	}
+	if !strings.Contains(got.Message, "[Diff:task diff 1]") {
+		t.Errorf("Expected diff button in task get: %s", got.Message)
+	}
+
+	// A run without changes removes the stale diff
+	before, _ = snapshotTree(projectPath)
+	saveTaskDiff(projectPath, projectPath, 1, before)
+	if result := GetDiff(projectPath, "1"); result.Success {
+		t.Error("Expected no diff after a run without changes")
+	}
+}
+
+func TestRunSharedTreeSkipsDiff(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	setupGitRepo(t, projectPath)
+
+	Add(projectPath, "Add handler", nil, "spec")
+	useFakeAgent(t, projectPath,
+		claude.FakeStep{Report: "[PLANNED]\n간단한 수정"},
+		claude.FakeStep{Report: "완료"},
+	)
+	if r := Plan(projectPath, "1", ActorCLI); !r.Success {
+		t.Fatalf("Plan failed: %s", r.Message)
+	}
+	os.WriteFile(DiffFilePath(projectPath, 1), []byte("stale"), 0644)
+	os.WriteFile(filepath.Join(projectPath, "other.go"), []byte("package main\n"), 0644)
+
+	// Another worker's edits would end up in the diff: none is saved
+	if r := runTask(withSharedTree(context.Background()), projectPath, "1", false); !r.Success {
+		t.Fatalf("runTask failed: %s", r.Message)
+	}
+	if _, err := os.Stat(DiffFilePath(projectPath, 1)); !os.IsNotExist(err) {
+		t.Error("Expected no diff for a parallel run in a shared tree")
+	}
+}
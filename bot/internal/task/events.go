@@ -0,0 +1,170 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+)
+
+// Actors recorded on task_events (who or what changed a task's status)
+const (
+	ActorCLI      = "cli"
+	ActorGUI      = "gui"
+	ActorCycle    = "cycle"    // plan/run of a batch traversal (cycle, plan/run --all) and its retries
+	ActorSync     = "sync"     // task file edited outside claribot, picked up by task sync
+	ActorWatch    = "watch"    // same, picked up by the file watcher
+	ActorRebuild  = "rebuild"  // DB rebuilt from the task files
+	ActorRollup   = "rollup"   // parent status derived from its children
+	ActorSchedule = "schedule" // task file edited by a scheduled run, synced by the scheduler after it
+)
+
+// TelegramActor is the actor of a command sent by a Telegram user.
+func TelegramActor(userID int64) string {
+	return fmt.Sprintf("telegram:%d", userID)
+}
+
+// actorCtxKey carries the actor of the status changes made under a ctx.
+type actorCtxKey struct{}
+
+// WithActor returns ctx whose status changes are recorded with actor: the sender
+// of a single task plan/run/replan, or the scheduler for SyncWithContext.
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// actorFromContext returns the actor carried by ctx (ActorCycle if none: a batch traversal).
+func actorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorCtxKey{}).(string); ok {
+		return actor
+	}
+	return ActorCycle
+}
+
+// TaskEvent is one recorded status transition of a task.
+type TaskEvent struct {
+	ID          int64  `json:"id"`
+	TaskID      int    `json:"task_id"`
+	FromStatus  string `json:"from_status"`
+	ToStatus    string `json:"to_status"`
+	Actor       string `json:"actor"`
+	TraversalID *int64 `json:"traversal_id,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// execer is implemented by *db.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// taskStatus returns the current status of a task ("" if it does not exist).
+func taskStatus(localDB *db.DB, taskID int) string {
+	var status string
+	localDB.QueryRow(`SELECT status FROM tasks WHERE id = ?`, taskID).Scan(&status)
+	return status
+}
+
+// recordEvent records a status transition (no-op when the status did not change).
+// travID is the traversal the change happened in (0 = none). Failures are only logged.
+func recordEvent(q execer, taskID int, from, to, actor string, travID int64) {
+	if from == to {
+		return
+	}
+	var trav *int64
+	if travID != 0 {
+		trav = &travID
+	}
+	_, err := q.Exec(`
+		INSERT INTO task_events (task_id, from_status, to_status, actor, traversal_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, taskID, from, to, actor, trav, db.TimeNow())
+	if err != nil {
+		log.Printf("[Task] task_events INSERT 실패 (#%d %s→%s): %v", taskID, from, to, err)
+	}
+}
+
+// loadEvents returns the status transitions of a task, oldest first.
+func loadEvents(localDB *db.DB, taskID int) ([]TaskEvent, error) {
+	rows, err := localDB.Query(`
+		SELECT id, task_id, from_status, to_status, actor, traversal_id, created_at
+		FROM task_events WHERE task_id = ? ORDER BY id
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []TaskEvent
+	for rows.Next() {
+		var e TaskEvent
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.FromStatus, &e.ToStatus, &e.Actor, &e.TraversalID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// formatEvents renders a status timeline, one line per transition.
+func formatEvents(events []TaskEvent) string {
+	var sb strings.Builder
+	for _, e := range events {
+		line := fmt.Sprintf("  %s %s → %s (%s", e.CreatedAt, e.FromStatus, e.ToStatus, e.Actor)
+		if e.TraversalID != nil {
+			line += fmt.Sprintf(", [순회 #%d:task history %d]", *e.TraversalID, *e.TraversalID)
+		}
+		sb.WriteString(line + ")\n")
+	}
+	return sb.String()
+}
+
+// ListEvents returns the status timeline of a task.
+func ListEvents(projectPath, id string) types.Result {
+	taskID, err := strconv.Atoi(id)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("잘못된 작업 ID: %s", id)}
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	var title string
+	if err := localDB.QueryRow(`SELECT title FROM tasks WHERE id = ?`, taskID).Scan(&title); err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("작업을 찾을 수 없습니다: %s", id)}
+	}
+
+	events, err := loadEvents(localDB, taskID)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("조회 실패: %v", err),
+		}
+	}
+	if events == nil {
+		events = []TaskEvent{}
+	}
+
+	msg := fmt.Sprintf("작업 #%d 상태 변경 이력이 없습니다: %s", taskID, title)
+	if len(events) > 0 {
+		msg = fmt.Sprintf("🕒 작업 #%d 상태 변경 이력: %s (%d건)\n%s", taskID, title, len(events), formatEvents(events))
+	}
+	return types.Result{
+		Success: true,
+		Message: strings.TrimRight(msg, "\n"),
+		Data:    events,
+	}
+}
@@ -0,0 +1,161 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+// eventLines formats the events of a task as "from→to actor" for comparison.
+func eventLines(t *testing.T, projectPath string, id string) []string {
+	t.Helper()
+	result := ListEvents(projectPath, id)
+	if !result.Success {
+		t.Fatalf("ListEvents failed: %s", result.Message)
+	}
+	var out []string
+	for _, e := range result.Data.([]TaskEvent) {
+		line := fmt.Sprintf("%s→%s %s", e.FromStatus, e.ToStatus, e.Actor)
+		if e.TraversalID != nil {
+			line += fmt.Sprintf(" trav:%d", *e.TraversalID)
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+func checkEvents(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("Expected events %v, got %v", want, got)
+	}
+}
+
+func TestTaskEvents(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	parentID := 1
+	Add(projectPath, "Login", &parentID, "spec")
+
+	Set(projectPath, "2", "status", "review", ActorGUI)
+	Set(projectPath, "2", "status", "review", ActorGUI) // unchanged: not recorded
+	Reject(projectPath, "2", "use JWT", TelegramActor(42))
+	Set(projectPath, "2", "status", "done", ActorCLI)
+
+	checkEvents(t, eventLines(t, projectPath, "2"),
+		"todo→review gui", "review→todo telegram:42", "todo→done cli")
+	checkEvents(t, eventLines(t, projectPath, "1"),
+		"todo→split rollup", "split→done rollup")
+
+	result := Get(projectPath, "2")
+	if !strings.Contains(result.Message, "🕒 Timeline:") || !strings.Contains(result.Message, "review → todo (telegram:42)") {
+		t.Errorf("Expected timeline in task get, got: %s", result.Message)
+	}
+	if got := result.Data.(*Task); len(got.Events) != 3 {
+		t.Errorf("Expected 3 events on task, got %d", len(got.Events))
+	}
+
+	if result := ListEvents(projectPath, "99"); result.Success {
+		t.Error("Expected unknown task to fail")
+	}
+
+	// Deleting a task drops its events
+	Delete(projectPath, "1", true)
+	localDB, _ := db.OpenLocal(projectPath)
+	defer localDB.Close()
+	var n int
+	localDB.QueryRow(`SELECT COUNT(*) FROM task_events`).Scan(&n)
+	if n != 0 {
+		t.Errorf("Expected events of deleted tasks to be removed, got %d", n)
+	}
+}
+
+func TestTaskEventsFromFiles(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	setFileStatus := func(status string) {
+		tc, _ := ReadTaskContent(projectPath, 1)
+		tc.Frontmatter.Status = status
+		content := FormatFrontmatter(tc.Frontmatter, tc.Title, tc.Body)
+		if err := os.WriteFile(TaskFilePath(projectPath, 1), []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	setFileStatus("planned")
+	if _, err := Sync(projectPath); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	setFileStatus("failed")
+	if _, err := SyncTasks(projectPath, []int{1}); err != nil {
+		t.Fatalf("SyncTasks failed: %v", err)
+	}
+	setFileStatus("todo")
+	if _, err := Rebuild(projectPath); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+
+	checkEvents(t, eventLines(t, projectPath, "1"),
+		"todo→planned sync", "planned→failed watch", "failed→todo rebuild")
+}
+
+func TestTaskEventsManualPlanRun(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	useFakeAgent(t, projectPath,
+		claude.FakeStep{Report: "[PLANNED]\n간단한 수정"},
+		claude.FakeStep{Report: "완료"},
+	)
+	if r := Plan(projectPath, "1", TelegramActor(7)); !r.Success {
+		t.Fatalf("Plan failed: %s", r.Message)
+	}
+	if r := Run(projectPath, "1", ActorGUI); !r.Success {
+		t.Fatalf("Run failed: %s", r.Message)
+	}
+
+	// A single plan/run is recorded with its sender, not the cycle actor
+	got := eventLines(t, projectPath, "1")
+	if len(got) != 2 || !strings.HasPrefix(got[0], "todo→planned telegram:7 trav:") || !strings.HasPrefix(got[1], "planned→done gui trav:") {
+		t.Errorf("Expected events by telegram:7 and gui, got %v", got)
+	}
+}
+
+func TestTaskEventsTraversal(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	Set(projectPath, "1", "status", "planned", ActorCLI)
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+	travID, err := insertTraversal(localDB, "run", nil, "")
+	if err != nil {
+		t.Fatalf("insertTraversal failed: %v", err)
+	}
+
+	ctx := withTraversal(context.Background(), travID)
+	failRun(ctx, localDB, projectPath, 1, "boom", "failed", ReportFilePath(projectPath, 1))
+	if err := resetForRetry(projectPath, 1, travID); err != nil {
+		t.Fatalf("resetForRetry failed: %v", err)
+	}
+
+	checkEvents(t, eventLines(t, projectPath, "1"),
+		"todo→planned cli",
+		fmt.Sprintf("planned→failed cycle trav:%d", travID),
+		fmt.Sprintf("failed→planned cycle trav:%d", travID))
+}
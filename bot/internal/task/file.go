@@ -37,6 +37,16 @@ func ErrorFilePath(projectPath string, id int) string {
 	return filepath.Join(TaskDir(projectPath), fmt.Sprintf("%d.error.md", id))
 }
 
+// VerifyFilePath returns the verification output path: {projectPath}/.claribot/tasks/{id}.verify.md
+func VerifyFilePath(projectPath string, id int) string {
+	return filepath.Join(TaskDir(projectPath), fmt.Sprintf("%d.verify.md", id))
+}
+
+// ReviewFilePath returns the reviewer comment path: {projectPath}/.claribot/tasks/{id}.review.md
+func ReviewFilePath(projectPath string, id int) string {
+	return filepath.Join(TaskDir(projectPath), fmt.Sprintf("%d.review.md", id))
+}
+
 // EnsureTaskDir creates the task directory if it doesn't exist.
 func EnsureTaskDir(projectPath string) error {
 	dir := TaskDir(projectPath)
@@ -81,9 +91,10 @@ func WriteTaskContent(projectPath string, id int, fm Frontmatter, title, body st
 
 	content := FormatFrontmatter(fm, title, body)
 	path := TaskFilePath(projectPath, id)
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	if err := writeTaskFile(path, content); err != nil {
 		return fmt.Errorf("write task file: %w", err)
 	}
+	indexTaskContent(projectPath, id, searchSpec, specSearchContent(title, body))
 	return nil
 }
 
@@ -107,9 +118,10 @@ func WritePlanContent(projectPath string, id int, content string) error {
 	}
 
 	path := PlanFilePath(projectPath, id)
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	if err := writeTaskFile(path, content); err != nil {
 		return fmt.Errorf("write plan file: %w", err)
 	}
+	indexTaskContent(projectPath, id, searchPlan, content)
 	return nil
 }
 
@@ -167,9 +179,10 @@ func WriteReportContent(projectPath string, id int, content string) error {
 	}
 
 	path := ReportFilePath(projectPath, id)
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	if err := writeTaskFile(path, content); err != nil {
 		return fmt.Errorf("write report file: %w", err)
 	}
+	indexTaskContent(projectPath, id, searchReport, content)
 	return nil
 }
 
@@ -193,9 +206,62 @@ func WriteErrorContent(projectPath string, id int, content string) error {
 	}
 
 	path := ErrorFilePath(projectPath, id)
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	if err := writeTaskFile(path, content); err != nil {
 		return fmt.Errorf("write error file: %w", err)
 	}
+	indexTaskContent(projectPath, id, searchError, content)
+	return nil
+}
+
+// ReadVerifyContent reads a verification output .md file. Returns ("", nil) if file doesn't exist.
+func ReadVerifyContent(projectPath string, id int) (string, error) {
+	path := VerifyFilePath(projectPath, id)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read verify file: %w", err)
+	}
+	return string(data), nil
+}
+
+// WriteVerifyContent writes a verification output .md file.
+func WriteVerifyContent(projectPath string, id int, content string) error {
+	if err := EnsureTaskDir(projectPath); err != nil {
+		return err
+	}
+
+	path := VerifyFilePath(projectPath, id)
+	if err := writeTaskFile(path, content); err != nil {
+		return fmt.Errorf("write verify file: %w", err)
+	}
+	return nil
+}
+
+// ReadReviewContent reads a reviewer comment .md file. Returns ("", nil) if file doesn't exist.
+func ReadReviewContent(projectPath string, id int) (string, error) {
+	path := ReviewFilePath(projectPath, id)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read review file: %w", err)
+	}
+	return string(data), nil
+}
+
+// WriteReviewContent writes a reviewer comment .md file.
+func WriteReviewContent(projectPath string, id int, content string) error {
+	if err := EnsureTaskDir(projectPath); err != nil {
+		return err
+	}
+
+	path := ReviewFilePath(projectPath, id)
+	if err := writeTaskFile(path, content); err != nil {
+		return fmt.Errorf("write review file: %w", err)
+	}
 	return nil
 }
 
@@ -253,7 +319,7 @@ func computeDepth(id int, parentMap map[int]*int) int {
 	}
 }
 
-// LoadContent populates a Task's content fields (Spec, Plan, Report, Error) from files.
+// LoadContent populates a Task's content fields (Spec, Plan, Report, Error, Verify, Review) from files.
 // Fields are only overwritten if the file exists and has non-empty content.
 func LoadContent(projectPath string, t *Task) {
 	if tc, err := ReadTaskContent(projectPath, t.ID); err == nil && tc.Body != "" {
@@ -268,4 +334,10 @@ func LoadContent(projectPath string, t *Task) {
 	if errContent, err := ReadErrorContent(projectPath, t.ID); err == nil && errContent != "" {
 		t.Error = errContent
 	}
+	if verify, err := ReadVerifyContent(projectPath, t.ID); err == nil && verify != "" {
+		t.Verify = verify
+	}
+	if review, err := ReadReviewContent(projectPath, t.ID); err == nil && review != "" {
+		t.Review = review
+	}
 }
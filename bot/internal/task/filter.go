@@ -0,0 +1,256 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+	"parkjunwoo.com/claribot/pkg/pagination"
+)
+
+// ListFilter narrows a task list. Zero fields are ignored.
+type ListFilter struct {
+	Labels []string `json:"labels,omitempty"` // task must have every label
+	Status []string `json:"status,omitempty"` // task must have one of the statuses
+	Depth  *int     `json:"depth,omitempty"`
+	Root   *int     `json:"root,omitempty"` // descendants of this task (subtree, root excluded)
+	Text   string   `json:"text,omitempty"` // case-insensitive substring of the title
+}
+
+// filterFlags are the task list flags that take a value.
+var filterFlags = map[string]bool{
+	"--label":  true,
+	"--status": true,
+	"--depth":  true,
+	"--root":   true,
+	"--text":   true,
+}
+
+// NewListFilter builds a filter from raw values (empty values are ignored).
+// labels and status are comma/space-separated lists.
+func NewListFilter(labels, status, depth, root, text string) (ListFilter, error) {
+	var f ListFilter
+	var err error
+	if f.Labels, err = ParseLabels(labels); err != nil {
+		return f, err
+	}
+	if len(f.Labels) == 0 {
+		f.Labels = nil
+	}
+	for _, s := range strings.FieldsFunc(status, func(r rune) bool { return r == ',' || r == ' ' }) {
+		s = strings.ToLower(s)
+		if !ValidStatus[s] {
+			return f, fmt.Errorf("허용되지 않는 상태: %s", s)
+		}
+		f.Status = append(f.Status, s)
+	}
+	if depth = strings.TrimSpace(depth); depth != "" {
+		d, err := strconv.Atoi(depth)
+		if err != nil || d < 0 {
+			return f, fmt.Errorf("depth는 0 이상의 정수여야 합니다: %s", depth)
+		}
+		f.Depth = &d
+	}
+	if root = strings.TrimPrefix(strings.TrimSpace(root), "#"); root != "" {
+		r, err := strconv.Atoi(root)
+		if err != nil || r <= 0 {
+			return f, fmt.Errorf("잘못된 root ID: %s", root)
+		}
+		f.Root = &r
+	}
+	f.Text = strings.TrimSpace(text)
+	return f, nil
+}
+
+// ParseListFilter extracts the filter flags (--label, --status, --depth, --root, --text)
+// from command args. It returns the filter and the remaining args.
+func ParseListFilter(args []string) (ListFilter, []string, error) {
+	values := make(map[string]string)
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if !filterFlags[args[i]] {
+			rest = append(rest, args[i])
+			continue
+		}
+		if i+1 >= len(args) {
+			return ListFilter{}, nil, fmt.Errorf("%s 값이 필요합니다", args[i])
+		}
+		if prev := values[args[i]]; prev != "" {
+			values[args[i]] = prev + "," + args[i+1]
+		} else {
+			values[args[i]] = args[i+1]
+		}
+		i++
+	}
+	f, err := NewListFilter(values["--label"], values["--status"], values["--depth"], values["--root"], values["--text"])
+	if err != nil {
+		return ListFilter{}, nil, err
+	}
+	return f, rest, nil
+}
+
+// IsEmpty reports whether the filter has no conditions.
+func (f ListFilter) IsEmpty() bool {
+	return len(f.Labels) == 0 && len(f.Status) == 0 && f.Depth == nil && f.Root == nil && f.Text == ""
+}
+
+// String formats the filter as command flags, e.g. `--label api --text "login page"`.
+func (f ListFilter) String() string {
+	var parts []string
+	if len(f.Labels) > 0 {
+		parts = append(parts, "--label "+strings.Join(f.Labels, ","))
+	}
+	if len(f.Status) > 0 {
+		parts = append(parts, "--status "+strings.Join(f.Status, ","))
+	}
+	if f.Depth != nil {
+		parts = append(parts, fmt.Sprintf("--depth %d", *f.Depth))
+	}
+	if f.Root != nil {
+		parts = append(parts, fmt.Sprintf("--root %d", *f.Root))
+	}
+	if f.Text != "" {
+		text := f.Text
+		if strings.ContainsAny(text, " \t") {
+			text = `"` + text + `"`
+		}
+		parts = append(parts, "--text "+text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// where builds the SQL condition and arguments for the filter.
+func (f ListFilter) where() (string, []interface{}) {
+	conds := []string{"1 = 1"}
+	var args []interface{}
+	for _, label := range f.Labels {
+		conds = append(conds, `id IN (SELECT task_id FROM task_labels WHERE label = ?)`)
+		args = append(args, label)
+	}
+	if len(f.Status) > 0 {
+		conds = append(conds, "status IN (?"+strings.Repeat(", ?", len(f.Status)-1)+")")
+		for _, s := range f.Status {
+			args = append(args, s)
+		}
+	}
+	if f.Depth != nil {
+		conds = append(conds, "depth = ?")
+		args = append(args, *f.Depth)
+	}
+	if f.Root != nil {
+		conds = append(conds, `id IN (
+			WITH RECURSIVE subtree(id) AS (
+				SELECT id FROM tasks WHERE parent_id = ?
+				UNION ALL
+				SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			)
+			SELECT id FROM subtree
+		)`)
+		args = append(args, *f.Root)
+	}
+	if f.Text != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(f.Text)
+		conds = append(conds, `title LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escaped+"%")
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// ListFiltered lists tasks matching the filter across the whole tree, newest first.
+// listCmd is the command that reproduces the list (used for pagination buttons).
+func ListFiltered(projectPath string, filter ListFilter, listCmd string, req pagination.PageRequest) types.Result {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	where, args := filter.where()
+
+	var total int
+	if err := localDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE `+where, args...).Scan(&total); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("카운트 실패: %v", err),
+		}
+	}
+	if total == 0 {
+		return types.Result{
+			Success: true,
+			Message: fmt.Sprintf("조건에 맞는 작업이 없습니다: %s", filter),
+		}
+	}
+
+	rows, err := localDB.Query(`
+		SELECT id, parent_id, title, status, priority, depth, is_leaf, created_at
+		FROM tasks
+		WHERE `+where+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`, append(args, req.Limit(), req.Offset())...)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("조회 실패: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		var t Task
+		if err := rows.Scan(&t.ID, &t.ParentID, &t.Title, &t.Status, &t.Priority, &t.Depth, &t.IsLeaf, &t.CreatedAt); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("스캔 실패: %v", err),
+			}
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("행 순회 오류: %v", err),
+		}
+	}
+	rows.Close()
+
+	if labelMap, err := loadLabelMap(localDB); err == nil {
+		for i := range tasks {
+			tasks[i].Labels = labelMap[tasks[i].ID]
+		}
+	}
+
+	pageResp := pagination.NewPageResponse(tasks, req.Page, req.PageSize, total)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔎 %s (%d/%d 페이지, 총 %d개)\n", filter, pageResp.Page, pageResp.TotalPages, total))
+	for _, t := range tasks {
+		line := fmt.Sprintf("  %s [#%d:task get %d] %s", statusToIcon(t.Status), t.ID, t.ID, t.Title)
+		if len(t.Labels) > 0 {
+			line += " 🏷 " + formatLabels(t.Labels)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if pageResp.HasPrev || pageResp.HasNext {
+		sb.WriteString("\n")
+		if pageResp.HasPrev {
+			sb.WriteString(fmt.Sprintf("[◀ 이전:%s -p %d]", listCmd, pageResp.Page-1))
+		}
+		if pageResp.HasNext {
+			sb.WriteString(fmt.Sprintf("[다음 ▶:%s -p %d]", listCmd, pageResp.Page+1))
+		}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: sb.String(),
+		Data:    pageResp,
+	}
+}
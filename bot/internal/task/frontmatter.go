@@ -9,9 +9,15 @@ import (
 
 // Frontmatter represents YAML metadata in a task .md file
 type Frontmatter struct {
-	Status   string `yaml:"status"`
-	Parent   *int   `yaml:"parent,omitempty"`
-	Priority int    `yaml:"priority,omitempty"`
+	Status       string   `yaml:"status"`
+	Parent       *int     `yaml:"parent,omitempty"`
+	Priority     int      `yaml:"priority,omitempty"`
+	DependsOn    []int    `yaml:"depends_on,omitempty,flow"`
+	Labels       []string `yaml:"labels,omitempty,flow"`
+	Node         string   `yaml:"node,omitempty"`
+	Model        string   `yaml:"model,omitempty"`
+	AllowedTools []string `yaml:"allowed_tools,omitempty,flow"`
+	Timeout      string   `yaml:"timeout,omitempty"`
 }
 
 // ParseFrontmatter parses a task markdown file content into frontmatter, title, and body.
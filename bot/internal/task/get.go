@@ -3,6 +3,7 @@ package task
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"parkjunwoo.com/claribot/internal/db"
 	"parkjunwoo.com/claribot/internal/types"
@@ -44,32 +45,100 @@ func Get(projectPath, id string) types.Result {
 	// Load content from files (sole source of truth)
 	LoadContent(projectPath, &t)
 
+	if deps, err := loadDependencies(localDB, t.ID); err == nil {
+		t.DependsOn = deps
+	}
+	if labels, err := loadLabels(localDB, t.ID); err == nil {
+		t.Labels = labels
+	}
+	t.Node = lookupGraphNode(localDB, t.ID)
+	if attempts, err := loadAttempts(localDB, t.ID); err == nil {
+		t.Attempts = attempts
+	}
+	if events, err := loadEvents(localDB, t.ID); err == nil {
+		t.Events = events
+	}
+	localDB.QueryRow("SELECT COALESCE(replans, 0) FROM tasks WHERE id = ?", t.ID).Scan(&t.Replans)
+	if diff, err := ReadDiffContent(projectPath, t.ID); err == nil && diff != "" {
+		t.Changes = ParseDiffSummary(diff)
+	}
+	runOpts := resolveRunOptions(localDB, projectPath, t.ID)
+	t.Model = runOpts.Model
+	t.AllowedTools = runOpts.AllowedTools
+	if runOpts.Timeout > 0 {
+		t.Timeout = runOpts.Timeout.String()
+	}
+
 	statusIcon := statusToIcon(t.Status)
 	msg := fmt.Sprintf("%s #%d %s\nStatus: %s\nCreated: %s", statusIcon, t.ID, t.Title, t.Status, t.CreatedAt)
 	if t.Priority != 0 {
 		msg += fmt.Sprintf("\nPriority: %d", t.Priority)
 	}
+	if len(t.Labels) > 0 {
+		msg += fmt.Sprintf("\nLabels: %s", formatLabels(t.Labels))
+	}
+	if t.Node != "" {
+		msg += fmt.Sprintf("\nNode: %s", t.Node)
+	}
+	if len(t.DependsOn) > 0 {
+		msg += fmt.Sprintf("\nDepends on: %s", formatDependsOn(t.DependsOn))
+		if unmet, err := unmetDependencies(localDB, t.ID); err == nil && len(unmet) > 0 {
+			msg += fmt.Sprintf(" (미완료: %s)", formatDependsOn(unmet))
+		}
+	}
+	if t.Replans > 0 {
+		msg += fmt.Sprintf("\nReplans: %d/%d", t.Replans, getReplanConfig(localDB).Max)
+	}
+	if opts := formatRunOptions(runOpts); opts != "" {
+		msg += fmt.Sprintf("\nClaude: %s", opts)
+	}
 	if t.Spec != "" {
 		msg += fmt.Sprintf("\n\n📝 Spec:\n%s", t.Spec)
 	}
 	if t.Plan != "" {
 		msg += fmt.Sprintf("\n\n📋 Plan:\n%s", t.Plan)
 	}
+	if t.Review != "" {
+		msg += fmt.Sprintf("\n\n💬 Review (반려 사유):\n%s", t.Review)
+	}
 	if t.Report != "" {
 		msg += fmt.Sprintf("\n\n📄 Report:\n%s", t.Report)
 	}
+	if len(t.Changes) > 0 {
+		msg += fmt.Sprintf("\n\n🔀 Changes: %s", formatDiffSummary(t.Changes))
+	}
+	if t.Verify != "" {
+		msg += fmt.Sprintf("\n\n🧪 Verify:\n%s", t.Verify)
+	}
 	if t.Error != "" {
 		msg += fmt.Sprintf("\n\n❌ Error:\n%s", t.Error)
 	}
+	if len(t.Attempts) > 0 {
+		msg += fmt.Sprintf("\n\n🔁 Attempts:\n%s", formatAttempts(t.Attempts))
+	}
+	if len(t.Events) > 0 {
+		msg += fmt.Sprintf("\n\n🕒 Timeline:\n%s", strings.TrimRight(formatEvents(t.Events), "\n"))
+	}
 
-	// Add action buttons based on status
-	switch t.Status {
-	case "todo":
+	// Add action buttons based on status (a parent's status follows its children)
+	switch {
+	case IsTaskRunning(projectPath, t.ID):
+		msg += fmt.Sprintf("\n🔄 실행 중 [취소:task cancel %d]", t.ID)
+	case !t.IsLeaf:
+		msg += fmt.Sprintf("\n[하위 순회:task cycle %d][삭제:task delete %d]", t.ID, t.ID)
+	case t.Status == "todo":
 		msg += fmt.Sprintf("\n[Plan 생성:task plan %d][삭제:task delete %d]", t.ID, t.ID)
-	case "planned":
+	case t.Status == "planned":
 		msg += fmt.Sprintf("\n[실행:task run %d][삭제:task delete %d]", t.ID, t.ID)
-	case "done", "failed":
+	case t.Status == "review":
+		msg += fmt.Sprintf("\n%s[삭제:task delete %d]", reviewButtons(t.ID), t.ID)
+	case t.Status == "done":
 		msg += fmt.Sprintf("\n[삭제:task delete %d]", t.ID)
+	case t.Status == "failed":
+		msg += fmt.Sprintf("\n[재계획:task replan %d][삭제:task delete %d]", t.ID, t.ID)
+	}
+	if len(t.Changes) > 0 {
+		msg += fmt.Sprintf("[Diff:task diff %d]", t.ID)
 	}
 
 	return types.Result{
@@ -1,23 +1,16 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
-	"sync/atomic"
 )
 
-var gitMu sync.Mutex     // git 명령어 직렬화
-var batchMode atomic.Bool // true: 개별 커밋 억제
-
-// SetBatchMode enables/disables batch mode (suppresses per-task commits).
-func SetBatchMode(enabled bool) { batchMode.Store(enabled) }
-
-// IsBatchMode returns whether batch mode is active.
-func IsBatchMode() bool { return batchMode.Load() }
+var gitMu sync.Mutex // git 명령어 직렬화
 
 var (
 	gitAvailableOnce sync.Once
@@ -63,15 +56,20 @@ func GitAdd(projectPath string, paths ...string) error {
 }
 
 // GitCommit creates a commit in the git repository.
+// If paths are given, only those paths are committed (other staged changes stay staged).
 // "nothing to commit" is silently ignored.
-func GitCommit(projectPath, message string) error {
+func GitCommit(projectPath, message string, paths ...string) error {
 	if !isGitAvailable() || !isGitRepo(projectPath) {
 		return nil
 	}
 	gitMu.Lock()
 	defer gitMu.Unlock()
 
-	cmd := exec.Command("git", "-C", projectPath, "commit", "-m", message)
+	args := []string{"-C", projectPath, "commit", "-m", message}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	cmd := exec.Command("git", args...)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		outStr := strings.TrimSpace(string(out))
@@ -100,9 +98,9 @@ func GitRestore(projectPath, filePath string) error {
 }
 
 // gitCommitTask adds and commits task-related files for a single task.
-// No-op in batch mode.
-func gitCommitTask(projectPath string, taskID int, action string) {
-	if IsBatchMode() {
+// No-op in batch mode (ctx of a traversal, see withBatch).
+func gitCommitTask(ctx context.Context, projectPath string, taskID int, action string) {
+	if batchFromContext(ctx) {
 		return
 	}
 	if !isGitAvailable() || !isGitRepo(projectPath) {
@@ -111,6 +109,7 @@ func gitCommitTask(projectPath string, taskID int, action string) {
 
 	taskDir := filepath.Join(".claribot", taskDirName)
 	idStr := fmt.Sprintf("%d", taskID)
+	var staged []string
 
 	if action == "deleted" {
 		// Stage deleted files with git add -u
@@ -119,11 +118,16 @@ func gitCommitTask(projectPath string, taskID int, action string) {
 			filepath.Join(taskDir, idStr+".plan.md"),
 			filepath.Join(taskDir, idStr+".report.md"),
 			filepath.Join(taskDir, idStr+".error.md"),
+			filepath.Join(taskDir, idStr+".verify.md"),
+			filepath.Join(taskDir, idStr+".diff"),
+			filepath.Join(taskDir, idStr+".review.md"),
 		}
 		for _, p := range paths {
 			if err := GitAdd(projectPath, p); err != nil {
 				log.Printf("[Task] git add 실패 (%s): %v", p, err)
+				continue
 			}
+			staged = append(staged, p)
 		}
 	} else {
 		// Stage existing task files
@@ -132,21 +136,57 @@ func gitCommitTask(projectPath string, taskID int, action string) {
 			filepath.Join(taskDir, idStr+".plan.md"),
 			filepath.Join(taskDir, idStr+".report.md"),
 			filepath.Join(taskDir, idStr+".error.md"),
+			filepath.Join(taskDir, idStr+".verify.md"),
+			filepath.Join(taskDir, idStr+".diff"),
+			filepath.Join(taskDir, idStr+".review.md"),
 		}
 		for _, f := range files {
 			if err := GitAdd(projectPath, f); err != nil {
 				// Ignore errors for files that don't exist
 				continue
 			}
+			staged = append(staged, f)
 		}
 	}
 
+	if len(staged) == 0 {
+		return
+	}
+
+	// Commit only this task's files, not whatever else happens to be staged
 	msg := fmt.Sprintf("task(#%d): %s", taskID, action)
-	if err := GitCommit(projectPath, msg); err != nil {
+	if err := GitCommit(projectPath, msg, staged...); err != nil {
 		log.Printf("[Task] git commit 실패 (task #%d, %s): %v", taskID, action, err)
 	}
 }
 
+// gitCommitTasks commits the task files (.md) of several tasks in one commit.
+// Used when one operation rewrites multiple tasks (e.g. move). No-op in batch mode.
+func gitCommitTasks(ctx context.Context, projectPath string, taskIDs []int, message string) {
+	if batchFromContext(ctx) {
+		return
+	}
+	if !isGitAvailable() || !isGitRepo(projectPath) {
+		return
+	}
+
+	taskDir := filepath.Join(".claribot", taskDirName)
+	var staged []string
+	for _, id := range taskIDs {
+		f := filepath.Join(taskDir, fmt.Sprintf("%d.md", id))
+		if err := GitAdd(projectPath, f); err != nil {
+			continue
+		}
+		staged = append(staged, f)
+	}
+	if len(staged) == 0 {
+		return
+	}
+	if err := GitCommit(projectPath, message, staged...); err != nil {
+		log.Printf("[Task] git commit 실패 (%s): %v", message, err)
+	}
+}
+
 // gitCommitBatch commits all task files in a single batch commit.
 func gitCommitBatch(projectPath, message string) {
 	if !isGitAvailable() || !isGitRepo(projectPath) {
@@ -158,7 +198,7 @@ func gitCommitBatch(projectPath, message string) {
 		log.Printf("[Task] git add 실패 (batch): %v", err)
 		return
 	}
-	if err := GitCommit(projectPath, message); err != nil {
+	if err := GitCommit(projectPath, message, taskDir); err != nil {
 		log.Printf("[Task] git commit 실패 (batch): %v", err)
 	}
 }
@@ -0,0 +1,785 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+)
+
+// DefaultGraphLayers is the default layer ordering (lowest first).
+// A node in a later layer may depend on nodes in earlier layers.
+var DefaultGraphLayers = []string{"component", "table", "model", "service", "api", "page"}
+
+// sharedGraphLayers hold nodes shared across features (e.g. component-RadioGroup).
+// They are not generated per feature by default, only created when referenced.
+var sharedGraphLayers = map[string]bool{"component": true}
+
+var (
+	layerNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	featureIDPattern = regexp.MustCompile(`^[A-Za-z0-9]+(?:[-_][A-Za-z0-9]+)*$`)
+)
+
+// GraphSpec is the input of a Node Graph build (YAML or JSON).
+// Layers overrides the project layer ordering; FeatureLayers is the default
+// set of layers generated for a feature that does not list its own.
+type GraphSpec struct {
+	Layers        []string       `yaml:"layers,omitempty" json:"layers,omitempty"`
+	FeatureLayers []string       `yaml:"feature_layers,omitempty" json:"feature_layers,omitempty"`
+	Features      []GraphFeature `yaml:"features" json:"features"`
+}
+
+// GraphFeature describes one feature of the graph.
+// Spec is inline text; SpecFile (relative to the project) takes priority if set.
+type GraphFeature struct {
+	ID       string   `yaml:"id" json:"id"`
+	Title    string   `yaml:"title,omitempty" json:"title,omitempty"`
+	Spec     string   `yaml:"spec,omitempty" json:"spec,omitempty"`
+	SpecFile string   `yaml:"spec_file,omitempty" json:"spec_file,omitempty"`
+	Layers   []string `yaml:"layers,omitempty" json:"layers,omitempty"`
+	Refs     []string `yaml:"refs,omitempty" json:"refs,omitempty"`
+}
+
+// GraphNode is a persisted node of the graph, as returned by ListGraph.
+type GraphNode struct {
+	TaskID    int      `json:"task_id"`
+	NodeID    string   `json:"node_id"`
+	FeatureID string   `json:"feature_id"`
+	Layer     string   `json:"layer"`
+	Status    string   `json:"status"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// nodeDraft is a node being assembled during a graph build.
+type nodeDraft struct {
+	ID      string
+	Feature string
+	Layer   string
+	Spec    string
+	Deps    []string
+	Empty   bool            // forward reference, resolved in pass 2
+	TaskID  int             // persisted task (0 = not yet created)
+	isNew   bool            // created by this build
+	added   bool            // new edges were added to an existing node
+	refsBy  map[string]bool // nodes referencing this one
+}
+
+// graphBuild holds the state of a 2-pass graph build.
+type graphBuild struct {
+	layers     []string
+	layerIndex map[string]int
+	nodes      map[string]*nodeDraft
+	features   map[string]*GraphFeature
+	specs      map[string]string // feature ID → spec text
+	refPattern *regexp.Regexp
+	edges      int
+	warnings   []string
+}
+
+// ParseLayers parses a layer ordering like "table, model, service".
+func ParseLayers(value string) ([]string, error) {
+	var layers []string
+	seen := make(map[string]bool)
+	for _, f := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		if !layerNamePattern.MatchString(f) {
+			return nil, fmt.Errorf("잘못된 layer 이름: %s (소문자, 숫자, _ 만 허용)", f)
+		}
+		if seen[f] {
+			return nil, fmt.Errorf("중복된 layer: %s", f)
+		}
+		seen[f] = true
+		layers = append(layers, f)
+	}
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("layer가 비어 있습니다")
+	}
+	return layers, nil
+}
+
+// getGraphLayers reads the graph_layers config from project local DB.
+func getGraphLayers(localDB *db.DB) []string {
+	var val string
+	if err := localDB.QueryRow("SELECT value FROM config WHERE key = 'graph_layers'").Scan(&val); err != nil {
+		return DefaultGraphLayers
+	}
+	layers, err := ParseLayers(val)
+	if err != nil {
+		return DefaultGraphLayers
+	}
+	return layers
+}
+
+// parseNodeID splits "{layer}-{feature_id}" at the first dash.
+func parseNodeID(nodeID string) (layer, feature string, ok bool) {
+	idx := strings.Index(nodeID, "-")
+	if idx <= 0 {
+		return "", "", false
+	}
+	layer, feature = nodeID[:idx], nodeID[idx+1:]
+	if !layerNamePattern.MatchString(layer) || !featureIDPattern.MatchString(feature) {
+		return "", "", false
+	}
+	return layer, feature, true
+}
+
+// LoadGraphSpec reads a graph spec file (YAML or JSON).
+func LoadGraphSpec(path string) (*GraphSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("그래프 파일 읽기 실패: %w", err)
+	}
+	var spec GraphSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("그래프 파일 파싱 실패: %w", err)
+	}
+	return &spec, nil
+}
+
+func newGraphBuild(layers []string) *graphBuild {
+	b := &graphBuild{
+		layers:     layers,
+		layerIndex: make(map[string]int, len(layers)),
+		nodes:      make(map[string]*nodeDraft),
+		features:   make(map[string]*GraphFeature),
+		specs:      make(map[string]string),
+	}
+	quoted := make([]string, len(layers))
+	for i, l := range layers {
+		b.layerIndex[l] = i
+		quoted[i] = regexp.QuoteMeta(l)
+	}
+	// Node references in spec text: "api-OWN001", "component-RadioGroup"
+	b.refPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_-])(` + strings.Join(quoted, "|") + `)-([A-Za-z0-9]+(?:[-_][A-Za-z0-9]+)*)`)
+	return b
+}
+
+// warnf records a non-fatal build warning.
+func (b *graphBuild) warnf(format string, args ...interface{}) {
+	b.warnings = append(b.warnings, fmt.Sprintf(format, args...))
+}
+
+// reaches reports whether to is reachable from from along dependency edges.
+func (b *graphBuild) reaches(from, to string, visited map[string]bool) bool {
+	if from == to {
+		return true
+	}
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+	if n, ok := b.nodes[from]; ok {
+		for _, dep := range n.Deps {
+			if b.reaches(dep, to, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addEdge adds from → to unless it already exists or would form a cycle.
+func (b *graphBuild) addEdge(from, to string) {
+	n := b.nodes[from]
+	for _, dep := range n.Deps {
+		if dep == to {
+			return
+		}
+	}
+	if b.reaches(to, from, make(map[string]bool)) {
+		b.warnf("순환 edge 무시: %s → %s", from, to)
+		return
+	}
+	n.Deps = append(n.Deps, to)
+	if n.TaskID != 0 {
+		n.added = true
+	}
+	b.edges++
+}
+
+// topNode returns the node of a feature in its highest layer, or nil if it has none.
+func (b *graphBuild) topNode(feature string) *nodeDraft {
+	for i := len(b.layers) - 1; i >= 0; i-- {
+		if n, ok := b.nodes[b.layers[i]+"-"+feature]; ok {
+			return n
+		}
+	}
+	return nil
+}
+
+// addNode creates a node unless one with the same ID already exists.
+func (b *graphBuild) addNode(layer, feature string, empty bool) *nodeDraft {
+	id := layer + "-" + feature
+	if n, ok := b.nodes[id]; ok {
+		return n
+	}
+	n := &nodeDraft{ID: id, Feature: feature, Layer: layer, Empty: empty, refsBy: make(map[string]bool)}
+	b.nodes[id] = n
+	return n
+}
+
+// scanRefs extracts node references to other features from spec text.
+func (b *graphBuild) scanRefs(feature, text string) []string {
+	var refs []string
+	for _, m := range b.refPattern.FindAllStringSubmatch(text, -1) {
+		if m[2] != feature {
+			refs = append(refs, m[1]+"-"+m[2])
+		}
+	}
+	return refs
+}
+
+// pass1 creates feature × layer nodes with vertical edges, then wires
+// cross-feature edges from refs and spec text. Unknown targets become empty nodes.
+func (b *graphBuild) pass1(features []GraphFeature, featureLayers []string) error {
+	for _, l := range featureLayers {
+		if _, ok := b.layerIndex[l]; !ok {
+			return fmt.Errorf("feature_layers: 알 수 없는 layer: %s (layers: %s)", l, strings.Join(b.layers, ", "))
+		}
+	}
+	for i := range features {
+		f := &features[i]
+		if !featureIDPattern.MatchString(f.ID) {
+			return fmt.Errorf("잘못된 feature ID: %q", f.ID)
+		}
+		if _, dup := b.features[f.ID]; dup {
+			return fmt.Errorf("중복된 feature ID: %s", f.ID)
+		}
+		b.features[f.ID] = f
+
+		layers := f.Layers
+		if len(layers) == 0 {
+			layers = featureLayers
+		}
+		want := make(map[string]bool)
+		for _, l := range layers {
+			if _, ok := b.layerIndex[l]; !ok {
+				return fmt.Errorf("%s: 알 수 없는 layer: %s (layers: %s)", f.ID, l, strings.Join(b.layers, ", "))
+			}
+			want[l] = true
+		}
+
+		// Vertical edges: each layer depends on the previous layer of the same feature
+		var prev *nodeDraft
+		for _, l := range b.layers {
+			if len(want) > 0 && !want[l] || len(want) == 0 && sharedGraphLayers[l] {
+				continue
+			}
+			n := b.addNode(l, f.ID, false)
+			n.Empty = false
+			if prev != nil {
+				b.addEdge(n.ID, prev.ID)
+			}
+			prev = n
+		}
+	}
+
+	// Horizontal edges: resolved after all features exist so forward references work
+	for _, f := range features {
+		refs := append(append([]string(nil), f.Refs...), b.scanRefs(f.ID, b.specs[f.ID])...)
+		for _, ref := range refs {
+			layer, feature, ok := parseNodeID(ref)
+			if !ok {
+				return fmt.Errorf("%s: 잘못된 node 참조: %s", f.ID, ref)
+			}
+			idx, known := b.layerIndex[layer]
+			if !known {
+				return fmt.Errorf("%s: 알 수 없는 layer 참조: %s", f.ID, ref)
+			}
+			if feature == f.ID {
+				continue
+			}
+			// The referencing node is the feature's top node (page → api, page → component,
+			// page → page); a lower layer never depends on a higher one.
+			from := b.topNode(f.ID)
+			if from == nil || b.layerIndex[from.Layer] < idx {
+				b.warnf("%s: %s를 참조할 상위 layer 노드 없음", f.ID, ref)
+				continue
+			}
+			to := b.addNode(layer, feature, true)
+			b.addEdge(from.ID, to.ID)
+			to.refsBy[from.ID] = true
+		}
+	}
+	return nil
+}
+
+// pass2 resolves empty (forward-referenced) nodes in topological order:
+// the spec is filled from the owning feature's layer section and the nodes that reference it.
+func (b *graphBuild) pass2(order []string) {
+	for _, id := range order {
+		n := b.nodes[id]
+		if !n.Empty || n.TaskID != 0 {
+			continue
+		}
+		var sb strings.Builder
+		if f, ok := b.features[n.Feature]; ok {
+			// Known feature without this layer: hook it onto the feature's nearest lower node
+			for i := b.layerIndex[n.Layer] - 1; i >= 0; i-- {
+				if lower, ok := b.nodes[b.layers[i]+"-"+n.Feature]; ok {
+					b.addEdge(n.ID, lower.ID)
+					break
+				}
+			}
+			sb.WriteString(nodeHeader(n, f))
+			if section, found := extractLayerSection(b.specs[n.Feature], n.Layer); found {
+				sb.WriteString(section)
+				sb.WriteString("\n\n")
+			}
+		} else {
+			sb.WriteString(nodeHeader(n, nil))
+			sb.WriteString("기획서 없음 (forward reference). 아래 노드가 이 노드를 참조합니다.\n\n")
+		}
+		sb.WriteString("## 참조하는 노드\n")
+		refs := make([]string, 0, len(n.refsBy))
+		for ref := range n.refsBy {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		for _, ref := range refs {
+			sb.WriteString("- " + ref)
+			if rf, ok := b.features[b.nodes[ref].Feature]; ok && rf.Title != "" {
+				sb.WriteString(" (" + rf.Title + ")")
+			}
+			sb.WriteString("\n")
+		}
+		n.Spec = strings.TrimSpace(sb.String())
+	}
+}
+
+// fillSpecs sets the spec of every non-empty new node from its feature spec.
+func (b *graphBuild) fillSpecs() {
+	for _, n := range b.nodes {
+		if n.Empty || n.TaskID != 0 {
+			continue
+		}
+		f := b.features[n.Feature]
+		text := b.specs[n.Feature]
+		if section, found := extractLayerSection(text, n.Layer); found {
+			text = section
+		}
+		n.Spec = strings.TrimSpace(nodeHeader(n, f) + text)
+	}
+}
+
+// nodeHeader renders the common header of a node spec.
+func nodeHeader(n *nodeDraft, f *GraphFeature) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("- Node: %s\n- Feature: %s", n.ID, n.Feature))
+	if f != nil && f.Title != "" {
+		sb.WriteString(" " + f.Title)
+	}
+	sb.WriteString(fmt.Sprintf("\n- Layer: %s\n", n.Layer))
+	if f != nil && f.SpecFile != "" {
+		sb.WriteString(fmt.Sprintf("- 기획서: `%s`\n", f.SpecFile))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// extractLayerSection returns the markdown section whose heading names the layer
+// (e.g. "## api" or "### API Endpoint" for layer "api"), up to the next heading of the same or higher level.
+func extractLayerSection(spec, layer string) (string, bool) {
+	lines := strings.Split(spec, "\n")
+	start, level := -1, 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		lv := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		if lv == 0 || lv > 6 {
+			continue
+		}
+		if start >= 0 {
+			if lv <= level {
+				return strings.TrimSpace(strings.Join(lines[start:i], "\n")), true
+			}
+			continue
+		}
+		heading := strings.ToLower(strings.TrimSpace(trimmed[lv:]))
+		if heading == layer || strings.HasPrefix(heading, layer+" ") {
+			start, level = i, lv
+		}
+	}
+	if start < 0 {
+		return "", false
+	}
+	return strings.TrimSpace(strings.Join(lines[start:], "\n")), true
+}
+
+// topoOrder returns node IDs in dependency order. Ties are broken by layer
+// ordering, then node ID, so all nodes of a lower layer come first where possible.
+func (b *graphBuild) topoOrder() ([]string, error) {
+	indegree := make(map[string]int, len(b.nodes))
+	dependents := make(map[string][]string)
+	for id, n := range b.nodes {
+		if _, ok := indegree[id]; !ok {
+			indegree[id] = 0
+		}
+		for _, dep := range n.Deps {
+			indegree[id]++
+			dependents[dep] = append(dependents[dep], id)
+		}
+	}
+
+	less := func(a, c string) bool {
+		la, lc := b.layerIndex[b.nodes[a].Layer], b.layerIndex[b.nodes[c].Layer]
+		if la != lc {
+			return la < lc
+		}
+		return a < c
+	}
+
+	var ready, order []string
+	for id, d := range indegree {
+		if d == 0 {
+			ready = append(ready, id)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		for _, next := range dependents[id] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+	if len(order) != len(b.nodes) {
+		return nil, fmt.Errorf("그래프에 순환이 있습니다")
+	}
+	return order, nil
+}
+
+// loadGraphNodes returns all persisted graph nodes keyed by node ID.
+func loadGraphNodes(localDB *db.DB) (map[string]*GraphNode, error) {
+	rows, err := localDB.Query(`
+		SELECT g.task_id, g.node_id, g.feature_id, g.layer, t.status
+		FROM graph_nodes g JOIN tasks t ON t.id = g.task_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("graph_nodes 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	nodes := make(map[string]*GraphNode)
+	for rows.Next() {
+		var n GraphNode
+		if err := rows.Scan(&n.TaskID, &n.NodeID, &n.FeatureID, &n.Layer, &n.Status); err != nil {
+			return nil, fmt.Errorf("graph_nodes 스캔 실패: %w", err)
+		}
+		nodes[n.NodeID] = &n
+	}
+	return nodes, rows.Err()
+}
+
+// lookupGraphNode returns the node ID of a task, or "" if it is not a graph node.
+func lookupGraphNode(localDB *db.DB, taskID int) string {
+	var nodeID string
+	if err := localDB.QueryRow(`SELECT node_id FROM graph_nodes WHERE task_id = ?`, taskID).Scan(&nodeID); err != nil {
+		return ""
+	}
+	return nodeID
+}
+
+// BuildGraph generates graph nodes as tasks from a feature list (2-pass build).
+// Existing nodes are kept as-is; only new nodes and new edges are added.
+func BuildGraph(projectPath string, spec *GraphSpec) types.Result {
+	if spec == nil || len(spec.Features) == 0 {
+		return types.Result{Success: false, Message: "feature 목록이 비어 있습니다"}
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	layers := getGraphLayers(localDB)
+	if len(spec.Layers) > 0 {
+		if layers, err = ParseLayers(strings.Join(spec.Layers, ",")); err != nil {
+			return types.Result{Success: false, Message: fmt.Sprintf("layer 오류: %v", err)}
+		}
+	}
+
+	b := newGraphBuild(layers)
+
+	// Seed with persisted nodes so rebuilding the same list is idempotent
+	existing, err := loadGraphNodes(localDB)
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+	depMap, err := loadDepMap(localDB)
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+	byTask := make(map[int]string, len(existing))
+	for _, n := range existing {
+		byTask[n.TaskID] = n.NodeID
+		if _, ok := b.layerIndex[n.Layer]; !ok {
+			b.layerIndex[n.Layer] = -1 // layer no longer configured: keep node, never reference it
+		}
+		b.nodes[n.NodeID] = &nodeDraft{ID: n.NodeID, Feature: n.FeatureID, Layer: n.Layer, TaskID: n.TaskID, refsBy: make(map[string]bool)}
+	}
+	for _, n := range existing {
+		for _, dep := range depMap[n.TaskID] {
+			if depNode, ok := byTask[dep]; ok {
+				b.nodes[n.NodeID].Deps = append(b.nodes[n.NodeID].Deps, depNode)
+			}
+		}
+	}
+
+	// Load feature specs (spec_file takes priority over inline spec)
+	for _, f := range spec.Features {
+		text := f.Spec
+		if f.SpecFile != "" {
+			path := f.SpecFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(projectPath, path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return types.Result{Success: false, Message: fmt.Sprintf("%s: 기획서 읽기 실패: %v", f.ID, err)}
+			}
+			text = string(data)
+		}
+		b.specs[f.ID] = text
+	}
+
+	if err := b.pass1(spec.Features, spec.FeatureLayers); err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("그래프 생성 실패: %v", err)}
+	}
+	b.fillSpecs()
+	order, err := b.topoOrder()
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("그래프 생성 실패: %v", err)}
+	}
+	b.pass2(order)
+	// pass2 may hook empty nodes onto lower layers: recompute the order
+	if order, err = b.topoOrder(); err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("그래프 생성 실패: %v", err)}
+	}
+
+	// Persist new nodes in topological order so task IDs follow execution order
+	tx, err := localDB.Begin()
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("트랜잭션 시작 실패: %v", err)}
+	}
+	now := db.TimeNow()
+	var created, changed []*nodeDraft
+	for _, id := range order {
+		n := b.nodes[id]
+		if n.TaskID != 0 {
+			continue
+		}
+		res, err := tx.Exec(`
+			INSERT INTO tasks (title, status, is_leaf, depth, created_at, updated_at)
+			VALUES (?, 'todo', 1, 0, ?, ?)
+		`, n.ID, now, now)
+		if err != nil {
+			tx.Rollback()
+			return types.Result{Success: false, Message: fmt.Sprintf("노드 추가 실패 (%s): %v", n.ID, err)}
+		}
+		taskID, _ := res.LastInsertId()
+		n.TaskID = int(taskID)
+		n.isNew = true
+		if _, err := tx.Exec(`INSERT INTO graph_nodes (task_id, node_id, feature_id, layer) VALUES (?, ?, ?, ?)`,
+			n.TaskID, n.ID, n.Feature, n.Layer); err != nil {
+			tx.Rollback()
+			return types.Result{Success: false, Message: fmt.Sprintf("노드 저장 실패 (%s): %v", n.ID, err)}
+		}
+		created = append(created, n)
+	}
+	for _, id := range order {
+		n := b.nodes[id]
+		if !n.isNew && !n.added {
+			continue
+		}
+		for _, dep := range n.Deps {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO task_deps (task_id, depends_on, created_at) VALUES (?, ?, ?)`,
+				n.TaskID, b.nodes[dep].TaskID, now); err != nil {
+				tx.Rollback()
+				return types.Result{Success: false, Message: fmt.Sprintf("edge 저장 실패 (%s → %s): %v", n.ID, dep, err)}
+			}
+		}
+		if n.added {
+			changed = append(changed, n)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("트랜잭션 커밋 실패: %v", err)}
+	}
+
+	// Dual-write: files for new nodes, depends_on frontmatter for existing nodes with new edges
+	for _, n := range created {
+		deps := make([]int, 0, len(n.Deps))
+		for _, dep := range n.Deps {
+			deps = append(deps, b.nodes[dep].TaskID)
+		}
+		sort.Ints(deps)
+		fm := Frontmatter{Status: "todo", DependsOn: deps, Node: n.ID}
+		if err := WriteTaskContent(projectPath, n.TaskID, fm, n.ID, n.Spec); err != nil {
+			log.Printf("[Graph] task 파일 생성 실패 (#%d): %v", n.TaskID, err)
+		}
+	}
+	for _, n := range changed {
+		deps, err := loadDependencies(localDB, n.TaskID)
+		if err != nil {
+			log.Printf("[Graph] 선행 작업 조회 실패 (%s): %v", n.ID, err)
+			continue
+		}
+		if err := updateTaskFileDependsOn(projectPath, n.TaskID, deps); err != nil {
+			log.Printf("[Graph] task 파일 depends_on 갱신 실패 (#%d): %v", n.TaskID, err)
+		}
+	}
+	gitCommitBatch(projectPath, fmt.Sprintf("graph: +%d nodes, +%d edges", len(created), b.edges))
+
+	msg := fmt.Sprintf("🕸️ Node Graph 생성 완료: 노드 %d개 추가, edge %d개 추가 (전체 노드 %d개)\nlayers: %s",
+		len(created), b.edges, len(b.nodes), strings.Join(layers, " → "))
+	if len(b.warnings) > 0 {
+		msg += "\n\n⚠️ 경고:"
+		for _, w := range b.warnings {
+			msg += "\n  - " + w
+		}
+	}
+	msg += "\n[그래프:task graph][순회:task cycle]"
+
+	return types.Result{
+		Success: true,
+		Message: msg,
+	}
+}
+
+// ListGraph shows graph nodes grouped by layer, in execution order.
+func ListGraph(projectPath string) types.Result {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	nodes, err := loadGraphNodes(localDB)
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+	if len(nodes) == 0 {
+		return types.Result{
+			Success: true,
+			Message: "Node Graph가 없습니다. 'task graph build <file>'로 생성하세요.",
+			Data:    []GraphNode{},
+		}
+	}
+	depMap, err := loadDepMap(localDB)
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	layers := getGraphLayers(localDB)
+	b := newGraphBuild(layers)
+	byTask := make(map[int]string, len(nodes))
+	for _, n := range nodes {
+		byTask[n.TaskID] = n.NodeID
+		if _, ok := b.layerIndex[n.Layer]; !ok {
+			b.layerIndex[n.Layer] = len(layers) // unconfigured layers sort last
+		}
+		b.nodes[n.NodeID] = &nodeDraft{ID: n.NodeID, Layer: n.Layer, TaskID: n.TaskID}
+	}
+	edges := 0
+	for _, n := range nodes {
+		for _, dep := range depMap[n.TaskID] {
+			if depNode, ok := byTask[dep]; ok {
+				n.DependsOn = append(n.DependsOn, depNode)
+				b.nodes[n.NodeID].Deps = append(b.nodes[n.NodeID].Deps, depNode)
+				edges++
+			}
+		}
+	}
+	order, err := b.topoOrder()
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🕸️ Node Graph: 노드 %d개, edge %d개\nlayers: %s\n", len(nodes), edges, strings.Join(layers, " → ")))
+	list := make([]GraphNode, 0, len(order))
+	lastLayer := ""
+	for _, id := range order {
+		n := nodes[id]
+		if n.Layer != lastLayer {
+			sb.WriteString(fmt.Sprintf("\n[%s]\n", n.Layer))
+			lastLayer = n.Layer
+		}
+		sb.WriteString(fmt.Sprintf("%s #%d %s", statusToIcon(n.Status), n.TaskID, n.NodeID))
+		if len(n.DependsOn) > 0 {
+			sb.WriteString(" ← " + strings.Join(n.DependsOn, ", "))
+		}
+		sb.WriteString("\n")
+		list = append(list, *n)
+	}
+	sb.WriteString("\n[순회:task cycle]")
+
+	return types.Result{
+		Success: true,
+		Message: sb.String(),
+		Data:    list,
+	}
+}
+
+// BuildUpstreamContext renders the direct upstream results of a task for prompt injection.
+// Each dependency contributes its report, or its plan if it has not run yet.
+func BuildUpstreamContext(localDB *db.DB, projectPath string, taskID int) (string, error) {
+	deps, err := loadDependencies(localDB, taskID)
+	if err != nil {
+		return "", err
+	}
+	if len(deps) == 0 {
+		return "(선행 노드 없음)", nil
+	}
+
+	var sb strings.Builder
+	for _, dep := range deps {
+		var title, status string
+		if err := localDB.QueryRow(`SELECT title, status FROM tasks WHERE id = ?`, dep).Scan(&title, &status); err != nil {
+			return "", fmt.Errorf("선행 작업 조회 실패 (#%d): %w", dep, err)
+		}
+		sb.WriteString(fmt.Sprintf("### %s (#%d, %s)\n\n", title, dep, status))
+		if report, err := ReadReportContent(projectPath, dep); err == nil && report != "" {
+			sb.WriteString(strings.TrimSpace(report))
+		} else if plan, err := ReadPlanContent(projectPath, dep); err == nil && plan != "" {
+			sb.WriteString("(아직 실행되지 않음 — 계획)\n\n")
+			sb.WriteString(strings.TrimSpace(plan))
+		} else {
+			sb.WriteString("(아직 결과 없음)")
+		}
+		sb.WriteString("\n\n")
+	}
+	return sb.String(), nil
+}
+
+// buildPromptContext returns the context injected into plan/run prompts.
+// Graph nodes get only their direct upstream results; other tasks get the tree summary
+// (limited to the task's labels when the tree is larger than context_map_limit).
+func buildPromptContext(localDB *db.DB, projectPath string, taskID int) (contextMap, upstream string, err error) {
+	if lookupGraphNode(localDB, taskID) != "" {
+		upstream, err = BuildUpstreamContext(localDB, projectPath, taskID)
+		return "", upstream, err
+	}
+	contextMap, err = buildTaskContextMap(localDB, taskID)
+	return contextMap, "", err
+}
@@ -0,0 +1,190 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestParseLayers(t *testing.T) {
+	layers, err := ParseLayers("table, model service,api")
+	if err != nil {
+		t.Fatalf("ParseLayers failed: %v", err)
+	}
+	if strings.Join(layers, ",") != "table,model,service,api" {
+		t.Errorf("Expected [table model service api], got %v", layers)
+	}
+
+	for _, bad := range []string{"", "table,table", "Table", "api-v2"} {
+		if _, err := ParseLayers(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestExtractLayerSection(t *testing.T) {
+	spec := "# 건물 등록\n\n개요\n\n## API Endpoint\nPOST /buildings\n\n### 응답\n201\n\n## Page\n등록 화면"
+
+	section, found := extractLayerSection(spec, "api")
+	if !found {
+		t.Fatal("Expected api section to be found")
+	}
+	if !strings.Contains(section, "POST /buildings") || !strings.Contains(section, "201") || strings.Contains(section, "등록 화면") {
+		t.Errorf("Unexpected api section: %q", section)
+	}
+
+	if _, found := extractLayerSection(spec, "table"); found {
+		t.Error("Expected no table section")
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	spec := &GraphSpec{
+		Layers: []string{"component", "table", "api", "page"},
+		Features: []GraphFeature{
+			{ID: "OWN006", Title: "건물 사진 등록", Spec: "건물 조회는 api-OWN001을 호출한다.", Refs: []string{"component-FileUpload"}},
+			{ID: "OWN001", Title: "건물 등록", Spec: "## table\nbuildings\n\n## page\n등록 화면"},
+		},
+	}
+	result := BuildGraph(projectPath, spec)
+	if !result.Success {
+		t.Fatalf("BuildGraph failed: %s", result.Message)
+	}
+
+	list := ListGraph(projectPath)
+	if !list.Success {
+		t.Fatalf("ListGraph failed: %s", list.Message)
+	}
+	nodes := list.Data.([]GraphNode)
+	byID := make(map[string]GraphNode)
+	pos := make(map[string]int)
+	for i, n := range nodes {
+		byID[n.NodeID] = n
+		pos[n.NodeID] = i
+	}
+
+	// 3 layers × 2 features (component is shared) + forward-referenced component
+	if len(nodes) != 7 {
+		t.Fatalf("Expected 7 nodes, got %d: %v", len(nodes), nodes)
+	}
+	if _, ok := byID["component-OWN001"]; ok {
+		t.Error("Shared layer should not be generated per feature")
+	}
+
+	// Vertical and horizontal edges
+	expectDeps := map[string][]string{
+		"api-OWN001":  {"table-OWN001"},
+		"page-OWN001": {"api-OWN001"},
+		"page-OWN006": {"api-OWN006", "api-OWN001", "component-FileUpload"},
+	}
+	for id, want := range expectDeps {
+		got := strings.Join(byID[id].DependsOn, ",")
+		for _, dep := range want {
+			if !strings.Contains(got, dep) {
+				t.Errorf("%s: expected dependency on %s, got %v", id, dep, byID[id].DependsOn)
+			}
+		}
+	}
+
+	// Topological order: lower layers first, task IDs follow execution order
+	if pos["component-FileUpload"] > pos["table-OWN001"] || pos["api-OWN001"] > pos["page-OWN006"] {
+		t.Errorf("Unexpected order: %v", nodes)
+	}
+	if byID["table-OWN001"].TaskID > byID["page-OWN001"].TaskID {
+		t.Errorf("Expected task IDs in topological order: %v", nodes)
+	}
+
+	// Node spec holds only its layer section; empty node lists who references it
+	tc, err := ReadTaskContent(projectPath, byID["table-OWN001"].TaskID)
+	if err != nil {
+		t.Fatalf("ReadTaskContent failed: %v", err)
+	}
+	if tc.Frontmatter.Node != "table-OWN001" || !strings.Contains(tc.Body, "buildings") || strings.Contains(tc.Body, "등록 화면") {
+		t.Errorf("Unexpected node file: %+v %q", tc.Frontmatter, tc.Body)
+	}
+	tc, _ = ReadTaskContent(projectPath, byID["component-FileUpload"].TaskID)
+	if tc == nil || !strings.Contains(tc.Body, "page-OWN006") {
+		t.Errorf("Expected forward reference spec to list page-OWN006")
+	}
+
+	// Rebuilding the same list is idempotent
+	if result := BuildGraph(projectPath, spec); !result.Success {
+		t.Fatalf("second BuildGraph failed: %s", result.Message)
+	}
+	if again := ListGraph(projectPath).Data.([]GraphNode); len(again) != len(nodes) {
+		t.Errorf("Expected %d nodes after rebuild, got %d", len(nodes), len(again))
+	}
+}
+
+func TestBuildUpstreamContext(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	spec := &GraphSpec{
+		Layers:   []string{"table", "api"},
+		Features: []GraphFeature{{ID: "OWN001", Spec: "spec"}, {ID: "OWN002", Spec: "spec"}},
+	}
+	if result := BuildGraph(projectPath, spec); !result.Success {
+		t.Fatalf("BuildGraph failed: %s", result.Message)
+	}
+	nodes := ListGraph(projectPath).Data.([]GraphNode)
+	ids := make(map[string]int)
+	for _, n := range nodes {
+		ids[n.NodeID] = n.TaskID
+	}
+	WriteReportContent(projectPath, ids["table-OWN001"], "CREATE TABLE buildings")
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer localDB.Close()
+
+	contextMap, upstream, err := buildPromptContext(localDB, projectPath, ids["api-OWN001"])
+	if err != nil {
+		t.Fatalf("buildPromptContext failed: %v", err)
+	}
+	if contextMap != "" {
+		t.Error("Graph node should not receive the context map")
+	}
+	if !strings.Contains(upstream, "table-OWN001") || !strings.Contains(upstream, "CREATE TABLE buildings") {
+		t.Errorf("Expected upstream report, got %q", upstream)
+	}
+	if strings.Contains(upstream, "OWN002") {
+		t.Errorf("Upstream context leaked unrelated nodes: %q", upstream)
+	}
+
+	// Regular tasks keep the context map
+	Add(projectPath, "Plain task", nil, "spec")
+	contextMap, upstream, _ = buildPromptContext(localDB, projectPath, 5)
+	if contextMap == "" || upstream != "" {
+		t.Errorf("Expected context map for regular task, got map=%q upstream=%q", contextMap, upstream)
+	}
+}
+
+func TestRebuildRestoresGraphNodes(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	spec := &GraphSpec{Layers: []string{"table", "api"}, Features: []GraphFeature{{ID: "OWN001", Spec: "spec"}}}
+	if result := BuildGraph(projectPath, spec); !result.Success {
+		t.Fatalf("BuildGraph failed: %s", result.Message)
+	}
+	if _, err := Rebuild(projectPath); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+
+	nodes := ListGraph(projectPath).Data.([]GraphNode)
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes after rebuild, got %v", nodes)
+	}
+	for _, n := range nodes {
+		if n.NodeID == "api-OWN001" && (len(n.DependsOn) != 1 || n.DependsOn[0] != "table-OWN001") {
+			t.Errorf("Expected api-OWN001 → table-OWN001 after rebuild, got %v", n.DependsOn)
+		}
+	}
+}
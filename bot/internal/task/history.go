@@ -0,0 +1,255 @@
+package task
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+	"parkjunwoo.com/claribot/pkg/pagination"
+)
+
+// Traversal is one plan/run/cycle execution with its totals.
+type Traversal struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"` // cycle | plan | run
+	TargetID   *int            `json:"target_id,omitempty"`
+	Trigger    string          `json:"trigger,omitempty"`
+	Status     string          `json:"status"` // running | done | failed | cancelled | interrupted
+	Total      int             `json:"total"`
+	Success    int             `json:"success"`
+	Failed     int             `json:"failed"`
+	StartedAt  string          `json:"started_at"`
+	FinishedAt string          `json:"finished_at,omitempty"`
+	Items      []TraversalItem `json:"items,omitempty"`
+}
+
+// TraversalItem is one task processed by a traversal.
+type TraversalItem struct {
+	ID         int64  `json:"id"`
+	TaskID     int    `json:"task_id"`
+	Title      string `json:"title"`
+	Phase      string `json:"phase"`  // plan | run
+	Result     string `json:"result"` // planned | split | review | done | failed | cancelled
+	ErrorType  string `json:"error_type,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+}
+
+// ListTraversals lists traversals with pagination, most recent first.
+func ListTraversals(projectPath string, req pagination.PageRequest) types.Result {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	var total int
+	if err := localDB.QueryRow(`SELECT COUNT(*) FROM traversals`).Scan(&total); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("카운트 실패: %v", err),
+		}
+	}
+	if total == 0 {
+		return types.Result{
+			Success: true,
+			Message: "순회 기록이 없습니다.\n[순회:task cycle]",
+		}
+	}
+
+	rows, err := localDB.Query(`
+		SELECT id, type, target_id, trigger, status, total, success, failed, started_at, COALESCE(finished_at, '')
+		FROM traversals
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`, req.Limit(), req.Offset())
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("조회 실패: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	var travs []Traversal
+	for rows.Next() {
+		var tr Traversal
+		if err := rows.Scan(&tr.ID, &tr.Type, &tr.TargetID, &tr.Trigger, &tr.Status, &tr.Total, &tr.Success, &tr.Failed, &tr.StartedAt, &tr.FinishedAt); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("스캔 실패: %v", err),
+			}
+		}
+		travs = append(travs, tr)
+	}
+	if err := rows.Err(); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("행 순회 오류: %v", err),
+		}
+	}
+
+	pageResp := pagination.NewPageResponse(travs, req.Page, req.PageSize, total)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📜 순회 기록 (%d/%d 페이지, 총 %d개)\n", pageResp.Page, pageResp.TotalPages, total))
+	for _, tr := range travs {
+		sb.WriteString(fmt.Sprintf("  %s [#%d:task history %d] %s · %s · 성공 %d, 실패 %d · %s%s\n",
+			historyIcon(tr.Status), tr.ID, tr.ID, traversalLabel(tr), tr.Status, tr.Success, tr.Failed, tr.StartedAt, elapsed(tr.StartedAt, tr.FinishedAt)))
+	}
+
+	if pageResp.HasPrev || pageResp.HasNext {
+		sb.WriteString("\n")
+		if pageResp.HasPrev {
+			sb.WriteString(fmt.Sprintf("[◀ 이전:task history -p %d]", pageResp.Page-1))
+		}
+		if pageResp.HasNext {
+			sb.WriteString(fmt.Sprintf("[다음 ▶:task history -p %d]", pageResp.Page+1))
+		}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: sb.String(),
+		Data:    pageResp,
+	}
+}
+
+// GetTraversal returns a traversal with one item per task it processed.
+func GetTraversal(projectPath, id string) types.Result {
+	travID, err := strconv.ParseInt(strings.TrimPrefix(id, "#"), 10, 64)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("잘못된 순회 ID: %s", id)}
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	var tr Traversal
+	err = localDB.QueryRow(`
+		SELECT id, type, target_id, trigger, status, total, success, failed, started_at, COALESCE(finished_at, '')
+		FROM traversals WHERE id = ?
+	`, travID).Scan(&tr.ID, &tr.Type, &tr.TargetID, &tr.Trigger, &tr.Status, &tr.Total, &tr.Success, &tr.Failed, &tr.StartedAt, &tr.FinishedAt)
+	if err == sql.ErrNoRows {
+		return types.Result{Success: false, Message: fmt.Sprintf("순회를 찾을 수 없습니다: #%d", travID)}
+	}
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("조회 실패: %v", err),
+		}
+	}
+
+	tr.Items, err = loadTraversalItems(localDB, travID)
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📜 순회 #%d: %s · %s %s\n", tr.ID, traversalLabel(tr), historyIcon(tr.Status), tr.Status))
+	sb.WriteString(fmt.Sprintf("시작: %s", tr.StartedAt))
+	if tr.FinishedAt != "" {
+		sb.WriteString(fmt.Sprintf(" → %s%s", tr.FinishedAt, elapsed(tr.StartedAt, tr.FinishedAt)))
+	}
+	sb.WriteString("\n")
+	if tr.Trigger != "" {
+		sb.WriteString(fmt.Sprintf("Trigger: %s\n", tr.Trigger))
+	}
+	sb.WriteString(fmt.Sprintf("결과: 전체 %d, 성공 %d, 실패 %d\n", tr.Total, tr.Success, tr.Failed))
+	if len(tr.Items) == 0 {
+		sb.WriteString("  (처리한 작업 없음)\n")
+	}
+	for _, it := range tr.Items {
+		sb.WriteString(fmt.Sprintf("  %s %s [#%d:task get %d] %s → %s", historyIcon(it.Result), it.Phase, it.TaskID, it.TaskID, it.Title, it.Result))
+		var details []string
+		if it.ErrorType != "" {
+			details = append(details, it.ErrorType)
+		}
+		if it.ExitCode != nil && *it.ExitCode != 0 {
+			details = append(details, fmt.Sprintf("exit %d", *it.ExitCode))
+		}
+		details = append(details, formatDuration(time.Duration(it.DurationMs)*time.Millisecond))
+		sb.WriteString(" (" + strings.Join(details, ", ") + ")\n")
+	}
+	sb.WriteString("[순회 기록:task history]")
+
+	return types.Result{
+		Success: true,
+		Message: sb.String(),
+		Data:    tr,
+	}
+}
+
+// loadTraversalItems returns the items of a traversal in processing order.
+func loadTraversalItems(localDB *db.DB, travID int64) ([]TraversalItem, error) {
+	rows, err := localDB.Query(`
+		SELECT id, task_id, title, phase, result, error_type, exit_code, duration_ms, started_at, finished_at
+		FROM traversal_items WHERE traversal_id = ? ORDER BY id ASC
+	`, travID)
+	if err != nil {
+		return nil, fmt.Errorf("traversal_items 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var items []TraversalItem
+	for rows.Next() {
+		var it TraversalItem
+		if err := rows.Scan(&it.ID, &it.TaskID, &it.Title, &it.Phase, &it.Result, &it.ErrorType, &it.ExitCode, &it.DurationMs, &it.StartedAt, &it.FinishedAt); err != nil {
+			return nil, fmt.Errorf("traversal_items 스캔 실패: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// traversalLabel renders the type and scope of a traversal ("cycle", "run #5 하위", "plan #3").
+func traversalLabel(tr Traversal) string {
+	if tr.TargetID == nil {
+		return tr.Type
+	}
+	if tr.Type == "cycle" {
+		return fmt.Sprintf("cycle #%d 하위", *tr.TargetID)
+	}
+	return fmt.Sprintf("%s #%d", tr.Type, *tr.TargetID)
+}
+
+// historyIcon returns the icon of a traversal status or item result.
+func historyIcon(status string) string {
+	switch status {
+	case "running":
+		return "🔄"
+	case "cancelled":
+		return "🛑"
+	case "interrupted":
+		return "⚠️"
+	case "split":
+		return "📂"
+	default:
+		return statusToIcon(status)
+	}
+}
+
+// elapsed renders the time between two RFC3339 timestamps (" (1m 5s)", "" if unknown).
+func elapsed(start, end string) string {
+	s, err1 := time.Parse(time.RFC3339, start)
+	e, err2 := time.Parse(time.RFC3339, end)
+	if err1 != nil || err2 != nil {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", formatDuration(e.Sub(s)))
+}
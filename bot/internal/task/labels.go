@@ -0,0 +1,136 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+// ParseLabels parses a label list like "backend, auth ui" into sorted, unique labels.
+// Labels are free-form words (no spaces or commas), compared case-insensitively
+// and stored in lower case. An empty string returns an empty (non-nil) slice,
+// meaning "clear labels".
+func ParseLabels(value string) ([]string, error) {
+	labels := []string{}
+	seen := make(map[string]bool)
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	for _, f := range fields {
+		label := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(f), "#"))
+		if label == "" {
+			continue
+		}
+		if len([]rune(label)) > 50 {
+			return nil, fmt.Errorf("라벨이 너무 깁니다 (최대 50자): %s", label)
+		}
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// normalizeLabels cleans labels read from a task file (invalid ones are dropped).
+func normalizeLabels(labels []string) []string {
+	var valid []string
+	for _, l := range labels {
+		if parsed, err := ParseLabels(l); err == nil {
+			valid = append(valid, parsed...)
+		}
+	}
+	parsed, _ := ParseLabels(strings.Join(valid, ","))
+	return parsed
+}
+
+// formatLabels formats labels as "backend, ui" for display.
+func formatLabels(labels []string) string {
+	return strings.Join(labels, ", ")
+}
+
+// loadLabels returns the labels of a task in name order.
+func loadLabels(localDB *db.DB, taskID int) ([]string, error) {
+	rows, err := localDB.Query(`SELECT label FROM task_labels WHERE task_id = ? ORDER BY label`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task_labels 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []string
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return nil, fmt.Errorf("task_labels 스캔 실패: %w", err)
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+// loadLabelMap returns the labels of every task: task ID → labels.
+func loadLabelMap(localDB *db.DB) (map[int][]string, error) {
+	rows, err := localDB.Query(`SELECT task_id, label FROM task_labels ORDER BY task_id, label`)
+	if err != nil {
+		return nil, fmt.Errorf("task_labels 조회 실패: %w", err)
+	}
+	defer rows.Close()
+
+	labelMap := make(map[int][]string)
+	for rows.Next() {
+		var taskID int
+		var label string
+		if err := rows.Scan(&taskID, &label); err != nil {
+			return nil, fmt.Errorf("task_labels 스캔 실패: %w", err)
+		}
+		labelMap[taskID] = append(labelMap[taskID], label)
+	}
+	return labelMap, rows.Err()
+}
+
+// SetLabels replaces the labels of a task in the DB.
+// The caller is responsible for mirroring the change into the task file frontmatter.
+func SetLabels(localDB *db.DB, taskID int, labels []string) error {
+	tx, err := localDB.Begin()
+	if err != nil {
+		return fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM task_labels WHERE task_id = ?`, taskID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("task_labels 삭제 실패: %w", err)
+	}
+	for _, label := range labels {
+		if _, err := tx.Exec(`INSERT INTO task_labels (task_id, label) VALUES (?, ?)`, taskID, label); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("task_labels 저장 실패: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// updateTaskFileLabels reads existing task.md, updates labels, and writes back.
+// If file doesn't exist, does nothing (returns nil).
+func updateTaskFileLabels(projectPath string, id int, labels []string) error {
+	tc, err := ReadTaskContent(projectPath, id)
+	if err != nil {
+		return nil
+	}
+	tc.Frontmatter.Labels = labels
+	return WriteTaskContent(projectPath, id, tc.Frontmatter, tc.Title, tc.Body)
+}
+
+// sameLabels reports whether two sorted label lists are equal.
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,244 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/pkg/pagination"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels("UI, #backend api,ui")
+	if err != nil {
+		t.Fatalf("ParseLabels failed: %v", err)
+	}
+	if strings.Join(labels, ",") != "api,backend,ui" {
+		t.Errorf("Expected [api backend ui], got %v", labels)
+	}
+
+	labels, err = ParseLabels("")
+	if err != nil || labels == nil || len(labels) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %v (err=%v)", labels, err)
+	}
+
+	if _, err := ParseLabels(strings.Repeat("x", 51)); err == nil {
+		t.Error("Expected error for long label")
+	}
+}
+
+func TestSetLabels(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	if result := Set(projectPath, "1", "labels", "backend, Auth", ActorCLI); !result.Success {
+		t.Fatalf("Set labels failed: %s", result.Message)
+	}
+
+	tc, err := ReadTaskContent(projectPath, 1)
+	if err != nil {
+		t.Fatalf("ReadTaskContent failed: %v", err)
+	}
+	if strings.Join(tc.Frontmatter.Labels, ",") != "auth,backend" {
+		t.Errorf("Expected labels [auth backend] in frontmatter, got %v", tc.Frontmatter.Labels)
+	}
+	result := Get(projectPath, "1")
+	if got := result.Data.(*Task); strings.Join(got.Labels, ",") != "auth,backend" {
+		t.Errorf("Expected Labels [auth backend], got %v", got.Labels)
+	}
+	if !strings.Contains(result.Message, "Labels: auth, backend") {
+		t.Errorf("Expected labels in message, got: %s", result.Message)
+	}
+
+	// Children inherit the parent's labels
+	parentID := 1
+	Add(projectPath, "Login", &parentID, "spec")
+	if got := Get(projectPath, "2").Data.(*Task); strings.Join(got.Labels, ",") != "auth,backend" {
+		t.Errorf("Expected child to inherit labels, got %v", got.Labels)
+	}
+
+	// Empty value clears
+	if result := Set(projectPath, "1", "labels", "", ActorCLI); !result.Success {
+		t.Fatalf("Clear labels failed: %s", result.Message)
+	}
+	if got := Get(projectPath, "1").Data.(*Task); len(got.Labels) != 0 {
+		t.Errorf("Expected no labels, got %v", got.Labels)
+	}
+}
+
+func TestSyncLabels(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	tc, _ := ReadTaskContent(projectPath, 1)
+	tc.Frontmatter.Labels = []string{"Backend", "api"}
+	if err := WriteTaskContent(projectPath, 1, tc.Frontmatter, tc.Title, tc.Body); err != nil {
+		t.Fatalf("WriteTaskContent failed: %v", err)
+	}
+
+	if _, err := Sync(projectPath); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := Get(projectPath, "1").Data.(*Task); strings.Join(got.Labels, ",") != "api,backend" {
+		t.Errorf("Expected labels synced from file, got %v", got.Labels)
+	}
+
+	if _, err := Rebuild(projectPath); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	if got := Get(projectPath, "1").Data.(*Task); strings.Join(got.Labels, ",") != "api,backend" {
+		t.Errorf("Expected labels restored by rebuild, got %v", got.Labels)
+	}
+}
+
+func TestListFiltered(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec") // 1
+	root := 1
+	Add(projectPath, "Login page", &root, "spec")    // 2
+	Add(projectPath, "Token refresh", &root, "spec") // 3
+	child := 2
+	Add(projectPath, "Login form", &child, "spec") // 4
+	Add(projectPath, "Billing page", nil, "spec")  // 5
+	Set(projectPath, "2", "labels", "ui", ActorCLI)
+	Set(projectPath, "4", "labels", "ui,forms", ActorCLI)
+	Set(projectPath, "5", "labels", "ui", ActorCLI)
+	Set(projectPath, "3", "status", "done", ActorCLI)
+
+	ids := func(f ListFilter) []int {
+		result := ListFiltered(projectPath, f, "task list", pagination.NewPageRequest(1, 20))
+		if !result.Success {
+			t.Fatalf("ListFiltered failed: %s", result.Message)
+		}
+		if result.Data == nil {
+			return nil
+		}
+		var out []int
+		for _, task := range result.Data.(pagination.PageResponse[Task]).Items {
+			out = append(out, task.ID)
+		}
+		return out
+	}
+	check := func(name string, f ListFilter, want ...int) {
+		t.Helper()
+		got := ids(f)
+		if len(got) != len(want) {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+			return
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: expected %v, got %v", name, want, got)
+				return
+			}
+		}
+	}
+
+	check("label", ListFilter{Labels: []string{"ui"}}, 5, 4, 2)
+	check("labels AND", ListFilter{Labels: []string{"ui", "forms"}}, 4)
+	check("status", ListFilter{Status: []string{"done"}}, 3)
+	depth := 1
+	check("depth", ListFilter{Depth: &depth}, 3, 2)
+	check("subtree", ListFilter{Root: &root}, 4, 3, 2)
+	check("subtree + label", ListFilter{Root: &root, Labels: []string{"ui"}}, 4, 2)
+	check("text", ListFilter{Text: "PAGE"}, 5, 2)
+	check("text wildcard", ListFilter{Text: "%"})
+
+	f, rest, err := ParseListFilter([]string{"5", "--label", "UI", "--status", "todo,planned", "--text", "login form", "-p", "2"})
+	if err != nil {
+		t.Fatalf("ParseListFilter failed: %v", err)
+	}
+	if strings.Join(rest, " ") != "5 -p 2" {
+		t.Errorf("Expected remaining args [5 -p 2], got %v", rest)
+	}
+	if got := f.String(); got != `--label ui --status todo,planned --text "login form"` {
+		t.Errorf("Unexpected filter string: %s", got)
+	}
+	if _, _, err := ParseListFilter([]string{"--status", "bogus"}); err == nil {
+		t.Error("Expected error for unknown status")
+	}
+	if _, _, err := ParseListFilter([]string{"--depth"}); err == nil {
+		t.Error("Expected error for missing value")
+	}
+}
+
+func TestSavedQueries(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	Add(projectPath, "Billing", nil, "spec")
+	Set(projectPath, "2", "labels", "billing", ActorCLI)
+
+	if result := SaveQuery(projectPath, "list", ListFilter{Labels: []string{"billing"}}); result.Success {
+		t.Error("Expected reserved name to fail")
+	}
+	if result := SaveQuery(projectPath, "empty", ListFilter{}); result.Success {
+		t.Error("Expected empty filter to fail")
+	}
+	if result := SaveQuery(projectPath, "Money", ListFilter{Labels: []string{"billing"}}); !result.Success {
+		t.Fatalf("SaveQuery failed: %s", result.Message)
+	}
+
+	result := RunQuery(projectPath, "money", pagination.NewPageRequest(1, 10))
+	if !result.Success {
+		t.Fatalf("RunQuery failed: %s", result.Message)
+	}
+	items := result.Data.(pagination.PageResponse[Task]).Items
+	if len(items) != 1 || items[0].ID != 2 {
+		t.Errorf("Expected task #2, got %+v", items)
+	}
+
+	list := ListQueries(projectPath)
+	if queries := list.Data.([]SavedQuery); len(queries) != 1 || queries[0].Query != "--label billing" {
+		t.Errorf("Unexpected queries: %+v", list.Data)
+	}
+
+	if result := DeleteQuery(projectPath, "money"); !result.Success {
+		t.Fatalf("DeleteQuery failed: %s", result.Message)
+	}
+	if result := RunQuery(projectPath, "money", pagination.NewPageRequest(1, 10)); result.Success {
+		t.Error("Expected deleted query to fail")
+	}
+}
+
+func TestBuildTaskContextMapByLabel(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	root := 1
+	Add(projectPath, "Login", &root, "spec")
+	Add(projectPath, "Billing", nil, "spec")
+	Set(projectPath, "2", "labels", "auth", ActorCLI)
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	// Below the limit the whole tree is used
+	full, _ := buildTaskContextMap(localDB, 2)
+	if !strings.Contains(full, "Billing") {
+		t.Errorf("Expected full tree, got:\n%s", full)
+	}
+
+	localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES (?, ?, ?)", "context_map_limit", "2", db.TimeNow())
+	scoped, err := buildTaskContextMap(localDB, 2)
+	if err != nil {
+		t.Fatalf("buildTaskContextMap failed: %v", err)
+	}
+	if strings.Contains(scoped, "Billing") || !strings.Contains(scoped, "#1 [split] Auth") || !strings.Contains(scoped, "#2 [todo] Login") {
+		t.Errorf("Expected auth subtree with ancestor only, got:\n%s", scoped)
+	}
+
+	// Tasks without labels keep the whole tree
+	if unlabeled, _ := buildTaskContextMap(localDB, 3); !strings.Contains(unlabeled, "Login") {
+		t.Errorf("Expected full tree for unlabeled task, got:\n%s", unlabeled)
+	}
+}
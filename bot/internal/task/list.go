@@ -205,6 +205,8 @@ func statusToIcon(status string) string {
 		return "📝"
 	case "planned":
 		return "📋"
+	case "review":
+		return "🔍"
 	case "done":
 		return "✅"
 	case "failed":
@@ -0,0 +1,220 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+)
+
+// ParseParent parses the target parent of a move ("root", "none" or "0" = top level).
+func ParseParent(value string) (*int, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "#")
+	switch value {
+	case "", "root", "none", "0":
+		return nil, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 0 {
+		return nil, fmt.Errorf("잘못된 작업 ID: %s", value)
+	}
+	return &id, nil
+}
+
+// Move reparents a task and its subtree under newParent (nil = top level).
+// Depth is recomputed for the whole subtree, the old parent goes back to a todo leaf
+// when it has no children left, and the status of both parents is rolled up again.
+// Status changes of the parents are recorded with actor.
+func Move(projectPath, id string, newParent *int, actor string) types.Result {
+	taskID, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("잘못된 작업 ID: %s", id)}
+	}
+	if IsCycleRunning(projectPath) {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("이 프로젝트는 순회 중입니다: %s (순회 종료 후 이동하세요)", getProjectID(projectPath)),
+		}
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("DB 열기 실패: %v", err)}
+	}
+	defer localDB.Close()
+
+	// Load the whole parent chain (needed for cycle check and depth)
+	rows, err := localDB.Query(`SELECT id, parent_id, depth, status, is_leaf FROM tasks`)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("조회 실패: %v", err)}
+	}
+	parentMap := make(map[int]*int)
+	depths := make(map[int]int)
+	children := make(map[int][]int)
+	statuses := make(map[int]string)
+	leaves := make(map[int]bool)
+	for rows.Next() {
+		var tid, depth int
+		var pid *int
+		var status string
+		var isLeaf bool
+		if err := rows.Scan(&tid, &pid, &depth, &status, &isLeaf); err != nil {
+			rows.Close()
+			return types.Result{Success: false, Message: fmt.Sprintf("스캔 실패: %v", err)}
+		}
+		parentMap[tid] = pid
+		depths[tid] = depth
+		statuses[tid] = status
+		leaves[tid] = isLeaf
+		if pid != nil {
+			children[*pid] = append(children[*pid], tid)
+		}
+	}
+	rows.Close()
+
+	oldParent, ok := parentMap[taskID]
+	if !ok {
+		return types.Result{Success: false, Message: fmt.Sprintf("작업을 찾을 수 없습니다: #%d", taskID)}
+	}
+	if newParent != nil {
+		if _, ok := parentMap[*newParent]; !ok {
+			return types.Result{Success: false, Message: fmt.Sprintf("부모 작업을 찾을 수 없습니다: #%d", *newParent)}
+		}
+		// Reject cycles: the new parent must not be the task itself or one of its descendants
+		for cur := newParent; cur != nil; cur = parentMap[*cur] {
+			if *cur == taskID {
+				return types.Result{
+					Success: false,
+					Message: fmt.Sprintf("순환 이동 불가: #%d은(는) #%d 자신이거나 하위 작업입니다", *newParent, taskID),
+				}
+			}
+		}
+	}
+	if sameParent(oldParent, newParent) {
+		return types.Result{Success: false, Message: fmt.Sprintf("작업 #%d은(는) 이미 %s 아래에 있습니다", taskID, formatParent(newParent))}
+	}
+
+	// Collect the subtree and recompute depth with the new parent
+	subtree := []int{taskID}
+	for i := 0; i < len(subtree); i++ {
+		subtree = append(subtree, children[subtree[i]]...)
+	}
+	parentMap[taskID] = newParent
+	newDepths := make(map[int]int, len(subtree))
+	for _, tid := range subtree {
+		d := computeDepth(tid, parentMap)
+		if d > MaxDepth {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("최대 깊이 초과: #%d의 depth가 %d이 됩니다 (MaxDepth %d)", tid, d, MaxDepth),
+			}
+		}
+		newDepths[tid] = d
+	}
+
+	now := db.TimeNow()
+	tx, err := localDB.Begin()
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("트랜잭션 시작 실패: %v", err)}
+	}
+	if _, err := tx.Exec(`UPDATE tasks SET parent_id = ?, updated_at = ? WHERE id = ?`, newParent, now, taskID); err != nil {
+		tx.Rollback()
+		return types.Result{Success: false, Message: fmt.Sprintf("이동 실패: %v", err)}
+	}
+	for _, tid := range subtree {
+		if _, err := tx.Exec(`UPDATE tasks SET depth = ? WHERE id = ?`, newDepths[tid], tid); err != nil {
+			tx.Rollback()
+			return types.Result{Success: false, Message: fmt.Sprintf("depth 갱신 실패: %v", err)}
+		}
+	}
+
+	// Recheck is_leaf on both parents
+	changed := []int{taskID}
+	var notes []string
+	if oldParent != nil && len(children[*oldParent]) == 1 {
+		// Last child moved away: the old parent is a leaf again and needs a plan
+		if _, err := tx.Exec(`UPDATE tasks SET is_leaf = 1, status = CASE WHEN status = 'split' THEN 'todo' ELSE status END, updated_at = ? WHERE id = ?`, now, *oldParent); err != nil {
+			tx.Rollback()
+			return types.Result{Success: false, Message: fmt.Sprintf("이전 부모 갱신 실패: %v", err)}
+		}
+		if statuses[*oldParent] == "split" {
+			recordEvent(tx, *oldParent, "split", "todo", actor, 0)
+		}
+		changed = append(changed, *oldParent)
+		notes = append(notes, fmt.Sprintf("#%d → leaf", *oldParent))
+	}
+	if newParent != nil {
+		// A todo/planned leaf now has a child to wait for; other statuses are left to rollUp
+		if _, err := tx.Exec(`UPDATE tasks SET is_leaf = 0, status = CASE WHEN is_leaf = 1 AND status IN ('todo', 'planned') THEN 'split' ELSE status END, updated_at = ? WHERE id = ?`, now, *newParent); err != nil {
+			tx.Rollback()
+			return types.Result{Success: false, Message: fmt.Sprintf("새 부모 갱신 실패: %v", err)}
+		}
+		if from := statuses[*newParent]; leaves[*newParent] && (from == "todo" || from == "planned") {
+			recordEvent(tx, *newParent, from, "split", actor, 0)
+		}
+		changed = append(changed, *newParent)
+	}
+	if err := tx.Commit(); err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("트랜잭션 커밋 실패: %v", err)}
+	}
+
+	// Dual-write: parent in the moved task's frontmatter, status of the parents
+	if err := updateTaskFileParent(projectPath, taskID, newParent); err != nil {
+		log.Printf("[Task] task 파일 parent 갱신 실패 (#%d): %v", taskID, err)
+	}
+	for _, pid := range changed[1:] {
+		var status string
+		if err := localDB.QueryRow(`SELECT status FROM tasks WHERE id = ?`, pid).Scan(&status); err != nil {
+			continue
+		}
+		if err := updateTaskFileStatus(projectPath, pid, status); err != nil {
+			log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", pid, err)
+		}
+	}
+	gitCommitTasks(context.Background(), projectPath, changed, fmt.Sprintf("task(#%d): moved to %s", taskID, formatParent(newParent)))
+	for _, c := range rollUp(context.Background(), localDB, projectPath) {
+		notes = append(notes, formatRollup(c))
+	}
+
+	msg := fmt.Sprintf("작업 #%d 이동됨: %s → %s (depth %d → %d", taskID, formatParent(oldParent), formatParent(newParent), depths[taskID], newDepths[taskID])
+	if len(subtree) > 1 {
+		msg += fmt.Sprintf(", 하위 %d개 포함", len(subtree)-1)
+	}
+	msg += ")"
+	if len(notes) > 0 {
+		msg += "\n" + strings.Join(notes, "\n")
+	}
+	msg += fmt.Sprintf("\n[조회:task get %d][트리:task list --tree]", taskID)
+
+	return types.Result{Success: true, Message: msg}
+}
+
+// updateTaskFileParent updates the parent field in a task file's frontmatter.
+func updateTaskFileParent(projectPath string, id int, parent *int) error {
+	tc, err := ReadTaskContent(projectPath, id)
+	if err != nil {
+		return nil
+	}
+	tc.Frontmatter.Parent = parent
+	return WriteTaskContent(projectPath, id, tc.Frontmatter, tc.Title, tc.Body)
+}
+
+// sameParent reports whether two parent IDs are equal (nil = top level).
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// formatParent renders a parent ID for messages ("root" for top level).
+func formatParent(parent *int) string {
+	if parent == nil {
+		return "root"
+	}
+	return fmt.Sprintf("#%d", *parent)
+}
@@ -0,0 +1,178 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestMove(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// #1 → #2 → #3, #4 (root)
+	one, two := 1, 2
+	Add(projectPath, "Epic", nil, "spec")
+	Add(projectPath, "Feature", &one, "spec")
+	Add(projectPath, "Step", &two, "spec")
+	Add(projectPath, "Other epic", nil, "spec")
+
+	four := 4
+	result := Move(projectPath, "2", &four, ActorCLI)
+	if !result.Success {
+		t.Fatalf("Move failed: %s", result.Message)
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	check := func(id, wantDepth int, wantStatus string, wantLeaf bool) {
+		t.Helper()
+		var depth int
+		var status string
+		var isLeaf bool
+		localDB.QueryRow("SELECT depth, status, is_leaf FROM tasks WHERE id = ?", id).Scan(&depth, &status, &isLeaf)
+		if depth != wantDepth || status != wantStatus || isLeaf != wantLeaf {
+			t.Errorf("#%d: expected depth=%d status=%s leaf=%v, got depth=%d status=%s leaf=%v",
+				id, wantDepth, wantStatus, wantLeaf, depth, status, isLeaf)
+		}
+	}
+	check(1, 0, "todo", true) // last child moved away
+	check(2, 1, "split", false)
+	check(3, 2, "todo", true)
+	check(4, 0, "split", false)
+
+	// Both parent changes are recorded with the caller's actor
+	lastEvent := func(id int) string {
+		t.Helper()
+		events, err := loadEvents(localDB, id)
+		if err != nil || len(events) == 0 {
+			t.Fatalf("No events for #%d (%v)", id, err)
+		}
+		e := events[len(events)-1]
+		return e.FromStatus + "→" + e.ToStatus + " " + e.Actor
+	}
+	if got := lastEvent(1); got != "split→todo cli" {
+		t.Errorf("Unexpected old parent event: %s", got)
+	}
+	if got := lastEvent(4); got != "todo→split cli" {
+		t.Errorf("Unexpected new parent event: %s", got)
+	}
+
+	tc, err := ReadTaskContent(projectPath, 2)
+	if err != nil || tc.Frontmatter.Parent == nil || *tc.Frontmatter.Parent != 4 {
+		t.Errorf("Expected parent 4 in frontmatter, got %+v (%v)", tc.Frontmatter.Parent, err)
+	}
+	if tc, _ := ReadTaskContent(projectPath, 1); tc.Frontmatter.Status != "todo" {
+		t.Errorf("Expected old parent file status todo, got %s", tc.Frontmatter.Status)
+	}
+
+	// Back to top level
+	if result := Move(projectPath, "2", nil, ActorCLI); !result.Success {
+		t.Fatalf("Move to root failed: %s", result.Message)
+	}
+	check(2, 0, "split", false)
+	check(3, 1, "todo", true)
+	check(4, 0, "todo", true)
+
+	// Sync derives the same tree from the files
+	if _, err := Sync(projectPath); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	check(3, 1, "todo", true)
+	check(4, 0, "todo", true)
+}
+
+func TestMoveKeepsParentStatus(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Done epic", nil, "spec")
+	Add(projectPath, "Done step", nil, "spec")
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+	localDB.Exec("UPDATE tasks SET status = 'done'")
+	WriteReportContent(projectPath, 1, "own report")
+
+	// A done leaf is not forced to split, so rollUp leaves it (and its report) alone
+	one := 1
+	if result := Move(projectPath, "2", &one, ActorCLI); !result.Success {
+		t.Fatalf("Move failed: %s", result.Message)
+	}
+	var status string
+	var isLeaf bool
+	localDB.QueryRow("SELECT status, is_leaf FROM tasks WHERE id = 1").Scan(&status, &isLeaf)
+	if status != "done" || isLeaf {
+		t.Errorf("Expected done parent, got status=%s leaf=%v", status, isLeaf)
+	}
+	if report, _ := ReadReportContent(projectPath, 1); report != "own report" {
+		t.Errorf("Expected report kept, got %q", report)
+	}
+}
+
+func TestMoveRejects(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Chain #1 → #2 → ... → #6 (depth 0..5), #7 → #8
+	Add(projectPath, "Task 1", nil, "spec")
+	for i := 1; i <= 5; i++ {
+		parent := i
+		Add(projectPath, "Task", &parent, "spec")
+	}
+	seven := 7
+	Add(projectPath, "Task 7", nil, "spec")
+	Add(projectPath, "Task 8", &seven, "spec")
+
+	tests := []struct {
+		name   string
+		id     string
+		parent int
+		want   string
+	}{
+		{"self", "3", 3, "순환"},
+		{"descendant", "2", 5, "순환"},
+		{"max depth", "7", 5, "최대 깊이"},
+		{"same parent", "2", 1, "이미"},
+		{"unknown parent", "2", 99, "찾을 수 없습니다"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := tt.parent
+			result := Move(projectPath, tt.id, &parent, ActorCLI)
+			if result.Success || !strings.Contains(result.Message, tt.want) {
+				t.Errorf("Expected %q error, got success=%v: %s", tt.want, result.Success, result.Message)
+			}
+		})
+	}
+
+	// #7 alone fits at depth 5 once #8 is moved out
+	if result := Move(projectPath, "8", nil, ActorCLI); !result.Success {
+		t.Fatalf("Move failed: %s", result.Message)
+	}
+	five := 5
+	if result := Move(projectPath, "7", &five, ActorCLI); !result.Success {
+		t.Errorf("Expected move to depth 5 to succeed: %s", result.Message)
+	}
+}
+
+func TestParseParent(t *testing.T) {
+	for _, s := range []string{"root", "none", "0", ""} {
+		if p, err := ParseParent(s); err != nil || p != nil {
+			t.Errorf("ParseParent(%q) = %v, %v; want nil", s, p, err)
+		}
+	}
+	if p, err := ParseParent("#12"); err != nil || p == nil || *p != 12 {
+		t.Errorf("ParseParent(#12) = %v, %v", p, err)
+	}
+	if _, err := ParseParent("abc"); err == nil {
+		t.Error("Expected error for abc")
+	}
+}
@@ -0,0 +1,189 @@
+package task
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+// RunOptions are the Claude execution settings of a task (model, tool restriction, idle timeout, agent).
+type RunOptions struct {
+	Model        string
+	AllowedTools []string
+	Timeout      time.Duration // idle timeout override (0 = claude config)
+	Agent        string        // agent backend (project-level only, "" = claude.DefaultAgent)
+}
+
+// apply sets the resolved options on a Claude invocation.
+func (o RunOptions) apply(opts *claude.Options) {
+	opts.Model = o.Model
+	opts.AllowedTools = o.AllowedTools
+	opts.Timeout = o.Timeout
+	opts.Agent = o.Agent
+}
+
+// ParseAllowedTools parses a comma or space separated tool list (e.g. "Read,Grep,Bash").
+// Empty input returns nil (no restriction).
+func ParseAllowedTools(s string) []string {
+	var tools []string
+	seen := make(map[string]bool)
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if !seen[f] {
+			seen[f] = true
+			tools = append(tools, f)
+		}
+	}
+	return tools
+}
+
+// ParseRunTimeout parses a task timeout duration (e.g. "15m"). Empty input returns 0 (use default).
+func ParseRunTimeout(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("기간 형식이 아닙니다: %s (예: 15m, 90s)", s)
+	}
+	return d, nil
+}
+
+// getDefaultRunOptions reads the project-level defaults (config keys model, allowed_tools, timeout, agent).
+func getDefaultRunOptions(localDB *db.DB) RunOptions {
+	var o RunOptions
+	rows, err := localDB.Query(`SELECT key, value FROM config WHERE key IN ('model', 'allowed_tools', 'timeout', 'agent')`)
+	if err != nil {
+		return o
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k, v string
+		if rows.Scan(&k, &v) != nil {
+			continue
+		}
+		switch k {
+		case "model":
+			o.Model = strings.TrimSpace(v)
+		case "allowed_tools":
+			o.AllowedTools = ParseAllowedTools(v)
+		case "timeout":
+			o.Timeout, _ = ParseRunTimeout(v)
+		case "agent":
+			o.Agent = strings.TrimSpace(v)
+		}
+	}
+	return o
+}
+
+// resolveRunOptions resolves the Claude options of a task. Each key is taken from the
+// task's frontmatter, then the nearest ancestor that sets it (split children keep their
+// parent's restrictions), then the project defaults.
+func resolveRunOptions(localDB *db.DB, projectPath string, taskID int) RunOptions {
+	var o RunOptions
+	var hasModel, hasTools, hasTimeout bool
+
+	visited := make(map[int]bool)
+	for id := taskID; id > 0 && !visited[id]; {
+		visited[id] = true
+		if tc, err := ReadTaskContent(projectPath, id); err == nil {
+			fm := tc.Frontmatter
+			if !hasModel && fm.Model != "" {
+				o.Model, hasModel = fm.Model, true
+			}
+			if !hasTools && len(fm.AllowedTools) > 0 {
+				o.AllowedTools, hasTools = fm.AllowedTools, true
+			}
+			if !hasTimeout && fm.Timeout != "" {
+				d, err := ParseRunTimeout(fm.Timeout)
+				if err != nil {
+					log.Printf("[Task] timeout 형식 오류 (#%d): %v", id, err)
+				} else {
+					o.Timeout, hasTimeout = d, true
+				}
+			}
+		}
+
+		var parentID sql.NullInt64
+		if err := localDB.QueryRow("SELECT parent_id FROM tasks WHERE id = ?", id).Scan(&parentID); err != nil || !parentID.Valid {
+			break
+		}
+		id = int(parentID.Int64)
+	}
+
+	defaults := getDefaultRunOptions(localDB)
+	if !hasModel {
+		o.Model = defaults.Model
+	}
+	if !hasTools {
+		o.AllowedTools = defaults.AllowedTools
+	}
+	if !hasTimeout {
+		o.Timeout = defaults.Timeout
+	}
+	o.Agent = defaults.Agent
+	return o
+}
+
+// ProjectAgent returns the agent backend configured for a project (config key agent).
+// Empty means claude.DefaultAgent.
+func ProjectAgent(projectPath string) string {
+	return strings.TrimSpace(projectConfig(projectPath, "agent"))
+}
+
+// ProjectQuota returns the max concurrent Claude runs of a project (config key quota, 0 = no limit).
+func ProjectQuota(projectPath string) int {
+	n, err := strconv.Atoi(projectConfig(projectPath, "quota"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// projectConfig reads a key of the project's local config ("" if unset).
+// A directory without a claribot DB has no settings (and none is created).
+func projectConfig(projectPath, key string) string {
+	if projectPath == "" {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".claribot", "db.clt")); err != nil {
+		return ""
+	}
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return ""
+	}
+	defer localDB.Close()
+
+	var val string
+	if err := localDB.QueryRow("SELECT value FROM config WHERE key = ?", key).Scan(&val); err != nil {
+		return ""
+	}
+	return val
+}
+
+// formatRunOptions renders the resolved options for task get (empty if all defaults).
+func formatRunOptions(o RunOptions) string {
+	var parts []string
+	if o.Model != "" {
+		parts = append(parts, "model="+o.Model)
+	}
+	if len(o.AllowedTools) > 0 {
+		parts = append(parts, "tools="+strings.Join(o.AllowedTools, ","))
+	}
+	if o.Timeout > 0 {
+		parts = append(parts, "timeout="+o.Timeout.String())
+	}
+	if o.Agent != "" {
+		parts = append(parts, "agent="+o.Agent)
+	}
+	return strings.Join(parts, " ")
+}
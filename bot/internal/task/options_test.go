@@ -0,0 +1,76 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestParseAllowedTools(t *testing.T) {
+	tools := ParseAllowedTools("Read, Grep,Glob Read")
+	if strings.Join(tools, ",") != "Read,Grep,Glob" {
+		t.Errorf("Unexpected tools: %v", tools)
+	}
+	if tools := ParseAllowedTools("  "); tools != nil {
+		t.Errorf("Expected nil for empty input, got %v", tools)
+	}
+}
+
+func TestResolveRunOptions(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Parent", nil, "spec")
+	parentID := 1
+	Add(projectPath, "Child", &parentID, "spec")
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	// Nothing set: Claude defaults
+	if o := resolveRunOptions(localDB, projectPath, 2); o.Model != "" || o.AllowedTools != nil || o.Timeout != 0 {
+		t.Errorf("Expected empty options, got %+v", o)
+	}
+
+	// Project defaults
+	for k, v := range map[string]string{"model": "sonnet", "allowed_tools": "Read,Bash", "timeout": "5m"} {
+		localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES (?, ?, ?)", k, v, db.TimeNow())
+	}
+	o := resolveRunOptions(localDB, projectPath, 2)
+	if o.Model != "sonnet" || strings.Join(o.AllowedTools, ",") != "Read,Bash" || o.Timeout != 5*time.Minute {
+		t.Errorf("Expected project defaults, got %+v", o)
+	}
+
+	// Parent frontmatter overrides the project default, per key
+	if r := Set(projectPath, "1", "allowed_tools", "Read,Grep", ActorCLI); !r.Success {
+		t.Fatalf("Set allowed_tools failed: %s", r.Message)
+	}
+	// Child frontmatter overrides the parent
+	if r := Set(projectPath, "2", "model", "haiku", ActorCLI); !r.Success {
+		t.Fatalf("Set model failed: %s", r.Message)
+	}
+	o = resolveRunOptions(localDB, projectPath, 2)
+	if o.Model != "haiku" || strings.Join(o.AllowedTools, ",") != "Read,Grep" || o.Timeout != 5*time.Minute {
+		t.Errorf("Expected child model + parent tools + project timeout, got %+v", o)
+	}
+
+	// Frontmatter round-trip
+	tc, err := ReadTaskContent(projectPath, 1)
+	if err != nil || strings.Join(tc.Frontmatter.AllowedTools, ",") != "Read,Grep" {
+		t.Errorf("Expected allowed_tools in frontmatter, got %+v (%v)", tc.Frontmatter, err)
+	}
+
+	if r := Set(projectPath, "2", "timeout", "soon", ActorCLI); r.Success {
+		t.Error("Expected invalid timeout to be rejected")
+	}
+
+	got := Get(projectPath, "2")
+	if !strings.Contains(got.Message, "Claude: model=haiku tools=Read,Grep timeout=5m0s") {
+		t.Errorf("Expected resolved options in task get: %s", got.Message)
+	}
+}
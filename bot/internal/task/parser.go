@@ -1,21 +1,227 @@
 package task
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"regexp"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
+// maxSplitChildren caps the children of one structured split.
+const maxSplitChildren = 10
+
+// maxPlanFormatAttempts is how many times a plan is asked for when its output
+// fails the schema (the output of each failure goes back into the next prompt).
+const maxPlanFormatAttempts = 3
+
 // PlanResult represents the result of 1회차 순회
 type PlanResult struct {
 	Type     string  `json:"type"`               // "split" or "planned"
 	Plan     string  `json:"plan,omitempty"`      // Plan content (if planned)
 	Children []Child `json:"children,omitempty"`  // Child tasks (if split)
+	Format   string  `json:"format,omitempty"`    // "json", "yaml" or "marker"
 }
 
-// Child represents a child task created during subdivision
+// Child represents a child task created during subdivision.
+// Marker output only carries ID and Title (Claude created the task itself);
+// a structured split carries the rest and the task is created from it.
 type Child struct {
-	ID    int    `json:"id"`
-	Title string `json:"title"`
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Spec      string `json:"spec,omitempty"`
+	Priority  int    `json:"priority,omitempty"`
+	After     []int  `json:"after,omitempty"`      // earlier siblings (1-based) to wait for
+	DependsOn []int  `json:"depends_on,omitempty"` // existing tasks to wait for
+}
+
+// PlanFormatError lists why a plan output was rejected.
+// The plan is asked again with these problems instead of guessing.
+type PlanFormatError struct {
+	Problems []string
+}
+
+func (e *PlanFormatError) Error() string {
+	return "계획 출력 형식 오류: " + strings.Join(e.Problems, "; ")
+}
+
+// planDocument is the schema of the structured plan block (JSON or YAML).
+type planDocument struct {
+	Type     string      `json:"type" yaml:"type"`
+	Plan     string      `json:"plan" yaml:"plan"`
+	Children []planChild `json:"children" yaml:"children"`
+}
+
+type planChild struct {
+	Title     string `json:"title" yaml:"title"`
+	Spec      string `json:"spec" yaml:"spec"`
+	Priority  int    `json:"priority" yaml:"priority"`
+	After     []int  `json:"after" yaml:"after"`
+	DependsOn []int  `json:"depends_on" yaml:"depends_on"`
+}
+
+// planBlockRe matches fenced ```json / ```yaml blocks starting at column 0
+// (fences indented inside a YAML plan or escaped inside a JSON string do not match).
+var planBlockRe = regexp.MustCompile("(?ms)^```(json|yaml|yml)[ \t]*\n(.*?)\n```[ \t]*$")
+
+// ParsePlan parses Claude output from 1회차 순회. A structured plan block
+// (```json or ```yaml, or the whole output) is decoded strictly and validated;
+// output starting with a [SPLIT]/[PLANNED] marker, or with a marker and no block,
+// is parsed the old way. Anything else is a *PlanFormatError.
+func ParsePlan(output string) (PlanResult, error) {
+	output = strings.TrimSpace(strings.ReplaceAll(output, "\r\n", "\n"))
+
+	stripped := stripCodeBlocks(output)
+	if strings.HasPrefix(stripped, "[SPLIT]") || strings.HasPrefix(stripped, "[PLANNED]") {
+		return markerResult(output), nil
+	}
+
+	type candidate struct{ format, body string }
+	var candidates []candidate
+	for _, m := range planBlockRe.FindAllStringSubmatch(output, -1) {
+		format := m[1]
+		if format == "yml" {
+			format = "yaml"
+		}
+		candidates = append(candidates, candidate{format, m[2]})
+	}
+	switch {
+	case strings.HasPrefix(output, "{"):
+		candidates = append(candidates, candidate{"json", output})
+	case strings.HasPrefix(output, "type:"):
+		candidates = append(candidates, candidate{"yaml", output})
+	}
+
+	var syntaxErr error
+	for _, c := range candidates {
+		var loose map[string]any
+		var err error
+		if c.format == "json" {
+			err = json.Unmarshal([]byte(c.body), &loose)
+		} else {
+			err = yaml.Unmarshal([]byte(c.body), &loose)
+		}
+		if err != nil {
+			if syntaxErr == nil {
+				syntaxErr = fmt.Errorf("%s 파싱 실패: %v", strings.ToUpper(c.format), err)
+			}
+			continue
+		}
+		if _, ok := loose["type"]; !ok {
+			continue // some other block (e.g. an example in a preamble)
+		}
+		return parsePlanDocument(c.format, c.body)
+	}
+
+	if _, _, found := extractMarker(stripped); found {
+		return markerResult(output), nil
+	}
+	if syntaxErr != nil {
+		return PlanResult{}, &PlanFormatError{Problems: []string{syntaxErr.Error()}}
+	}
+	return PlanResult{}, &PlanFormatError{Problems: []string{"계획 블록(```json 또는 ```yaml)이 없습니다"}}
+}
+
+// markerResult parses marker output and tags it as such.
+func markerResult(output string) PlanResult {
+	result := ParsePlanOutput(output)
+	result.Format = "marker"
+	return result
+}
+
+// parsePlanDocument decodes a plan block strictly (unknown fields are errors) and validates it.
+func parsePlanDocument(format, body string) (PlanResult, error) {
+	var doc planDocument
+	var err error
+	if format == "json" {
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&doc)
+		if err == nil && dec.More() {
+			err = errors.New("블록 뒤에 추가 데이터가 있습니다")
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader([]byte(body)))
+		dec.KnownFields(true)
+		err = dec.Decode(&doc)
+		if err == io.EOF {
+			err = errors.New("빈 문서")
+		}
+	}
+	if err != nil {
+		return PlanResult{}, &PlanFormatError{Problems: []string{fmt.Sprintf("%s 스키마 불일치: %v", strings.ToUpper(format), err)}}
+	}
+
+	if problems := validatePlanDocument(doc); len(problems) > 0 {
+		return PlanResult{}, &PlanFormatError{Problems: problems}
+	}
+
+	result := PlanResult{Type: doc.Type, Plan: strings.TrimSpace(doc.Plan), Format: format}
+	for _, c := range doc.Children {
+		result.Children = append(result.Children, Child{
+			Title:     strings.TrimSpace(c.Title),
+			Spec:      strings.TrimSpace(c.Spec),
+			Priority:  c.Priority,
+			After:     c.After,
+			DependsOn: c.DependsOn,
+		})
+	}
+	return result, nil
+}
+
+// validatePlanDocument checks a decoded plan block against the schema rules.
+func validatePlanDocument(doc planDocument) []string {
+	var problems []string
+	switch doc.Type {
+	case "planned":
+		if strings.TrimSpace(doc.Plan) == "" {
+			problems = append(problems, "plan: planned에는 계획 내용이 필요합니다")
+		}
+		if len(doc.Children) > 0 {
+			problems = append(problems, "children: planned에는 하위 작업을 넣을 수 없습니다")
+		}
+	case "split":
+		if strings.TrimSpace(doc.Plan) != "" {
+			problems = append(problems, "plan: split에는 계획 내용을 넣을 수 없습니다")
+		}
+		if len(doc.Children) < 2 {
+			problems = append(problems, fmt.Sprintf("children: 하위 작업이 %d개입니다 (2개 이상, 1개면 planned로 계획)", len(doc.Children)))
+		}
+		if len(doc.Children) > maxSplitChildren {
+			problems = append(problems, fmt.Sprintf("children: 하위 작업이 %d개입니다 (최대 %d개, 초과 시 계층 추가)", len(doc.Children), maxSplitChildren))
+		}
+		for i, c := range doc.Children {
+			n := i + 1
+			title := strings.TrimSpace(c.Title)
+			if title == "" {
+				problems = append(problems, fmt.Sprintf("children #%d title: 비어 있습니다", n))
+			} else if strings.Contains(title, "\n") {
+				problems = append(problems, fmt.Sprintf("children #%d title: 한 줄이어야 합니다", n))
+			}
+			if strings.TrimSpace(c.Spec) == "" {
+				problems = append(problems, fmt.Sprintf("children #%d spec: 비어 있습니다", n))
+			}
+			for _, a := range c.After {
+				if a < 1 || a >= n {
+					problems = append(problems, fmt.Sprintf("children #%d after: %d은(는) 앞선 하위 작업 번호가 아닙니다 (1~%d)", n, a, n-1))
+				}
+			}
+			for _, d := range c.DependsOn {
+				if d < 1 {
+					problems = append(problems, fmt.Sprintf("children #%d depends_on: 잘못된 작업 ID %d", n, d))
+				}
+			}
+		}
+	case "":
+		problems = append(problems, "type: 필수 항목입니다 (split 또는 planned)")
+	default:
+		problems = append(problems, fmt.Sprintf("type: %q은(는) 지원하지 않습니다 (split 또는 planned)", doc.Type))
+	}
+	return problems
 }
 
 // stripCodeBlocks removes wrapping code block markers (```) from output.
@@ -41,7 +247,8 @@ func extractMarker(output string) (marker string, content string, found bool) {
 	return "", "", false
 }
 
-// ParsePlanOutput parses Claude output from 1회차 순회
+// ParsePlanOutput parses the [SPLIT]/[PLANNED] marker format of 1회차 순회
+// (fallback of ParsePlan; output without a marker is taken as the plan)
 func ParsePlanOutput(output string) PlanResult {
 	output = strings.TrimSpace(output)
 
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -193,3 +194,120 @@ func TestExtractMarker(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlan_JSONPlanned(t *testing.T) {
+	output := "계획을 작성했습니다.\n\n```json\n{\"type\": \"planned\", \"plan\": \"## 구현 방향\\n수정\"}\n```\n"
+	result, err := ParsePlan(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Type != "planned" || result.Format != "json" || result.Plan != "## 구현 방향\n수정" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestParsePlan_YAMLSplit(t *testing.T) {
+	output := "```yaml\ntype: split\nchildren:\n  - title: API 추가\n    spec: |\n      ## 목표\n      API\n    priority: 2\n  - title: UI 추가\n    spec: UI 연결\n    after: [1]\n    depends_on: [7]\n```"
+	result, err := ParsePlan(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Type != "split" || result.Format != "yaml" || len(result.Children) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	first, second := result.Children[0], result.Children[1]
+	if first.Title != "API 추가" || first.Spec != "## 목표\nAPI" || first.Priority != 2 {
+		t.Errorf("unexpected first child: %+v", first)
+	}
+	if len(second.After) != 1 || second.After[0] != 1 || len(second.DependsOn) != 1 || second.DependsOn[0] != 7 {
+		t.Errorf("unexpected second child: %+v", second)
+	}
+}
+
+func TestParsePlan_WholeOutputJSON(t *testing.T) {
+	result, err := ParsePlan(`{"type": "planned", "plan": "계획"}`)
+	if err != nil || result.Type != "planned" || result.Plan != "계획" {
+		t.Errorf("unexpected result: %+v, %v", result, err)
+	}
+}
+
+func TestParsePlan_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			name:   "unknown field",
+			output: "```json\n{\"type\": \"planned\", \"plan\": \"p\", \"note\": \"x\"}\n```",
+			want:   []string{"note"},
+		},
+		{
+			name:   "missing type",
+			output: "```yaml\ntype: ''\nplan: p\n```",
+			want:   []string{"type: 필수"},
+		},
+		{
+			name:   "planned without plan",
+			output: "```json\n{\"type\": \"planned\"}\n```",
+			want:   []string{"plan:"},
+		},
+		{
+			name:   "split problems",
+			output: "```json\n{\"type\": \"split\", \"children\": [{\"title\": \"A\", \"spec\": \"\"}, {\"title\": \"B\", \"spec\": \"s\", \"after\": [2]}]}\n```",
+			want:   []string{"children #1 spec", "children #2 after: 2"},
+		},
+		{
+			name:   "single child",
+			output: "```json\n{\"type\": \"split\", \"children\": [{\"title\": \"A\", \"spec\": \"s\"}]}\n```",
+			want:   []string{"2개 이상"},
+		},
+		{
+			name:   "wrong type for priority",
+			output: "```json\n{\"type\": \"split\", \"children\": [{\"title\": \"A\", \"spec\": \"s\", \"priority\": \"high\"}]}\n```",
+			want:   []string{"priority"},
+		},
+		{
+			name:   "syntax error",
+			output: "```json\n{\"type\": \"planned\",\n```",
+			want:   []string{"JSON 파싱 실패"},
+		},
+		{
+			name:   "no block and no marker",
+			output: "이건 그냥 자유 형식 출력입니다.",
+			want:   []string{"계획 블록"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePlan(tt.output)
+			formatErr, ok := err.(*PlanFormatError)
+			if !ok {
+				t.Fatalf("expected *PlanFormatError, got %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(formatErr.Error(), w) {
+					t.Errorf("expected %q in %q", w, formatErr.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestParsePlan_MarkerFallback(t *testing.T) {
+	// Marker output still works, including a plan that embeds a JSON example
+	output := "[PLANNED]\n## 구현 방향\n설정 추가\n\n```json\n{\"type\": \"oauth\"}\n```"
+	result, err := ParsePlan(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Type != "planned" || result.Format != "marker" || !strings.Contains(result.Plan, "oauth") {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	result, err = ParsePlan("분석 결과입니다.\r\n\r\n[SPLIT]\r\n- Task #20: 작업 A\r\n- Task #21: 작업 B\r\n")
+	if err != nil || result.Type != "split" || result.Format != "marker" || len(result.Children) != 2 {
+		t.Errorf("unexpected result: %+v, %v", result, err)
+	}
+}
@@ -16,8 +16,8 @@ import (
 )
 
 // Plan generates plan for a task (1회차 순회: todo → planned/split)
-// If id is empty, plans next todo task
-func Plan(projectPath, id string) types.Result {
+// If id is empty, plans next todo task. actor is recorded on the status changes.
+func Plan(projectPath, id, actor string) types.Result {
 	localDB, err := db.OpenLocal(projectPath)
 	if err != nil {
 		return types.Result{
@@ -84,7 +84,7 @@ func Plan(projectPath, id string) types.Result {
 		log.Printf("[Task] traversal INSERT 실패: %v", travErr)
 	}
 
-	result := planRecursive(context.Background(), localDB, projectPath, &t)
+	result := planRecursive(withTraversal(WithActor(context.Background(), actor), travID), localDB, projectPath, &t)
 
 	// Update traversal record
 	if travErr == nil {
@@ -104,6 +104,12 @@ func Plan(projectPath, id string) types.Result {
 
 // planRecursive executes plan for a task and its children recursively
 func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *Task) types.Result {
+	return planTask(ctx, localDB, projectPath, t, nil)
+}
+
+// planTask plans a task and its children recursively. With replan set, the prompt
+// carries the failed plan and its error, and the new plan (or split) replaces the old one.
+func planTask(ctx context.Context, localDB *db.DB, projectPath string, t *Task, replan *replanInfo) (ret types.Result) {
 	UpdateCurrentTask(projectPath, t.ID)
 
 	// Check spec - skip if empty (not an error, just skip)
@@ -117,15 +123,27 @@ func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *T
 		return ret
 	}
 
+	// Own context per task: CancelTask stops this task (and its children) only
+	ctx, done := withTaskCancel(ctx, projectPath, t.ID)
+	defer done()
+	item := startItem(ctx, t, "plan")
+	defer func() { item.finish(localDB, ret) }()
+
 	// Check depth limit - force plan if at max depth
 	forceLeaf := t.Depth >= MaxDepth
 
-	// Build context map (lightweight task tree summary)
-	contextMap, err := BuildContextMap(localDB)
+	// Graph nodes are atomic: never split
+	isNode := lookupGraphNode(localDB, t.ID) != ""
+	if isNode {
+		forceLeaf = true
+	}
+
+	// Build context (graph node: direct upstream results, otherwise task tree summary)
+	contextMap, upstream, err := buildPromptContext(localDB, projectPath, t.ID)
 	if err != nil {
 		return types.Result{
 			Success: false,
-			Message: fmt.Sprintf("Context Map 생성 실패: %v", err),
+			Message: fmt.Sprintf("컨텍스트 생성 실패: %v", err),
 		}
 	}
 
@@ -140,11 +158,16 @@ func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *T
 	}
 
 	// Build prompt with report path
-	prompt := BuildPlanPrompt(t, contextMap, reportPath)
+	prompt := BuildPlanPromptWithUpstream(t, contextMap, upstream, reportPath)
+	if replan != nil {
+		prompt = BuildReplanPrompt(t, contextMap, upstream, replan, reportPath)
+	}
 
-	// Add force leaf instruction if at max depth
-	if forceLeaf {
-		prompt += "\n\n⚠️ 최대 깊이에 도달했습니다. 반드시 [PLANNED] 형식으로 계획을 작성하세요. 분할은 불가능합니다."
+	// Add force leaf instruction if at max depth or graph node
+	if isNode {
+		prompt += "\n\n⚠️ Node Graph 노드입니다. 반드시 \"type\": \"planned\"로 계획을 작성하세요. 분할은 불가능합니다."
+	} else if forceLeaf {
+		prompt += "\n\n⚠️ 최대 깊이에 도달했습니다. 반드시 \"type\": \"planned\"로 계획을 작성하세요. 분할은 불가능합니다."
 	}
 
 	// Run Claude Code
@@ -152,52 +175,117 @@ func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *T
 		UserPrompt: prompt,
 		WorkDir:    projectPath,
 		ReportPath: reportPath,
+		Origin:     claude.Origin{Project: projectPath, TaskID: t.ID},
 	}
+	resolveRunOptions(localDB, projectPath, t.ID).apply(&opts)
 
-	result, err := claude.RunContext(ctx, opts)
-	if err != nil {
-		ret := types.Result{
-			Success: false,
-			Message: fmt.Sprintf("Claude 실행 오류: %v", err),
+	// Claude is asked again (with the problems found) while the output fails the plan schema
+	var planResult PlanResult
+	for formatAttempt := 1; ; formatAttempt++ {
+		// Record the attempt (each plan call keeps its own outcome and output)
+		opts.RunID = claude.NewRunID()
+		attemptID, _, attemptErr := startAttempt(localDB, t.ID, "plan", opts.RunID)
+		if attemptErr != nil {
+			log.Printf("[Task] attempt INSERT 실패 (#%d): %v", t.ID, attemptErr)
 		}
-		if ctx.Err() != nil {
-			ret.ErrorType = "cancelled"
+
+		result, err := claude.RunContext(ctx, opts)
+		if err != nil {
+			ret := types.Result{
+				Success:   false,
+				Message:   fmt.Sprintf("Claude 실행 오류: %v", err),
+				ErrorType: classifyRunError(ctx, err),
+			}
+			if attemptErr == nil {
+				status := "failed"
+				if ret.ErrorType == "cancelled" {
+					status = "cancelled"
+				}
+				finishAttempt(localDB, projectPath, attemptID, status, nil, ret.ErrorType, err.Error(), "")
+			}
+			return ret
 		}
-		return ret
-	}
 
-	if result.ExitCode != 0 {
-		// Check for authentication error
-		authError := claude.IsAuthError(result)
-		if authError {
-			log.Printf("[Task] Plan 인증 오류 감지 (task #%d)", t.ID)
+		item.setExitCode(result.ExitCode)
+		if result.ExitCode != 0 {
+			// Check for authentication error
+			authError := claude.IsAuthError(result)
+			if authError {
+				log.Printf("[Task] Plan 인증 오류 감지 (task #%d)", t.ID)
+			}
+			errorType := "exit_error"
+			if authError {
+				errorType = "auth_error"
+			}
+			if attemptErr == nil {
+				exitCode := result.ExitCode
+				finishAttempt(localDB, projectPath, attemptID, "failed", &exitCode, errorType, truncateLine(result.Output, 200), result.Output)
+			}
+
+			// Save error to file
+			now := db.TimeNow()
+			if err := WriteErrorContent(projectPath, t.ID, result.Output); err != nil {
+				log.Printf("[Task] Plan 에러 파일 생성 실패 (task #%d): %v", t.ID, err)
+			}
+			if _, err := localDB.Exec(`UPDATE tasks SET updated_at = ? WHERE id = ?`, now, t.ID); err != nil {
+				log.Printf("[Task] Plan updated_at 갱신 실패 (task #%d): %v", t.ID, err)
+			}
+			gitCommitTask(ctx, projectPath, t.ID, "plan error")
+
+			return types.Result{
+				Success:   false,
+				Message:   fmt.Sprintf("Plan 생성 실패: %s", result.Output),
+				ErrorType: errorType,
+			}
 		}
 
-		// Save error to file
-		now := db.TimeNow()
-		if err := WriteErrorContent(projectPath, t.ID, result.Output); err != nil {
-			log.Printf("[Task] Plan 에러 파일 생성 실패 (task #%d): %v", t.ID, err)
+		// Parse output: structured plan block, [SPLIT]/[PLANNED] markers as fallback
+		var parseErr error
+		planResult, parseErr = ParsePlan(result.Output)
+		if parseErr == nil && forceLeaf && planResult.Type == "split" && planResult.Format != "marker" {
+			parseErr = &PlanFormatError{Problems: []string{"type: 분할할 수 없는 작업입니다 (planned만 허용)"}}
 		}
-		if _, err := localDB.Exec(`UPDATE tasks SET updated_at = ? WHERE id = ?`, now, t.ID); err != nil {
-			log.Printf("[Task] Plan updated_at 갱신 실패 (task #%d): %v", t.ID, err)
+		exitCode := 0
+		if parseErr == nil {
+			if attemptErr == nil {
+				finishAttempt(localDB, projectPath, attemptID, "done", &exitCode, "", "", result.Output)
+			}
+			break
 		}
-		gitCommitTask(projectPath, t.ID, "plan error")
 
-		ret := types.Result{
-			Success: false,
-			Message: fmt.Sprintf("Plan 생성 실패: %s", result.Output),
+		if attemptErr == nil {
+			finishAttempt(localDB, projectPath, attemptID, "failed", &exitCode, "invalid_output", truncateLine(parseErr.Error(), 200), result.Output)
 		}
-		if authError {
-			ret.ErrorType = "auth_error"
+		if formatAttempt >= maxPlanFormatAttempts {
+			if err := WriteErrorContent(projectPath, t.ID, parseErr.Error()+"\n\n"+result.Output); err != nil {
+				log.Printf("[Task] Plan 에러 파일 생성 실패 (task #%d): %v", t.ID, err)
+			}
+			gitCommitTask(ctx, projectPath, t.ID, "plan error")
+			return types.Result{
+				Success:   false,
+				Message:   fmt.Sprintf("Plan 출력 검증 실패 (%d회): %v", formatAttempt, parseErr),
+				ErrorType: "invalid_output",
+			}
 		}
-		return ret
+		log.Printf("[Task] Plan 출력 검증 실패, 재요청 (#%d, %d/%d): %v", t.ID, formatAttempt, maxPlanFormatAttempts, parseErr)
+		opts.UserPrompt = prompt + formatRetrySection(parseErr, formatAttempt)
 	}
 
-	// Parse output
-	planResult := ParsePlanOutput(result.Output)
 	now := db.TimeNow()
 
 	if planResult.Type == "split" && !forceLeaf {
+		// Structured split: the children come with the output (marker split: Claude added them)
+		if planResult.Format != "marker" {
+			ids, err := addChildren(ctx, localDB, projectPath, t, planResult.Children)
+			if err != nil {
+				log.Printf("[Task] 하위 작업 생성 실패 (#%d, 생성됨: %v): %v", t.ID, ids, err)
+				return types.Result{
+					Success: false,
+					Message: fmt.Sprintf("분할 실패: 작업 #%d (%s)의 하위 작업 생성 실패: %v", t.ID, t.Title, err),
+				}
+			}
+		}
+
 		// Mark as split, not a leaf
 		_, err = localDB.Exec(`
 			UPDATE tasks SET status = 'split', is_leaf = 0, updated_at = ? WHERE id = ?
@@ -208,12 +296,24 @@ func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *T
 				Message: fmt.Sprintf("상태 업데이트 실패: %v", err),
 			}
 		}
+		recordEvent(localDB, t.ID, t.Status, "split", actorFromContext(ctx), traversalFromContext(ctx))
 
 		// Dual-write: update task file status
 		if err := updateTaskFileStatus(projectPath, t.ID, "split"); err != nil {
 			log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", t.ID, err)
 		}
-		gitCommitTask(projectPath, t.ID, "split")
+		clearReview(projectPath, t.ID)
+		if replan != nil {
+			// The failed plan is replaced by the children
+			if err := os.Remove(PlanFilePath(projectPath, t.ID)); err != nil && !os.IsNotExist(err) {
+				log.Printf("[Task] plan 파일 삭제 실패 (#%d): %v", t.ID, err)
+			}
+			indexTaskContent(projectPath, t.ID, searchPlan, "")
+		}
+		gitCommitTask(ctx, projectPath, t.ID, "split")
+		// The split itself is this task's item; children are logged as their own
+		item.setOutcome("split")
+		item.finish(localDB, types.Result{Success: true})
 
 		// Clean up report file after DB save
 		if err := os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
@@ -262,6 +362,8 @@ func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *T
 			`, db.TimeNow(), t.ID)
 			if err != nil {
 				log.Printf("[Task] Failed to revert split status for task #%d: %v", t.ID, err)
+			} else {
+				recordEvent(localDB, t.ID, "split", "todo", actorFromContext(ctx), traversalFromContext(ctx))
 			}
 			return types.Result{
 				Success: false,
@@ -274,7 +376,7 @@ func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *T
 		var authErrorDetected bool
 		for _, child := range children {
 			// Check cancel flag before each child
-			if IsCancelled() || ctx.Err() != nil {
+			if ctx.Err() != nil {
 				childResults = append(childResults, fmt.Sprintf("🛑 중단 요청으로 나머지 하위 작업 건너뜀"))
 				break
 			}
@@ -313,30 +415,45 @@ func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *T
 		return ret
 	}
 
-	// Planned (leaf)
+	// Planned (leaf): with approval on, the plan waits for review before it can run
+	status := "planned"
+	if getApprovalMode(localDB) {
+		status = "review"
+	}
+
 	// Write plan to file (sole source of truth)
 	if err := WritePlanContent(projectPath, t.ID, planResult.Plan); err != nil {
 		log.Printf("[Task] plan 파일 생성 실패 (#%d): %v", t.ID, err)
 	}
 	_, err = localDB.Exec(`
-		UPDATE tasks SET status = 'planned', is_leaf = 1, updated_at = ? WHERE id = ?
-	`, now, t.ID)
+		UPDATE tasks SET status = ?, is_leaf = 1, updated_at = ? WHERE id = ?
+	`, status, now, t.ID)
 	if err != nil {
 		return types.Result{
 			Success: false,
 			Message: fmt.Sprintf("Plan 상태 저장 실패: %v", err),
 		}
 	}
-	if err := updateTaskFileStatus(projectPath, t.ID, "planned"); err != nil {
+	recordEvent(localDB, t.ID, t.Status, status, actorFromContext(ctx), traversalFromContext(ctx))
+	if err := updateTaskFileStatus(projectPath, t.ID, status); err != nil {
 		log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", t.ID, err)
 	}
-	gitCommitTask(projectPath, t.ID, "planned")
+	clearReview(projectPath, t.ID)
+	gitCommitTask(ctx, projectPath, t.ID, status)
+	item.setOutcome(status)
 
 	// Clean up report file after DB save
 	if err := os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
 		log.Printf("[Task] Plan report 파일 삭제 실패 (task #%d): %v", t.ID, err)
 	}
 
+	if status == "review" {
+		return types.Result{
+			Success: true,
+			Message: fmt.Sprintf("🔍 작업 #%d Plan 생성 완료 (승인 대기): %s\n[조회:task get %d]%s", t.ID, t.Title, t.ID, reviewButtons(t.ID)),
+			Data:    t,
+		}
+	}
 	return types.Result{
 		Success: true,
 		Message: fmt.Sprintf("📋 작업 #%d Plan 생성 완료: %s\n[조회:task get %d][실행:task run %d]", t.ID, t.Title, t.ID, t.ID),
@@ -346,6 +463,21 @@ func planRecursive(ctx context.Context, localDB *db.DB, projectPath string, t *T
 
 // PlanAll generates plans for all todo tasks (1회차 순회 전체 실행)
 func PlanAll(projectPath string) types.Result {
+	return planAll(projectPath, 0, "")
+}
+
+// PlanSubtree generates plans for the todo tasks in the subtree of a root task.
+func PlanSubtree(projectPath, id string) types.Result {
+	rootID, errResult := resolveScope(projectPath, id)
+	if errResult != nil {
+		return *errResult
+	}
+	return planAll(projectPath, rootID, "")
+}
+
+// planAll runs the plan traversal over the whole project (rootID 0) or a subtree.
+// trigger is recorded on the traversal ("resume:<id>" when restarted after an interruption).
+func planAll(projectPath string, rootID int, trigger string) types.Result {
 	// Check if already running for this project
 	if IsCycleRunning(projectPath) {
 		return types.Result{
@@ -354,10 +486,20 @@ func PlanAll(projectPath string) types.Result {
 		}
 	}
 
-	ResetCancel()
-	SetBatchMode(true)
-	defer SetBatchMode(false)
-	ctx, cancel := context.WithCancel(context.Background())
+	// Batch mode: per-task commits are replaced by one commit at the end
+	ctx, cancel := context.WithCancel(withBatch(context.Background()))
+
+	// Insert traversal record
+	localDB, travErr := db.OpenLocal(projectPath)
+	var travID int64
+	if travErr == nil {
+		travID, travErr = insertTraversal(localDB, "plan", scopeTarget(rootID), trigger)
+		if travErr != nil {
+			log.Printf("[Task] traversal INSERT 실패: %v", travErr)
+		}
+		localDB.Close()
+	}
+	ctx = withTraversal(ctx, travID)
 
 	startTime := time.Now()
 	SetCycleState(projectPath, CycleState{
@@ -365,7 +507,9 @@ func PlanAll(projectPath string) types.Result {
 		Type:        "plan",
 		StartedAt:   startTime,
 		ProjectPath: projectPath,
+		RootID:      rootID,
 		Phase:       "plan",
+		TraversalID: travID,
 	})
 	SetCycleCancel(projectPath, cancel)
 	defer func() {
@@ -373,19 +517,8 @@ func PlanAll(projectPath string) types.Result {
 		ClearCycleState(projectPath)
 	}()
 
-	// Insert traversal record
-	localDB, travErr := db.OpenLocal(projectPath)
-	var travID int64
-	if travErr == nil {
-		travID, travErr = insertTraversal(localDB, "plan", nil, "")
-		if travErr != nil {
-			log.Printf("[Task] traversal INSERT 실패: %v", travErr)
-		}
-		localDB.Close()
-	}
-
-	result := planAllInternal(ctx, projectPath)
-	gitCommitBatch(projectPath, fmt.Sprintf("planAll: %s", summarizeResult(result.Message)))
+	result := planAllInternal(ctx, projectPath, rootID)
+	gitCommitBatch(projectPath, fmt.Sprintf("planAll%s: %s", scopeCommitSuffix(rootID), summarizeResult(result.Message)))
 
 	// Update traversal record
 	if travErr == nil {
@@ -395,17 +528,25 @@ func PlanAll(projectPath string) types.Result {
 			if !result.Success {
 				status = "failed"
 			}
-			if IsCancelled() {
+			if ctx.Err() != nil {
 				status = "cancelled"
 			}
-			total, success, failed := countFromMessage(result.Message)
+			total, success, failed := countItems(localDB, travID)
 			finishTraversal(localDB, travID, status, total, success, failed)
 			localDB.Close()
 		}
 	}
 
+	// Plans waiting for approval (approval mode)
+	if localDB, err := db.OpenLocal(projectPath); err == nil {
+		if _, pending := formatPendingReviews(localDB, rootID); pending != "" {
+			result.Message += "\n\n" + pending
+		}
+		localDB.Close()
+	}
+
 	if globalNotifier != nil {
-		notification := fmt.Sprintf("📋 Plan 순회 완료\n소요: %s\n%s", formatDuration(time.Since(startTime)), result.Message)
+		notification := fmt.Sprintf("📋 %sPlan 순회 완료\n소요: %s\n%s", scopeLabel(rootID), formatDuration(time.Since(startTime)), result.Message)
 		globalNotifier(nil, notification)
 	}
 
@@ -414,17 +555,19 @@ func PlanAll(projectPath string) types.Result {
 
 // planResult holds the result of a single task plan for channel communication
 type planResult struct {
-	TaskID  int
-	Title   string
-	Success bool
-	Message string
-	IsAuth  bool
+	TaskID    int
+	Title     string
+	Success   bool
+	Message   string
+	IsAuth    bool
+	ErrorType string
 }
 
 // planAllInternal is the internal implementation of PlanAll without CycleState management.
 // Used by Cycle() to avoid overwriting the cycle type.
 // Supports parallel execution based on project's parallel config.
-func planAllInternal(ctx context.Context, projectPath string) types.Result {
+// rootID limits the traversal to a subtree (0 = whole project).
+func planAllInternal(ctx context.Context, projectPath string, rootID int) types.Result {
 	localDB, err := db.OpenLocal(projectPath)
 	if err != nil {
 		return types.Result{
@@ -437,13 +580,14 @@ func planAllInternal(ctx context.Context, projectPath string) types.Result {
 	parallel := getParallel(localDB)
 
 	// Get all root todo tasks (no parent or parent is split), priority first
+	scope, scopeArgs := scopeFilter(rootID)
 	rows, err := localDB.Query(`
 		SELECT id, parent_id, title, status, is_leaf, depth FROM tasks
 		WHERE status = 'todo' AND (parent_id IS NULL OR parent_id IN (
 			SELECT id FROM tasks WHERE status = 'split'
-		))
+		))`+scope+`
 		ORDER BY priority DESC, depth ASC, id ASC
-	`)
+	`, scopeArgs...)
 	if err != nil {
 		localDB.Close()
 		return types.Result{
@@ -511,7 +655,7 @@ func planAllSequential(ctx context.Context, projectPath string, tasks []Task) ty
 	var messages []string
 
 	for _, t := range tasks {
-		if IsCancelled() || ctx.Err() != nil {
+		if ctx.Err() != nil {
 			remaining := len(tasks) - success - failed - skipped
 			messages = append(messages, fmt.Sprintf("🛑 중단 요청으로 %d개 작업 건너뜀", remaining))
 			break
@@ -529,6 +673,10 @@ func planAllSequential(ctx context.Context, projectPath string, tasks []Task) ty
 		if result.ErrorType == "skipped" {
 			skipped++
 			messages = append(messages, result.Message)
+		} else if result.ErrorType == "cancelled" && ctx.Err() == nil {
+			// Only this task was cancelled (task cancel): it stays todo, the traversal goes on
+			skipped++
+			messages = append(messages, cancelledMessage(t))
 		} else if result.Success {
 			success++
 			messages = append(messages, result.Message)
@@ -540,7 +688,7 @@ func planAllSequential(ctx context.Context, projectPath string, tasks []Task) ty
 				messages = append(messages, fmt.Sprintf("🔐 인증 오류로 순회 중단, %d개 작업 건너뜀", remaining))
 				break
 			}
-			if result.ErrorType == "cancelled" {
+			if result.ErrorType == "cancelled" && ctx.Err() != nil {
 				remaining := len(tasks) - success - failed - skipped
 				messages = append(messages, fmt.Sprintf("🛑 중단 요청으로 %d개 작업 건너뜀", remaining))
 				break
@@ -574,9 +722,6 @@ func planAllParallel(parentCtx context.Context, projectPath string, tasks []Task
 
 	for _, t := range tasks {
 		// Check cancel/stop before dispatching
-		if IsCancelled() {
-			break
-		}
 		select {
 		case <-ctx.Done():
 			break
@@ -601,7 +746,7 @@ func planAllParallel(parentCtx context.Context, projectPath string, tasks []Task
 			defer func() { <-sem }()
 
 			// Re-check after acquiring semaphore
-			if IsCancelled() || ctx.Err() != nil {
+			if ctx.Err() != nil {
 				resultCh <- planResult{TaskID: t.ID, Title: t.Title, Success: false, Message: "중단됨"}
 				return
 			}
@@ -631,11 +776,12 @@ func planAllParallel(parentCtx context.Context, projectPath string, tasks []Task
 
 			result := planRecursive(ctx, workerDB, projectPath, &t)
 			pr := planResult{
-				TaskID:  t.ID,
-				Title:   t.Title,
-				Success: result.Success,
-				Message: result.Message,
-				IsAuth:  result.ErrorType == "auth_error",
+				TaskID:    t.ID,
+				Title:     t.Title,
+				Success:   result.Success,
+				Message:   result.Message,
+				IsAuth:    result.ErrorType == "auth_error",
+				ErrorType: result.ErrorType,
 			}
 
 			// Auth error: cancel all other workers
@@ -668,7 +814,10 @@ func planAllParallel(parentCtx context.Context, projectPath string, tasks []Task
 			continue
 		}
 		IncrementCompleted(projectPath)
-		if pr.Success {
+		if pr.ErrorType == "cancelled" && ctx.Err() == nil {
+			// Only this task was cancelled (task cancel): it stays todo
+			messages = append(messages, cancelledMessage(Task{ID: pr.TaskID, Title: pr.Title}))
+		} else if pr.Success {
 			success++
 			messages = append(messages, pr.Message)
 		} else {
@@ -682,7 +831,7 @@ func planAllParallel(parentCtx context.Context, projectPath string, tasks []Task
 
 	if authDetected {
 		messages = append(messages, fmt.Sprintf("🔐 인증 오류로 순회 중단, %d개 작업 건너뜀", skipped))
-	} else if IsCancelled() && skipped > 0 {
+	} else if ctx.Err() != nil && skipped > 0 {
 		messages = append(messages, fmt.Sprintf("🛑 중단 요청으로 %d개 작업 건너뜀", skipped))
 	}
 
@@ -2,6 +2,7 @@ package task
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 	"text/template"
@@ -18,21 +19,43 @@ type PlanPromptData struct {
 	Depth      int
 	MaxDepth   int
 	ContextMap string
+	Upstream   string
 	ReportPath string
+	// ReviewComment is the reviewer's reason for rejecting PreviousPlan (empty otherwise)
+	ReviewComment string
+	PreviousPlan  string
+	// Replan fields: the plan whose run failed, its error output and history (re-plan only)
+	FailedPlan    string
+	FailureReport string
+	RunAttempts   int
+	Replan        int
+	ReplanMax     int
 }
 
 // BuildPlanPrompt builds prompt for Plan generation (1회차 순회)
 func BuildPlanPrompt(t *Task, contextMap string, reportPath string) string {
+	return BuildPlanPromptWithUpstream(t, contextMap, "", reportPath)
+}
+
+// BuildPlanPromptWithUpstream builds a plan prompt that may carry the direct
+// upstream results of a graph node instead of (or alongside) the context map.
+func BuildPlanPromptWithUpstream(t *Task, contextMap, upstream, reportPath string) string {
+	return BuildReplanPrompt(t, contextMap, upstream, nil, reportPath)
+}
+
+// BuildReplanPrompt builds a plan prompt for a failed task: the plan that failed,
+// its error report and the number of run attempts so far (replan nil = regular plan).
+func BuildReplanPrompt(t *Task, contextMap, upstream string, replan *replanInfo, reportPath string) string {
 	// Load template from prompts
 	tmplContent, err := prompts.Get("task")
 	if err != nil {
 		// Fallback to simple prompt if template not found
-		return buildSimplePlanPrompt(t, contextMap)
+		return buildSimplePlanPrompt(t, contextMap, upstream, replan)
 	}
 
 	tmpl, err := template.New("plan").Parse(tmplContent)
 	if err != nil {
-		return buildSimplePlanPrompt(t, contextMap)
+		return buildSimplePlanPrompt(t, contextMap, upstream, replan)
 	}
 
 	data := PlanPromptData{
@@ -43,19 +66,31 @@ func BuildPlanPrompt(t *Task, contextMap string, reportPath string) string {
 		Depth:      t.Depth,
 		MaxDepth:   MaxDepth,
 		ContextMap: contextMap,
+		Upstream:   upstream,
 		ReportPath: reportPath,
 	}
+	if t.Review != "" {
+		data.ReviewComment = t.Review
+		data.PreviousPlan = t.Plan
+	}
+	if replan != nil {
+		data.FailedPlan = replan.Plan
+		data.FailureReport = replan.Error
+		data.RunAttempts = replan.Attempts
+		data.Replan = replan.Replan
+		data.ReplanMax = replan.Max
+	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return buildSimplePlanPrompt(t, contextMap)
+		return buildSimplePlanPrompt(t, contextMap, upstream, replan)
 	}
 
 	return buf.String()
 }
 
 // buildSimplePlanPrompt is fallback when template fails
-func buildSimplePlanPrompt(t *Task, contextMap string) string {
+func buildSimplePlanPrompt(t *Task, contextMap, upstream string, replan *replanInfo) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("# Task: %s\n\n", t.Title))
@@ -73,8 +108,14 @@ func buildSimplePlanPrompt(t *Task, contextMap string) string {
 		sb.WriteString("- `clari task list [parent_id]` - Task 목록 조회\n\n")
 	}
 
+	writeUpstreamSection(&sb, upstream)
+	writeReviewSection(&sb, t)
+	writeReplanSection(&sb, replan)
+
 	sb.WriteString("---\n\n")
-	sb.WriteString("위 요구사항과 Context Map을 참고하여 [SPLIT] 또는 [PLANNED] 형식으로 응답하세요.\n")
+	sb.WriteString("위 요구사항과 Context Map을 참고하여 분할 또는 계획을 ```json 블록으로 응답하세요:\n\n")
+	sb.WriteString("```json\n{\"type\": \"planned\", \"plan\": \"## 구현 방향\\n...\"}\n```\n\n")
+	sb.WriteString("```json\n{\"type\": \"split\", \"children\": [{\"title\": \"...\", \"spec\": \"...\", \"priority\": 0, \"after\": [], \"depends_on\": []}]}\n```\n")
 
 	return sb.String()
 }
@@ -85,41 +126,58 @@ type ExecutePromptData struct {
 	Title      string
 	Plan       string
 	ContextMap string
+	Upstream   string
 	ReportPath string
+	// VerifyFailure is the failed verification output for a fix-up run (empty otherwise)
+	VerifyFailure string
 }
 
 // BuildExecutePrompt builds prompt for execution (2회차 순회)
 func BuildExecutePrompt(t *Task, contextMap string, reportPath string) string {
+	return BuildExecutePromptWithUpstream(t, contextMap, "", reportPath)
+}
+
+// BuildExecutePromptWithUpstream builds an execute prompt that may carry the
+// direct upstream results of a graph node instead of (or alongside) the context map.
+func BuildExecutePromptWithUpstream(t *Task, contextMap, upstream, reportPath string) string {
+	return BuildFixPrompt(t, contextMap, upstream, "", reportPath)
+}
+
+// BuildFixPrompt builds an execute prompt for a fix-up run: the original plan plus
+// the output of the verification commands that failed after the previous run.
+func BuildFixPrompt(t *Task, contextMap, upstream, verifyFailure, reportPath string) string {
 	// Load template from prompts
 	tmplContent, err := prompts.Get("task_run")
 	if err != nil {
 		// Fallback to simple prompt if template not found
-		return buildSimpleExecutePrompt(t, contextMap, reportPath)
+		return buildSimpleExecutePrompt(t, contextMap, upstream, verifyFailure, reportPath)
 	}
 
 	tmpl, err := template.New("execute").Parse(tmplContent)
 	if err != nil {
-		return buildSimpleExecutePrompt(t, contextMap, reportPath)
+		return buildSimpleExecutePrompt(t, contextMap, upstream, verifyFailure, reportPath)
 	}
 
 	data := ExecutePromptData{
-		TaskID:     t.ID,
-		Title:      t.Title,
-		Plan:       t.Plan,
-		ContextMap: contextMap,
-		ReportPath: reportPath,
+		TaskID:        t.ID,
+		Title:         t.Title,
+		Plan:          t.Plan,
+		ContextMap:    contextMap,
+		Upstream:      upstream,
+		ReportPath:    reportPath,
+		VerifyFailure: verifyFailure,
 	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return buildSimpleExecutePrompt(t, contextMap, reportPath)
+		return buildSimpleExecutePrompt(t, contextMap, upstream, verifyFailure, reportPath)
 	}
 
 	return buf.String()
 }
 
 // buildSimpleExecutePrompt is fallback when template fails
-func buildSimpleExecutePrompt(t *Task, contextMap string, reportPath string) string {
+func buildSimpleExecutePrompt(t *Task, contextMap, upstream, verifyFailure, reportPath string) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("# Task: %s\n\n", t.Title))
@@ -137,6 +195,15 @@ func buildSimpleExecutePrompt(t *Task, contextMap string, reportPath string) str
 		sb.WriteString("- `clari task list [parent_id]` - Task 목록 조회\n\n")
 	}
 
+	writeUpstreamSection(&sb, upstream)
+
+	if verifyFailure != "" {
+		sb.WriteString("## ⚠️ 검증 실패 (수정 필요)\n\n")
+		sb.WriteString("이전 실행 후 검증 명령이 실패했습니다. 아래 출력을 보고 원인을 수정하세요.\n\n")
+		sb.WriteString(verifyFailure)
+		sb.WriteString("\n\n")
+	}
+
 	sb.WriteString("---\n\n")
 	sb.WriteString("위 계획서와 연관 자료를 참고하여 작업을 수행하세요.\n\n")
 	sb.WriteString("완료 후 보고서를 작성하세요:\n")
@@ -151,3 +218,67 @@ func buildSimpleExecutePrompt(t *Task, contextMap string, reportPath string) str
 
 	return sb.String()
 }
+
+// writeReviewSection appends the rejected plan and the reviewer's comment if present.
+func writeReviewSection(sb *strings.Builder, t *Task) {
+	if t.Review == "" {
+		return
+	}
+	sb.WriteString("## ⚠️ 반려된 Plan (재작성 필요)\n\n")
+	sb.WriteString("이전 Plan이 리뷰어에게 반려되었습니다. 반려 사유를 반영하여 Plan을 다시 작성하세요.\n\n")
+	sb.WriteString("### 반려 사유\n\n")
+	sb.WriteString(t.Review)
+	sb.WriteString("\n\n")
+	if t.Plan != "" {
+		sb.WriteString("### 이전 Plan\n\n")
+		sb.WriteString(t.Plan)
+		sb.WriteString("\n\n")
+	}
+}
+
+// writeReplanSection appends the failed plan and its error report for a re-plan.
+func writeReplanSection(sb *strings.Builder, replan *replanInfo) {
+	if replan == nil {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("## ⚠️ 실행 실패 후 재계획 (%d/%d회차, 실행 시도 %d회)\n\n", replan.Replan, replan.Max, replan.Attempts))
+	sb.WriteString("이전 Plan으로 실행했지만 실패했습니다. 실패 원인을 분석하여 다른 접근으로 Plan을 다시 작성하거나, 너무 크다면 분할하세요.\n\n")
+	if replan.Plan != "" {
+		sb.WriteString("### 실패한 Plan\n\n")
+		sb.WriteString(replan.Plan)
+		sb.WriteString("\n\n")
+	}
+	if replan.Error != "" {
+		sb.WriteString("### 에러 내용\n\n")
+		sb.WriteString(replan.Error)
+		sb.WriteString("\n\n")
+	}
+}
+
+// formatRetrySection tells Claude why its previous plan output was rejected.
+func formatRetrySection(err error, attempt int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n\n## ⚠️ 출력 형식 오류 (재요청 %d/%d)\n\n", attempt, maxPlanFormatAttempts-1))
+	sb.WriteString("이전 출력이 계획 스키마를 통과하지 못했습니다:\n\n")
+	var formatErr *PlanFormatError
+	if errors.As(err, &formatErr) {
+		for _, p := range formatErr.Problems {
+			sb.WriteString("- " + p + "\n")
+		}
+	} else {
+		sb.WriteString("- " + err.Error() + "\n")
+	}
+	sb.WriteString("\n위 문제를 고쳐 ```json 계획 블록 하나로 다시 출력하세요. 하위 작업은 아직 생성되지 않았습니다.\n")
+	return sb.String()
+}
+
+// writeUpstreamSection appends the upstream node results section if present.
+func writeUpstreamSection(sb *strings.Builder, upstream string) {
+	if upstream == "" {
+		return
+	}
+	sb.WriteString("## Upstream Nodes\n\n")
+	sb.WriteString("이 노드가 직접 의존하는 선행 노드의 결과입니다. 이 외의 작업은 탐색하지 마세요.\n\n")
+	sb.WriteString(upstream)
+	sb.WriteString("\n")
+}
@@ -0,0 +1,207 @@
+package task
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+	"parkjunwoo.com/claribot/pkg/pagination"
+)
+
+// SavedQuery is a named task list filter stored per project.
+type SavedQuery struct {
+	Name      string     `json:"name"`
+	Filter    ListFilter `json:"filter"`
+	Query     string     `json:"query"` // filter as command flags
+	CreatedAt string     `json:"created_at"`
+	UpdatedAt string     `json:"updated_at"`
+}
+
+var queryNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// reservedQueryNames collide with the "task query" subcommands.
+var reservedQueryNames = map[string]bool{"save": true, "list": true, "delete": true}
+
+// validateQueryName checks a saved query name (lower-case letters, digits, '-' and '_').
+func validateQueryName(name string) error {
+	if !queryNameRe.MatchString(name) {
+		return fmt.Errorf("쿼리 이름은 영문 소문자/숫자/-/_ 50자 이하여야 합니다: %s", name)
+	}
+	if reservedQueryNames[name] {
+		return fmt.Errorf("예약된 이름입니다: %s", name)
+	}
+	return nil
+}
+
+// SaveQuery creates or replaces a saved query.
+func SaveQuery(projectPath, name string, filter ListFilter) types.Result {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if err := validateQueryName(name); err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+	if filter.IsEmpty() {
+		return types.Result{Success: false, Message: "필터 조건이 없습니다 (--label, --status, --depth, --root, --text)"}
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("쿼리 직렬화 실패: %v", err),
+		}
+	}
+	now := db.TimeNow()
+	if _, err := localDB.Exec(`
+		INSERT INTO saved_queries (name, query, created_at, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET query = excluded.query, updated_at = excluded.updated_at
+	`, name, string(data), now, now); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("쿼리 저장 실패: %v", err),
+		}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("쿼리 저장됨: %s → %s\n[실행:task query %s]", name, filter, name),
+		Data:    &SavedQuery{Name: name, Filter: filter, Query: filter.String(), UpdatedAt: now},
+	}
+}
+
+// loadQuery returns a saved query by name.
+func loadQuery(localDB *db.DB, name string) (*SavedQuery, error) {
+	var q SavedQuery
+	var data string
+	err := localDB.QueryRow(`SELECT name, query, created_at, updated_at FROM saved_queries WHERE name = ?`, name).
+		Scan(&q.Name, &data, &q.CreatedAt, &q.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("저장된 쿼리를 찾을 수 없습니다: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("쿼리 조회 실패: %w", err)
+	}
+	if err := json.Unmarshal([]byte(data), &q.Filter); err != nil {
+		return nil, fmt.Errorf("쿼리 %s 파싱 실패: %w", name, err)
+	}
+	q.Query = q.Filter.String()
+	return &q, nil
+}
+
+// ListQueries lists the saved queries of a project.
+func ListQueries(projectPath string) types.Result {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	rows, err := localDB.Query(`SELECT name FROM saved_queries ORDER BY name`)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("조회 실패: %v", err),
+		}
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("스캔 실패: %v", err),
+			}
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	if len(names) == 0 {
+		return types.Result{
+			Success: true,
+			Message: "저장된 쿼리가 없습니다.\n사용법: task query save <name> --label <label> --status <status> ...",
+		}
+	}
+
+	queries := make([]SavedQuery, 0, len(names))
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔖 저장된 쿼리 (%d개)\n", len(names)))
+	for _, name := range names {
+		q, err := loadQuery(localDB, name)
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("  ⚠️ %s: %v\n", name, err))
+			continue
+		}
+		queries = append(queries, *q)
+		sb.WriteString(fmt.Sprintf("  [%s:task query %s] %s\n", q.Name, q.Name, q.Query))
+	}
+
+	return types.Result{
+		Success: true,
+		Message: sb.String(),
+		Data:    queries,
+	}
+}
+
+// DeleteQuery removes a saved query.
+func DeleteQuery(projectPath, name string) types.Result {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	res, err := localDB.Exec(`DELETE FROM saved_queries WHERE name = ?`, name)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("쿼리 삭제 실패: %v", err),
+		}
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("저장된 쿼리를 찾을 수 없습니다: %s", name),
+		}
+	}
+	return types.Result{
+		Success: true,
+		Message: fmt.Sprintf("쿼리 삭제됨: %s", name),
+	}
+}
+
+// RunQuery lists the tasks matching a saved query.
+func RunQuery(projectPath, name string, req pagination.PageRequest) types.Result {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	q, err := loadQuery(localDB, name)
+	localDB.Close()
+	if err != nil {
+		return types.Result{Success: false, Message: err.Error()}
+	}
+	return ListFiltered(projectPath, q.Filter, "task query "+q.Name, req)
+}
@@ -14,10 +14,11 @@ CREATE TABLE tasks (
     parent_id INTEGER,
     title TEXT NOT NULL,
     status TEXT DEFAULT 'todo'
-        CHECK(status IN ('todo', 'split', 'planned', 'done', 'failed')),
+        CHECK(status IN ('todo', 'split', 'planned', 'review', 'done', 'failed')),
     priority INTEGER DEFAULT 0,
     is_leaf INTEGER DEFAULT 1,
     depth INTEGER DEFAULT 0,
+    replans INTEGER DEFAULT 0,
     created_at TEXT NOT NULL,
     updated_at TEXT NOT NULL,
     FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE
@@ -38,11 +39,14 @@ func Rebuild(projectPath string) (int, error) {
 
 	// 2. Validate and read each file
 	type taskData struct {
-		ID       int
-		Title    string
-		Status   string
-		ParentID *int
-		Priority int
+		ID        int
+		Title     string
+		Status    string
+		ParentID  *int
+		Priority  int
+		DependsOn []int
+		Node      string
+		Labels    []string
 	}
 	var tasks []taskData
 	parentMap := make(map[int]*int)
@@ -58,11 +62,14 @@ func Rebuild(projectPath string) (int, error) {
 			continue
 		}
 		td := taskData{
-			ID:       id,
-			Title:    tc.Title,
-			Status:   tc.Frontmatter.Status,
-			ParentID: tc.Frontmatter.Parent,
-			Priority: tc.Frontmatter.Priority,
+			ID:        id,
+			Title:     tc.Title,
+			Status:    tc.Frontmatter.Status,
+			ParentID:  tc.Frontmatter.Parent,
+			Priority:  tc.Frontmatter.Priority,
+			DependsOn: tc.Frontmatter.DependsOn,
+			Node:      tc.Frontmatter.Node,
+			Labels:    normalizeLabels(tc.Frontmatter.Labels),
 		}
 		tasks = append(tasks, td)
 		parentMap[id] = tc.Frontmatter.Parent
@@ -75,6 +82,19 @@ func Rebuild(projectPath string) (int, error) {
 	}
 	defer localDB.Close()
 
+	// Statuses before the rebuild (best effort: the table may be broken), for task_events
+	oldStatus := make(map[int]string)
+	if rows, err := localDB.Query(`SELECT id, status FROM tasks`); err == nil {
+		for rows.Next() {
+			var id int
+			var status string
+			if rows.Scan(&id, &status) == nil {
+				oldStatus[id] = status
+			}
+		}
+		rows.Close()
+	}
+
 	localDB.Exec(`PRAGMA foreign_keys=OFF`)
 	defer localDB.Exec(`PRAGMA foreign_keys=ON`)
 
@@ -107,27 +127,93 @@ func Rebuild(projectPath string) (int, error) {
 			log.Printf("[Rebuild] INSERT 실패 (#%d): %v", t.ID, err)
 			continue
 		}
+		if from, ok := oldStatus[t.ID]; ok {
+			recordEvent(tx, t.ID, from, t.Status, ActorRebuild, 0)
+		}
+	}
+
+	// 6. Restore dependencies from frontmatter (skip edges that would form a cycle)
+	tx.Exec(`DELETE FROM task_deps`)
+	rebuiltDeps := make(map[int][]int)
+	for _, t := range tasks {
+		if len(t.DependsOn) == 0 {
+			continue
+		}
+		// Foreign keys are off during rebuild: drop references to missing tasks explicitly
+		var deps []int
+		for _, dep := range t.DependsOn {
+			if _, ok := parentMap[dep]; ok {
+				deps = append(deps, dep)
+			} else {
+				log.Printf("[Rebuild] 존재하지 않는 선행 작업 skip (#%d → #%d)", t.ID, dep)
+			}
+		}
+		t.DependsOn = deps
+		if len(t.DependsOn) == 0 {
+			continue
+		}
+		if cycle := findDepCycle(rebuiltDeps, t.ID, t.DependsOn); cycle != nil {
+			log.Printf("[Rebuild] 순환 의존성 skip (#%d): %s", t.ID, formatDependsOn(cycle))
+			continue
+		}
+		for _, dep := range t.DependsOn {
+			if _, err := tx.Exec(`INSERT INTO task_deps (task_id, depends_on, created_at) VALUES (?, ?, ?)`, t.ID, dep, now); err != nil {
+				log.Printf("[Rebuild] depends_on INSERT 실패 (#%d → #%d): %v", t.ID, dep, err)
+			}
+		}
+		rebuiltDeps[t.ID] = t.DependsOn
 	}
 
-	// 6. Compute is_leaf
+	// 7. Restore graph nodes from frontmatter
+	tx.Exec(`DELETE FROM graph_nodes`)
+	for _, t := range tasks {
+		if t.Node == "" {
+			continue
+		}
+		layer, feature, ok := parseNodeID(t.Node)
+		if !ok {
+			log.Printf("[Rebuild] 잘못된 node ID skip (#%d): %s", t.ID, t.Node)
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO graph_nodes (task_id, node_id, feature_id, layer) VALUES (?, ?, ?, ?)`, t.ID, t.Node, feature, layer); err != nil {
+			log.Printf("[Rebuild] node INSERT 실패 (#%d %s): %v", t.ID, t.Node, err)
+		}
+	}
+
+	// 8. Restore labels from frontmatter
+	tx.Exec(`DELETE FROM task_labels`)
+	for _, t := range tasks {
+		for _, label := range t.Labels {
+			if _, err := tx.Exec(`INSERT INTO task_labels (task_id, label) VALUES (?, ?)`, t.ID, label); err != nil {
+				log.Printf("[Rebuild] label INSERT 실패 (#%d %s): %v", t.ID, label, err)
+			}
+		}
+	}
+
+	// 9. Compute is_leaf
 	tx.Exec(`UPDATE tasks SET is_leaf = 0 WHERE id IN (SELECT DISTINCT parent_id FROM tasks WHERE parent_id IS NOT NULL)`)
 
-	// 7. Compute depth
+	// 10. Compute depth
 	for _, t := range tasks {
 		d := computeDepth(t.ID, parentMap)
 		tx.Exec(`UPDATE tasks SET depth = ? WHERE id = ?`, d, t.ID)
 	}
 
-	// 8. Fix sqlite_sequence
+	// 11. Fix sqlite_sequence
 	tx.Exec(`DELETE FROM sqlite_sequence WHERE name='tasks'`)
 	tx.Exec(`INSERT INTO sqlite_sequence (name, seq) VALUES ('tasks', (SELECT COALESCE(MAX(id), 0) FROM tasks))`)
 
-	// 9. Commit
+	// 12. Commit
 	if err := tx.Commit(); err != nil {
 		return 0, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
 	}
 
-	// 10. Git commit
+	// 13. Rebuild the search index
+	if _, err := reindexSearch(localDB, projectPath); err != nil {
+		log.Printf("[Rebuild] 검색 색인 재구축 실패: %v", err)
+	}
+
+	// 14. Git commit
 	gitCommitBatch(projectPath, fmt.Sprintf("rebuild: %d tasks from files", len(tasks)))
 
 	return len(tasks), nil
@@ -0,0 +1,229 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+)
+
+// defaultReplanMax is the number of re-plans allowed per task.
+const defaultReplanMax = 2
+
+// ReplanConfig controls re-planning of failed tasks.
+type ReplanConfig struct {
+	Max  int  // re-plans allowed per task (0 = never)
+	Auto bool // cycle re-plans failed tasks between its plan and run phases
+}
+
+// replanInfo is the failure context given to a re-plan prompt.
+type replanInfo struct {
+	Plan     string // plan whose run failed
+	Error    string // {id}.error.md
+	Attempts int    // run attempts so far
+	Replan   int    // 1-based number of this re-plan
+	Max      int
+}
+
+// getReplanConfig reads the re-plan config from project local DB (replan_max, replan_auto).
+func getReplanConfig(localDB *db.DB) ReplanConfig {
+	cfg := ReplanConfig{Max: defaultReplanMax}
+	var val string
+	if err := localDB.QueryRow("SELECT value FROM config WHERE key = 'replan_max'").Scan(&val); err == nil {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.Max = n
+		}
+	}
+	if err := localDB.QueryRow("SELECT value FROM config WHERE key = 'replan_auto'").Scan(&val); err == nil {
+		cfg.Auto = val == "true"
+	}
+	return cfg
+}
+
+// Replan re-plans a failed task with its failed plan and error report
+// (failed → planned/split, or review in approval mode). actor is recorded on the status changes.
+func Replan(projectPath, id, actor string) types.Result {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	var t Task
+	err = localDB.QueryRow(`
+		SELECT id, parent_id, title, status, is_leaf, depth FROM tasks WHERE id = ?
+	`, strings.TrimPrefix(id, "#")).Scan(&t.ID, &t.ParentID, &t.Title, &t.Status, &t.IsLeaf, &t.Depth)
+	if err == sql.ErrNoRows {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("작업을 찾을 수 없습니다: #%s", id),
+		}
+	}
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("조회 실패: %v", err),
+		}
+	}
+	LoadContent(projectPath, &t)
+
+	if t.Status != "failed" {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("작업 #%d은(는) %s 상태입니다. (failed 상태만 재계획 가능)", t.ID, t.Status),
+		}
+	}
+	if !t.IsLeaf {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("작업 #%d은(는) 하위 작업이 있습니다. 실패한 하위 작업을 재계획하세요.\n[하위 순회:task cycle %d]", t.ID, t.ID),
+		}
+	}
+
+	// Insert traversal record
+	travID, travErr := insertTraversal(localDB, "plan", &t.ID, "")
+	if travErr != nil {
+		log.Printf("[Task] traversal INSERT 실패: %v", travErr)
+	}
+
+	result := replanTask(withTraversal(WithActor(context.Background(), actor), travID), localDB, projectPath, &t, getReplanConfig(localDB))
+
+	// Update traversal record
+	if travErr == nil {
+		status := "done"
+		total, success, failed := 1, 0, 0
+		if result.Success {
+			success = 1
+		} else {
+			status = "failed"
+			failed = 1
+		}
+		finishTraversal(localDB, travID, status, total, success, failed)
+	}
+
+	return result
+}
+
+// replanTask re-plans a failed task unless it reached the per-task cap.
+// The counter is bumped before Claude runs, so a re-plan that fails still counts.
+func replanTask(ctx context.Context, localDB *db.DB, projectPath string, t *Task, cfg ReplanConfig) types.Result {
+	var replans int
+	if err := localDB.QueryRow("SELECT COALESCE(replans, 0) FROM tasks WHERE id = ?", t.ID).Scan(&replans); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("조회 실패: %v", err),
+		}
+	}
+	if replans >= cfg.Max {
+		return types.Result{
+			Success:   false,
+			Message:   fmt.Sprintf("작업 #%d 재계획 한도 도달 (%d/%d). 직접 수정 후 'task set %d status todo'로 되돌리세요.", t.ID, replans, cfg.Max, t.ID),
+			ErrorType: "replan_limit",
+		}
+	}
+
+	var attempts int
+	localDB.QueryRow("SELECT COUNT(*) FROM task_attempts WHERE task_id = ? AND phase = 'run'", t.ID).Scan(&attempts)
+
+	if _, err := localDB.Exec("UPDATE tasks SET replans = replans + 1, updated_at = ? WHERE id = ?", db.TimeNow(), t.ID); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("재계획 횟수 갱신 실패: %v", err),
+		}
+	}
+
+	info := &replanInfo{
+		Plan:     t.Plan,
+		Error:    t.Error,
+		Attempts: attempts,
+		Replan:   replans + 1,
+		Max:      cfg.Max,
+	}
+	log.Printf("[Task] Replan: task #%d (%d/%d, run attempts %d)", t.ID, info.Replan, info.Max, attempts)
+	result := planTask(ctx, localDB, projectPath, t, info)
+	// A failed parent goes back to split once its child has a new plan
+	rollUp(ctx, localDB, projectPath)
+	return result
+}
+
+// replanFailed re-plans every failed leaf that is below the re-plan cap (used by Cycle),
+// limited to the subtree of rootID (0 = whole project).
+// Returns the number of tasks that got a new plan or split.
+func replanFailed(ctx context.Context, projectPath string, rootID int) (int, types.Result) {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return 0, types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	cfg := getReplanConfig(localDB)
+	scope, scopeArgs := scopeFilter(rootID)
+	rows, err := localDB.Query(`
+		SELECT id, parent_id, title, status, is_leaf, depth FROM tasks
+		WHERE status = 'failed' AND is_leaf = 1 AND COALESCE(replans, 0) < ?`+scope+`
+		ORDER BY priority DESC, depth ASC, id ASC
+	`, append([]interface{}{cfg.Max}, scopeArgs...)...)
+	if err != nil {
+		return 0, types.Result{
+			Success: false,
+			Message: fmt.Sprintf("조회 실패: %v", err),
+		}
+	}
+	var tasks []Task
+	for rows.Next() {
+		var t Task
+		if err := rows.Scan(&t.ID, &t.ParentID, &t.Title, &t.Status, &t.IsLeaf, &t.Depth); err != nil {
+			continue
+		}
+		LoadContent(projectPath, &t)
+		tasks = append(tasks, t)
+	}
+	rows.Close()
+
+	if len(tasks) == 0 {
+		return 0, types.Result{Success: true}
+	}
+
+	UpdatePhase(projectPath, "plan", len(tasks))
+	var success, failed int
+	var messages []string
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			messages = append(messages, "🛑 중단 요청으로 나머지 재계획 건너뜀")
+			break
+		}
+		result := replanTask(ctx, localDB, projectPath, &t, cfg)
+		IncrementCompleted(projectPath)
+		if result.Success {
+			success++
+			messages = append(messages, fmt.Sprintf("✅ #%d %s", t.ID, t.Title))
+			continue
+		}
+		failed++
+		messages = append(messages, fmt.Sprintf("❌ #%d %s: %s", t.ID, t.Title, truncateLine(result.Message, 200)))
+		if result.ErrorType == "auth_error" {
+			messages = append(messages, "🔐 인증 오류로 나머지 재계획 건너뜀")
+			return success, types.Result{
+				Success:   false,
+				Message:   fmt.Sprintf("🔁 실패 작업 재계획: 성공 %d, 실패 %d\n%s", success, failed, strings.Join(messages, "\n")),
+				ErrorType: "auth_error",
+			}
+		}
+	}
+
+	return success, types.Result{
+		Success: true,
+		Message: fmt.Sprintf("🔁 실패 작업 재계획: 성공 %d, 실패 %d\n%s", success, failed, strings.Join(messages, "\n")),
+	}
+}
@@ -0,0 +1,78 @@
+package task
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestReplanConfig(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	if cfg := getReplanConfig(localDB); cfg.Max != defaultReplanMax || cfg.Auto {
+		t.Errorf("Unexpected default config: %+v", cfg)
+	}
+	for k, v := range map[string]string{"replan_max": "0", "replan_auto": "true"} {
+		localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES (?, ?, ?)", k, v, db.TimeNow())
+	}
+	if cfg := getReplanConfig(localDB); cfg.Max != 0 || !cfg.Auto {
+		t.Errorf("Unexpected configured config: %+v", cfg)
+	}
+}
+
+func TestReplanLimit(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Flaky task", nil, "spec")
+	if r := Replan(projectPath, "1", ActorCLI); r.Success || !strings.Contains(r.Message, "failed 상태만") {
+		t.Errorf("Expected replan of a todo task to be refused: %s", r.Message)
+	}
+
+	Set(projectPath, "1", "status", "failed", ActorCLI)
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+	localDB.Exec("UPDATE tasks SET replans = ? WHERE id = 1", defaultReplanMax)
+
+	task := &Task{ID: 1, Title: "Flaky task", Status: "failed", IsLeaf: true}
+	result := replanTask(context.Background(), localDB, projectPath, task, getReplanConfig(localDB))
+	if result.Success || result.ErrorType != "replan_limit" {
+		t.Errorf("Expected replan_limit, got %+v", result)
+	}
+
+	// Tasks at the cap are not picked up by the cycle
+	if n, result := replanFailed(context.Background(), projectPath, 0); n != 0 || result.Message != "" {
+		t.Errorf("Expected nothing to re-plan, got %d %q", n, result.Message)
+	}
+
+	if got := Get(projectPath, "1"); !strings.Contains(got.Message, "Replans: 2/2") || !strings.Contains(got.Message, "[재계획:task replan 1]") {
+		t.Errorf("Expected replan count and button in task get: %s", got.Message)
+	}
+}
+
+func TestBuildReplanPrompt(t *testing.T) {
+	task := &Task{ID: 4, Title: "Add cache", Spec: "cache the API", Plan: "use a map"}
+	info := &replanInfo{Plan: "use a map", Error: "data race detected", Attempts: 3, Replan: 1, Max: 2}
+
+	prompt := BuildReplanPrompt(task, "", "", info, "/tmp/report.md")
+	for _, want := range []string{"use a map", "data race detected", "1/2", "3회"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected %q in replan prompt", want)
+		}
+	}
+	if strings.Contains(BuildPlanPrompt(task, "", "/tmp/report.md"), "data race detected") {
+		t.Error("Regular plan prompt should not include the failure")
+	}
+}
@@ -0,0 +1,224 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+// interruptedTraversal is a traversal the previous daemon process left running.
+type interruptedTraversal struct {
+	ID            int64
+	Type          string // "cycle", "plan", "run"
+	TargetID      int    // subtree root of a batch traversal, task of a single plan/run (0 = whole project)
+	Batch         bool   // cycle, plan --all, run --all (progress kept in cycle_state)
+	Phase         string
+	TargetTotal   int
+	Completed     int
+	CurrentTaskID int
+}
+
+// saveCycleState persists the progress of a running traversal in cycle_state,
+// so that a restart can tell where it stopped.
+func saveCycleState(projectPath string, state CycleState) {
+	if state.TraversalID == 0 {
+		return
+	}
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		log.Printf("[Task] cycle_state 저장 실패 (traversal=%d): %v", state.TraversalID, err)
+		return
+	}
+	defer localDB.Close()
+
+	_, err = localDB.Exec(`
+		INSERT OR REPLACE INTO cycle_state (traversal_id, type, root_id, phase, target_total, completed, current_task_id, started_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, state.TraversalID, state.Type, state.RootID, state.Phase, state.TargetTotal, state.Completed, state.CurrentTaskID,
+		state.StartedAt.UTC().Format(time.RFC3339), db.TimeNow())
+	if err != nil {
+		log.Printf("[Task] cycle_state 저장 실패 (traversal=%d): %v", state.TraversalID, err)
+	}
+}
+
+// deleteCycleState removes the persisted progress of a finished traversal.
+func deleteCycleState(projectPath string, travID int64) {
+	if travID == 0 {
+		return
+	}
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		log.Printf("[Task] cycle_state 삭제 실패 (traversal=%d): %v", travID, err)
+		return
+	}
+	defer localDB.Close()
+
+	if _, err := localDB.Exec(`DELETE FROM cycle_state WHERE traversal_id = ?`, travID); err != nil {
+		log.Printf("[Task] cycle_state 삭제 실패 (traversal=%d): %v", travID, err)
+	}
+}
+
+// getAutoResume reads auto_resume from project local DB config.
+func getAutoResume(localDB *db.DB) bool {
+	var val string
+	if err := localDB.QueryRow("SELECT value FROM config WHERE key = 'auto_resume'").Scan(&val); err != nil {
+		return false
+	}
+	return val == "true"
+}
+
+// markInterrupted marks every traversal of a project that is still running as interrupted
+// (nothing runs yet at startup, so they were all cut short by the restart) and cancels
+// their running attempts. Returns them oldest first, and the latest batch traversal
+// to resume when auto_resume is on and its subtree root still exists (nil otherwise).
+func markInterrupted(projectPath string) ([]interruptedTraversal, *interruptedTraversal, error) {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DB 열기 실패: %w", err)
+	}
+	defer localDB.Close()
+
+	rows, err := localDB.Query(`
+		SELECT t.id, t.type, COALESCE(c.root_id, t.target_id, 0), c.traversal_id IS NOT NULL,
+			COALESCE(c.phase, ''), COALESCE(c.target_total, 0), COALESCE(c.completed, 0), COALESCE(c.current_task_id, 0)
+		FROM traversals t LEFT JOIN cycle_state c ON c.traversal_id = t.id
+		WHERE t.status = 'running'
+		ORDER BY t.id ASC
+	`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("traversal 조회 실패: %w", err)
+	}
+	var list []interruptedTraversal
+	for rows.Next() {
+		var t interruptedTraversal
+		if err := rows.Scan(&t.ID, &t.Type, &t.TargetID, &t.Batch, &t.Phase, &t.TargetTotal, &t.Completed, &t.CurrentTaskID); err != nil {
+			continue
+		}
+		list = append(list, t)
+	}
+	rows.Close()
+
+	now := db.TimeNow()
+	if _, err := localDB.Exec(`UPDATE traversals SET status = 'interrupted', finished_at = ? WHERE status = 'running'`, now); err != nil {
+		return nil, nil, fmt.Errorf("traversal 갱신 실패: %w", err)
+	}
+	localDB.Exec(`DELETE FROM cycle_state`)
+	localDB.Exec(`
+		UPDATE task_attempts SET status = 'cancelled', error_type = 'interrupted', error = '재시작으로 중단됨', finished_at = ?
+		WHERE status = 'running'
+	`, now)
+
+	if len(list) == 0 || !getAutoResume(localDB) {
+		return list, nil, nil
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		t := list[i]
+		if !t.Batch {
+			continue
+		}
+		if t.TargetID != 0 {
+			var exists int
+			if err := localDB.QueryRow("SELECT 1 FROM tasks WHERE id = ?", t.TargetID).Scan(&exists); err != nil {
+				return list, nil, nil
+			}
+		}
+		return list, &list[i], nil
+	}
+	return list, nil, nil
+}
+
+// RecoverTraversals handles the traversals of a project that a daemon restart cut short
+// (called once at startup, before any traversal runs). They are marked interrupted, and
+// with auto_resume on the latest cycle / plan --all / run --all starts again in the
+// background over the remaining todo/planned tasks. The admin chat is told which one happened.
+func RecoverTraversals(projectPath string) {
+	// Projects without a local DB have never run anything
+	if _, err := os.Stat(filepath.Join(projectPath, ".claribot", "db.clt")); err != nil {
+		return
+	}
+
+	list, resume, err := markInterrupted(projectPath)
+	if err != nil {
+		log.Printf("[Task] 중단된 순회 복구 실패 (%s): %v", getProjectID(projectPath), err)
+		return
+	}
+	if len(list) == 0 {
+		return
+	}
+
+	projectID := getProjectID(projectPath)
+	lines := []string{fmt.Sprintf("⚠️ [%s] 재시작으로 중단된 순회 %d개", projectID, len(list))}
+	for _, t := range list {
+		line := "- " + formatInterrupted(t)
+		if resume != nil && t.ID == resume.ID {
+			line += " → 자동 재개"
+		} else {
+			line += fmt.Sprintf(" → interrupted (재개: %s)", resumeCommand(t))
+		}
+		lines = append(lines, line)
+	}
+	if resume == nil {
+		lines = append(lines, fmt.Sprintf("자동 재개: project set %s auto_resume true", projectID))
+	}
+	log.Printf("[Task] 중단된 순회 %d개 (%s), 자동 재개: %t", len(list), projectID, resume != nil)
+
+	if globalNotifier != nil {
+		globalNotifier(nil, strings.Join(lines, "\n"))
+	}
+
+	if resume != nil {
+		t := *resume
+		trigger := fmt.Sprintf("resume:%d", t.ID)
+		go func() {
+			switch t.Type {
+			case "cycle":
+				cycle(projectPath, t.TargetID, trigger)
+			case "plan":
+				planAll(projectPath, t.TargetID, trigger)
+			case "run":
+				runAll(projectPath, t.TargetID, trigger)
+			}
+		}()
+	}
+}
+
+// formatInterrupted renders an interrupted traversal with the progress it had reached.
+func formatInterrupted(t interruptedTraversal) string {
+	name := map[string]string{"cycle": "Cycle", "plan": "Plan", "run": "Run"}[t.Type]
+	var s string
+	if t.Batch {
+		s = fmt.Sprintf("%s%s 순회", scopeLabel(t.TargetID), name)
+	} else {
+		s = fmt.Sprintf("%s #%d", name, t.TargetID)
+	}
+	s = fmt.Sprintf("#%d %s", t.ID, s)
+	if t.Phase != "" {
+		s += fmt.Sprintf(" (%s 단계 %d/%d", t.Phase, t.Completed, t.TargetTotal)
+		if t.CurrentTaskID != 0 {
+			s += fmt.Sprintf(", 작업 #%d", t.CurrentTaskID)
+		}
+		s += ")"
+	}
+	return s
+}
+
+// resumeCommand returns the command that restarts an interrupted traversal by hand.
+func resumeCommand(t interruptedTraversal) string {
+	switch {
+	case !t.Batch:
+		return fmt.Sprintf("task %s %d", t.Type, t.TargetID)
+	case t.Type == "cycle" && t.TargetID != 0:
+		return fmt.Sprintf("task cycle %d", t.TargetID)
+	case t.Type == "cycle":
+		return "task cycle"
+	case t.TargetID != 0:
+		return fmt.Sprintf("task %s --all %d", t.Type, t.TargetID)
+	default:
+		return fmt.Sprintf("task %s --all", t.Type)
+	}
+}
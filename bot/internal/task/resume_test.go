@@ -0,0 +1,129 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestCycleStatePersisted(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	travID, err := insertTraversal(localDB, "cycle", nil, "")
+	if err != nil {
+		t.Fatalf("insertTraversal failed: %v", err)
+	}
+	SetCycleState(projectPath, CycleState{Running: true, Type: "cycle", StartedAt: time.Now(), ProjectPath: projectPath, TraversalID: travID})
+	UpdatePhase(projectPath, "run", 5)
+	IncrementCompleted(projectPath)
+	IncrementCompleted(projectPath)
+	UpdateCurrentTask(projectPath, 3)
+
+	var phase string
+	var total, completed, current int
+	err = localDB.QueryRow(`SELECT phase, target_total, completed, current_task_id FROM cycle_state WHERE traversal_id = ?`, travID).
+		Scan(&phase, &total, &completed, &current)
+	if err != nil {
+		t.Fatalf("cycle_state row missing: %v", err)
+	}
+	if phase != "run" || total != 5 || completed != 2 || current != 3 {
+		t.Errorf("Unexpected persisted progress: %s %d/%d #%d", phase, completed, total, current)
+	}
+
+	ClearCycleState(projectPath)
+	var n int
+	localDB.QueryRow(`SELECT COUNT(*) FROM cycle_state`).Scan(&n)
+	if n != 0 {
+		t.Errorf("Expected cycle_state to be cleared, got %d rows", n)
+	}
+}
+
+func TestMarkInterrupted(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Root", nil, "spec")
+	parentID := 1
+	Add(projectPath, "Child", &parentID, "spec")
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	// A finished traversal, a batch cycle on #1 in its run phase and a single run of #2
+	doneID, _ := insertTraversal(localDB, "plan", nil, "")
+	finishTraversal(localDB, doneID, "done", 1, 1, 0)
+	cycleID, _ := insertTraversal(localDB, "cycle", &parentID, "")
+	saveCycleState(projectPath, CycleState{Type: "cycle", RootID: 1, Phase: "run", TargetTotal: 4, Completed: 1, CurrentTaskID: 2, StartedAt: time.Now(), TraversalID: cycleID})
+	taskID := 2
+	runID, _ := insertTraversal(localDB, "run", &taskID, "")
+
+	list, resume, err := markInterrupted(projectPath)
+	if err != nil {
+		t.Fatalf("markInterrupted failed: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != cycleID || list[1].ID != runID {
+		t.Fatalf("Expected the two running traversals, got %+v", list)
+	}
+	if !list[0].Batch || list[0].TargetID != 1 || list[0].Phase != "run" || list[0].Completed != 1 {
+		t.Errorf("Unexpected batch traversal: %+v", list[0])
+	}
+	if list[1].Batch || list[1].TargetID != 2 {
+		t.Errorf("Unexpected single traversal: %+v", list[1])
+	}
+	if resume != nil {
+		t.Errorf("Expected no resume without auto_resume, got %+v", resume)
+	}
+
+	for id, want := range map[int64]string{doneID: "done", cycleID: "interrupted", runID: "interrupted"} {
+		var status string
+		localDB.QueryRow(`SELECT status FROM traversals WHERE id = ?`, id).Scan(&status)
+		if status != want {
+			t.Errorf("Traversal %d: expected %s, got %s", id, want, status)
+		}
+	}
+	var n int
+	localDB.QueryRow(`SELECT COUNT(*) FROM cycle_state`).Scan(&n)
+	if n != 0 {
+		t.Errorf("Expected cycle_state to be cleared, got %d rows", n)
+	}
+
+	if got := formatInterrupted(list[0]); got != "#2 #1 하위 Cycle 순회 (run 단계 1/4, 작업 #2)" {
+		t.Errorf("Unexpected format: %s", got)
+	}
+	if got := resumeCommand(list[0]); got != "task cycle 1" {
+		t.Errorf("Unexpected resume command: %s", got)
+	}
+	if got := resumeCommand(list[1]); got != "task run 2" {
+		t.Errorf("Unexpected resume command: %s", got)
+	}
+
+	// auto_resume picks the latest batch traversal
+	localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES (?, ?, ?)", "auto_resume", "true", db.TimeNow())
+	planID, _ := insertTraversal(localDB, "plan", nil, "")
+	saveCycleState(projectPath, CycleState{Type: "plan", Phase: "plan", StartedAt: time.Now(), TraversalID: planID})
+	insertTraversal(localDB, "run", &taskID, "")
+
+	list, resume, err = markInterrupted(projectPath)
+	if err != nil {
+		t.Fatalf("markInterrupted failed: %v", err)
+	}
+	if len(list) != 2 || resume == nil || resume.ID != planID || resume.Type != "plan" || resume.TargetID != 0 {
+		t.Errorf("Expected to resume plan traversal %d, got %+v", planID, resume)
+	}
+
+	// Nothing left running: a second pass finds nothing
+	if list, resume, _ := markInterrupted(projectPath); len(list) != 0 || resume != nil {
+		t.Errorf("Expected nothing on second pass, got %+v %+v", list, resume)
+	}
+}
@@ -0,0 +1,168 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+// rollupChange is a parent whose status was derived from its children.
+type rollupChange struct {
+	ID    int
+	Title string
+	From  string
+	To    string
+}
+
+// rollupNode is a task as seen by the roll-up (one row of tasks).
+type rollupNode struct {
+	id       int
+	parentID *int
+	title    string
+	status   string
+	isLeaf   bool
+	depth    int
+	replans  int
+}
+
+// rollUp derives the status of every parent from its children, deepest first:
+//   - done when all children are done
+//   - failed when no child is pending any more and a child failed permanently
+//     (a failed leaf that replan_auto will not pick up again)
+//   - split otherwise (work left, or a failed child waiting for its re-plan)
+//
+// A parent that becomes done gets a {id}.report.md stitched from its children's reports.
+// Returns the parents whose status changed.
+func rollUp(ctx context.Context, localDB *db.DB, projectPath string) []rollupChange {
+	rows, err := localDB.Query(`SELECT id, parent_id, title, status, is_leaf, depth, COALESCE(replans, 0) FROM tasks`)
+	if err != nil {
+		log.Printf("[Task] roll-up 조회 실패: %v", err)
+		return nil
+	}
+	nodes := make(map[int]*rollupNode)
+	children := make(map[int][]*rollupNode)
+	for rows.Next() {
+		n := &rollupNode{}
+		if err := rows.Scan(&n.id, &n.parentID, &n.title, &n.status, &n.isLeaf, &n.depth, &n.replans); err != nil {
+			continue
+		}
+		nodes[n.id] = n
+		if n.parentID != nil {
+			children[*n.parentID] = append(children[*n.parentID], n)
+		}
+	}
+	rows.Close()
+
+	var parents []*rollupNode
+	for id, kids := range children {
+		if n, ok := nodes[id]; ok && len(kids) > 0 {
+			parents = append(parents, n)
+		}
+	}
+	// Deepest first so a rolled-up child is seen by its own parent
+	sort.Slice(parents, func(i, j int) bool {
+		if parents[i].depth != parents[j].depth {
+			return parents[i].depth > parents[j].depth
+		}
+		return parents[i].id < parents[j].id
+	})
+
+	replanCfg := getReplanConfig(localDB)
+	permanent := func(c *rollupNode) bool {
+		return !c.isLeaf || !replanCfg.Auto || c.replans >= replanCfg.Max
+	}
+
+	var changes []rollupChange
+	for _, p := range parents {
+		kids := children[p.id]
+		sort.Slice(kids, func(i, j int) bool { return kids[i].id < kids[j].id })
+
+		status := "done"
+		for _, c := range kids {
+			switch c.status {
+			case "done":
+			case "failed":
+				if status == "done" {
+					status = "failed"
+				}
+				if !permanent(c) {
+					status = "split"
+				}
+			default:
+				status = "split"
+			}
+			if status == "split" {
+				break
+			}
+		}
+		if status == p.status {
+			continue
+		}
+
+		if _, err := localDB.Exec(`UPDATE tasks SET status = ?, is_leaf = 0, updated_at = ? WHERE id = ?`, status, db.TimeNow(), p.id); err != nil {
+			log.Printf("[Task] roll-up 상태 저장 실패 (#%d): %v", p.id, err)
+			continue
+		}
+		recordEvent(localDB, p.id, p.status, status, ActorRollup, 0)
+		if err := updateTaskFileStatus(projectPath, p.id, status); err != nil {
+			log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", p.id, err)
+		}
+		if status == "done" {
+			if err := WriteReportContent(projectPath, p.id, buildRollupReport(projectPath, p, kids)); err != nil {
+				log.Printf("[Task] roll-up 리포트 저장 실패 (#%d): %v", p.id, err)
+			}
+		}
+		gitCommitTask(ctx, projectPath, p.id, "rolled up "+status)
+
+		log.Printf("[Task] Roll-up: #%d %s → %s", p.id, p.status, status)
+		changes = append(changes, rollupChange{ID: p.id, Title: p.title, From: p.status, To: status})
+		p.status = status
+	}
+	return changes
+}
+
+// buildRollupReport stitches the children's reports into the parent's report.
+func buildRollupReport(projectPath string, parent *rollupNode, kids []*rollupNode) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# #%d %s\n\n", parent.id, parent.title))
+	sb.WriteString(fmt.Sprintf("하위 작업 %d개 완료 (roll-up)\n", len(kids)))
+	for _, c := range kids {
+		sb.WriteString(fmt.Sprintf("\n## #%d %s\n\n", c.id, c.title))
+		report, err := ReadReportContent(projectPath, c.id)
+		if err != nil || strings.TrimSpace(report) == "" {
+			sb.WriteString("(리포트 없음)\n")
+			continue
+		}
+		sb.WriteString(strings.TrimSpace(report) + "\n")
+	}
+	return sb.String()
+}
+
+// rollUpProject opens the project DB and runs rollUp (for callers without a DB handle).
+func rollUpProject(ctx context.Context, projectPath string) []rollupChange {
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		log.Printf("[Task] roll-up DB 열기 실패: %v", err)
+		return nil
+	}
+	defer localDB.Close()
+	return rollUp(ctx, localDB, projectPath)
+}
+
+// formatRollup renders one roll-up change for result messages.
+func formatRollup(c rollupChange) string {
+	return fmt.Sprintf("🧩 상위 작업 %s #%d %s: %s → %s", statusToIcon(c.To), c.ID, c.Title, c.From, c.To)
+}
+
+// formatRollups renders roll-up changes for result messages ("" if none).
+func formatRollups(changes []rollupChange) string {
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, formatRollup(c))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,82 @@
+package task
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestRollUp(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// #1 epic → #2, #3 → #4
+	one, three := 1, 3
+	Add(projectPath, "Epic", nil, "spec")
+	Add(projectPath, "Login API", &one, "spec")
+	Add(projectPath, "Login page", &one, "spec")
+	Add(projectPath, "Form", &three, "spec")
+	WriteReportContent(projectPath, 2, "added POST /login")
+	WriteReportContent(projectPath, 4, "added the form")
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+	status := func(id int) string {
+		var s string
+		localDB.QueryRow("SELECT status FROM tasks WHERE id = ?", id).Scan(&s)
+		return s
+	}
+
+	Set(projectPath, "2", "status", "done", ActorCLI)
+	if status(1) != "split" {
+		t.Errorf("Expected #1 split while #3 is pending, got %s", status(1))
+	}
+
+	result := Set(projectPath, "4", "status", "done", ActorCLI)
+	if status(3) != "done" || status(1) != "done" {
+		t.Fatalf("Expected #3 and #1 done, got %s %s", status(3), status(1))
+	}
+	if !strings.Contains(result.Message, "🧩 상위 작업") {
+		t.Errorf("Expected roll-up in result: %s", result.Message)
+	}
+	if tc, _ := ReadTaskContent(projectPath, 1); tc.Frontmatter.Status != "done" {
+		t.Errorf("Expected done in #1 file, got %s", tc.Frontmatter.Status)
+	}
+
+	// The epic report stitches its children (#3 itself rolled up from #4)
+	report, _ := ReadReportContent(projectPath, 1)
+	for _, want := range []string{"## #2 Login API", "added POST /login", "## #3 Login page", "added the form"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in roll-up report:\n%s", want, report)
+		}
+	}
+
+	// A parent's status follows its children
+	if result := Set(projectPath, "1", "status", "todo", ActorCLI); result.Success {
+		t.Error("Expected status change of a parent to be refused")
+	}
+
+	// Reopening a child reopens its ancestors
+	Set(projectPath, "4", "status", "todo", ActorCLI)
+	if status(3) != "split" || status(1) != "split" {
+		t.Errorf("Expected #3 and #1 split again, got %s %s", status(3), status(1))
+	}
+
+	// A permanent failure fails the ancestors once nothing is pending
+	Set(projectPath, "4", "status", "failed", ActorCLI)
+	if status(3) != "failed" || status(1) != "failed" {
+		t.Errorf("Expected #3 and #1 failed, got %s %s", status(3), status(1))
+	}
+
+	// With replan_auto the failure is not final yet
+	localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES ('replan_auto', 'true', ?)", db.TimeNow())
+	rollUp(context.Background(), localDB, projectPath)
+	if status(3) != "split" || status(1) != "split" {
+		t.Errorf("Expected #3 and #1 split while #4 can be re-planned, got %s %s", status(3), status(1))
+	}
+}
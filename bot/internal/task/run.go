@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,13 +35,19 @@ func getParallel(localDB *db.DB) int {
 }
 
 // Run runs a task (2회차 순회: planned → done)
-// If id is empty, runs next planned task
-func Run(projectPath, id string) types.Result {
-	return RunWithContext(context.Background(), projectPath, id)
+// If id is empty, runs next planned task. actor is recorded on the status changes.
+func Run(projectPath, id, actor string) types.Result {
+	return RunWithContext(WithActor(context.Background(), actor), projectPath, id)
 }
 
 // RunWithContext runs a task with context for cancellation support
 func RunWithContext(ctx context.Context, projectPath, id string) types.Result {
+	return runTask(ctx, projectPath, id, false)
+}
+
+// runTask runs a task. With isolate, Claude works in a dedicated git worktree on a
+// per-task branch that is merged back into the main tree after a successful run.
+func runTask(ctx context.Context, projectPath, id string, isolate bool) (ret types.Result) {
 	localDB, err := db.OpenLocal(projectPath)
 	if err != nil {
 		return types.Result{
@@ -53,16 +60,20 @@ func RunWithContext(ctx context.Context, projectPath, id string) types.Result {
 	var t Task
 
 	if id == "" {
-		// Get next planned leaf task
+		// Get next planned leaf task whose dependencies are all done
 		err = localDB.QueryRow(`
 			SELECT id, title, status FROM tasks
 			WHERE status = 'planned' AND is_leaf = 1
+			AND NOT EXISTS (
+				SELECT 1 FROM task_deps d JOIN tasks dt ON dt.id = d.depends_on
+				WHERE d.task_id = tasks.id AND dt.status != 'done'
+			)
 			ORDER BY priority DESC, depth DESC, id ASC LIMIT 1
 		`).Scan(&t.ID, &t.Title, &t.Status)
 		if err == sql.ErrNoRows {
 			return types.Result{
 				Success: true,
-				Message: "실행할 작업이 없습니다. (planned 상태 leaf 작업 없음)\n[작업 목록:task list]",
+				Message: "실행할 작업이 없습니다. (선행 작업이 완료된 planned 상태 leaf 작업 없음)\n[작업 목록:task list]",
 			}
 		}
 	} else {
@@ -87,6 +98,12 @@ func RunWithContext(ctx context.Context, projectPath, id string) types.Result {
 	// Load content from files
 	LoadContent(projectPath, &t)
 
+	if t.Status == "review" {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("작업 #%d의 Plan이 승인 대기 중입니다.\n%s", t.ID, reviewButtons(t.ID)),
+		}
+	}
 	if t.Status != "planned" {
 		return types.Result{
 			Success: false,
@@ -101,20 +118,49 @@ func RunWithContext(ctx context.Context, projectPath, id string) types.Result {
 		}
 	}
 
-	// Insert traversal record
-	travID, travErr := insertTraversal(localDB, "run", &t.ID, "")
-	if travErr != nil {
-		log.Printf("[Task] traversal INSERT 실패: %v", travErr)
+	// Dependencies must be done before execution
+	unmet, err := unmetDependencies(localDB, t.ID)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("선행 작업 조회 실패: %v", err),
+		}
+	}
+	if len(unmet) > 0 {
+		return types.Result{
+			Success:   false,
+			Message:   fmt.Sprintf("작업 #%d의 선행 작업이 완료되지 않았습니다: %s", t.ID, formatDependsOn(unmet)),
+			ErrorType: "blocked",
+		}
 	}
 
-	// Build context map (lightweight task tree summary)
-	contextMap, err := BuildContextMap(localDB)
+	// Own context per task: CancelTask stops this task without stopping the traversal
+	ctx, done := withTaskCancel(ctx, projectPath, t.ID)
+	defer done()
+
+	// Inside a batch traversal the task is one of its items; alone it gets its own traversal
+	ownTrav := traversalFromContext(ctx) == 0
+	var travID int64
+	if ownTrav {
+		var travErr error
+		travID, travErr = insertTraversal(localDB, "run", &t.ID, "")
+		if travErr != nil {
+			log.Printf("[Task] traversal INSERT 실패: %v", travErr)
+			ownTrav = false
+		}
+		ctx = withTraversal(ctx, travID)
+	}
+	item := startItem(ctx, &t, "run")
+	defer func() { item.finish(localDB, ret) }()
+
+	// Build context (graph node: direct upstream results, otherwise task tree summary)
+	contextMap, upstream, err := buildPromptContext(localDB, projectPath, t.ID)
 	if err != nil {
 		ret := types.Result{
 			Success: false,
-			Message: fmt.Sprintf("Context Map 생성 실패: %v", err),
+			Message: fmt.Sprintf("컨텍스트 생성 실패: %v", err),
 		}
-		if travErr == nil {
+		if ownTrav {
 			finishTraversal(localDB, travID, "failed", 1, 0, 1)
 		}
 		return ret
@@ -128,37 +174,84 @@ func RunWithContext(ctx context.Context, projectPath, id string) types.Result {
 			Success: false,
 			Message: fmt.Sprintf("report 디렉토리 생성 실패: %v", err),
 		}
-		if travErr == nil {
+		if ownTrav {
 			finishTraversal(localDB, travID, "failed", 1, 0, 1)
 		}
 		return ret
 	}
 
+	// Worktree isolation: parallel workers must not edit the same working tree
+	workDir := projectPath
+	keepBranch := false
+	if isolate {
+		wt, err := createWorktree(projectPath, t.ID)
+		if err != nil {
+			if ownTrav {
+				finishTraversal(localDB, travID, "failed", 1, 0, 1)
+			}
+			return types.Result{
+				Success:   false,
+				Message:   fmt.Sprintf("worktree 생성 실패: %v", err),
+				ErrorType: "exec_error",
+			}
+		}
+		workDir = wt
+		defer func() { removeWorktree(projectPath, t.ID, keepBranch) }()
+	}
+
+	// Snapshot the tree so the task's own code change can be saved as {id}.diff.
+	// Parallel workers sharing the tree would see each other's edits: no diff then.
+	var before string
+	switch {
+	case sharedTreeFromContext(ctx):
+		log.Printf("[Task] 병렬 실행 (worktree 격리 없음), diff 생략 (#%d)", t.ID)
+		removeTaskDiff(projectPath, t.ID)
+	case isGitRepo(workDir):
+		if before, err = snapshotTree(workDir); err != nil {
+			log.Printf("[Task] 실행 전 스냅샷 실패 (#%d): %v", t.ID, err)
+		}
+	}
+
 	// Build prompt with report path
-	prompt := BuildExecutePrompt(&t, contextMap, reportPath)
+	prompt := BuildExecutePromptWithUpstream(&t, contextMap, upstream, reportPath)
 
 	// Run Claude Code
 	opts := claude.Options{
 		UserPrompt: prompt,
-		WorkDir:    projectPath,
+		WorkDir:    workDir,
 		ReportPath: reportPath,
+		Origin:     claude.Origin{Project: projectPath, TaskID: t.ID},
+	}
+	resolveRunOptions(localDB, projectPath, t.ID).apply(&opts)
+
+	// Record the attempt (each run keeps its own outcome and output)
+	opts.RunID = claude.NewRunID()
+	attemptID, _, attemptErr := startAttempt(localDB, t.ID, "run", opts.RunID)
+	if attemptErr != nil {
+		log.Printf("[Task] attempt INSERT 실패 (#%d): %v", t.ID, attemptErr)
 	}
 
 	result, err := claude.RunContext(ctx, opts)
 	if err != nil {
 		ret := types.Result{
-			Success: false,
-			Message: fmt.Sprintf("Claude 실행 오류: %v", err),
+			Success:   false,
+			Message:   fmt.Sprintf("Claude 실행 오류: %v", err),
+			ErrorType: classifyRunError(ctx, err),
 		}
-		if ctx.Err() != nil {
-			ret.ErrorType = "cancelled"
+		if attemptErr == nil {
+			status := "failed"
+			if ret.ErrorType == "cancelled" {
+				status = "cancelled"
+			}
+			finishAttempt(localDB, projectPath, attemptID, status, nil, ret.ErrorType, err.Error(), "")
 		}
-		if travErr == nil {
+		if ownTrav {
 			finishTraversal(localDB, travID, "failed", 1, 0, 1)
 		}
 		return ret
 	}
 
+	item.setExitCode(result.ExitCode)
 	now := db.TimeNow()
 
 	if result.ExitCode != 0 {
@@ -167,43 +260,91 @@ func RunWithContext(ctx context.Context, projectPath, id string) types.Result {
 		if authError {
 			log.Printf("[Task] Run 인증 오류 감지 (task #%d)", t.ID)
 		}
-
-		// Save error to file and mark as failed
-		if err := WriteErrorContent(projectPath, t.ID, result.Output); err != nil {
-			log.Printf("[Task] Run 에러 파일 생성 실패 (task #%d): %v", t.ID, err)
-		}
-		if _, err := localDB.Exec(`UPDATE tasks SET status = 'failed', updated_at = ? WHERE id = ?`, now, t.ID); err != nil {
-			log.Printf("[Task] Run 상태 저장 실패 (task #%d): %v", t.ID, err)
-		}
-		if err := updateTaskFileStatus(projectPath, t.ID, "failed"); err != nil {
-			log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", t.ID, err)
+		errorType := "exit_error"
+		if authError {
+			errorType = "auth_error"
 		}
-		gitCommitTask(projectPath, t.ID, "failed")
-		// Clean up report file
-		if err := os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
-			log.Printf("[Task] Run report 파일 삭제 실패 (task #%d): %v", t.ID, err)
+		if attemptErr == nil {
+			exitCode := result.ExitCode
+			finishAttempt(localDB, projectPath, attemptID, "failed", &exitCode, errorType, truncateLine(result.Output, 200), result.Output)
 		}
-		if travErr == nil {
+
+		// Save error to file and mark as failed
+		failRun(ctx, localDB, projectPath, t.ID, result.Output, "failed", reportPath)
+		if ownTrav {
 			finishTraversal(localDB, travID, "failed", 1, 0, 1)
 		}
 
-		ret := types.Result{
-			Success: false,
-			Message: fmt.Sprintf("작업 실행 실패: %s", result.Output),
-		}
-		if authError {
-			ret.ErrorType = "auth_error"
+		return types.Result{
+			Success:   false,
+			Message:   fmt.Sprintf("작업 실행 실패: %s", result.Output),
+			ErrorType: errorType,
 		}
-		return ret
+	}
+
+	if attemptErr == nil {
+		exitCode := 0
+		finishAttempt(localDB, projectPath, attemptID, "done", &exitCode, "", "", result.Output)
 	}
 
 	// Save report to file (sole source of truth) and update DB status
 	if err := WriteReportContent(projectPath, t.ID, result.Output); err != nil {
 		log.Printf("[Task] report 파일 생성 실패 (#%d): %v", t.ID, err)
 	}
+
+	// Verification gate: the project must still pass its build/test/lint commands
+	var failed *verifyStep
+	var verifyErrorType string
+	if cfg := getVerifyConfig(localDB); len(cfg.Commands) > 0 {
+		failed, verifyErrorType = verifyTask(ctx, localDB, projectPath, workDir, &t, cfg, attemptID, contextMap, upstream, reportPath)
+	}
+
+	// Record what the run changed (including any fix-up run)
+	saveTaskDiff(projectPath, workDir, t.ID, before)
+
+	if failed != nil {
+		msg := fmt.Sprintf("검증 실패: %s `%s` (%s)", failed.Stage, failed.Command, failed.status())
+		failRun(ctx, localDB, projectPath, t.ID, fmt.Sprintf("%s\n\n%s", msg, tailBytes(failed.Output, 2000)), "verify failed", reportPath)
+		if ownTrav {
+			finishTraversal(localDB, travID, "failed", 1, 0, 1)
+		}
+		return types.Result{
+			Success:   false,
+			Message:   fmt.Sprintf("작업 #%d %s\n%s", t.ID, msg, taskButtons(projectPath, t.ID)),
+			ErrorType: verifyErrorType,
+		}
+	}
+	now = db.TimeNow()
+
+	// Bring the task branch back into the main tree; a conflict fails the task
+	if isolate {
+		changed, err := commitWorktree(workDir, t.ID, t.Title)
+		var conflicts []string
+		if err == nil && changed {
+			conflicts, err = mergeWorktree(projectPath, t.ID, t.Title)
+		}
+		if err != nil {
+			keepBranch = changed
+			failRun(ctx, localDB, projectPath, t.ID, formatMergeConflict(t.ID, conflicts, err), "merge failed", reportPath)
+			if ownTrav {
+				finishTraversal(localDB, travID, "failed", 1, 0, 1)
+			}
+			msg := fmt.Sprintf("병합 실패: %v", err)
+			if len(conflicts) > 0 {
+				msg = fmt.Sprintf("병합 충돌: %s", strings.Join(conflicts, ", "))
+			}
+			return types.Result{
+				Success:   false,
+				Message:   fmt.Sprintf("작업 #%d %s\n%s", t.ID, msg, taskButtons(projectPath, t.ID)),
+				ErrorType: "merge_conflict",
+			}
+		}
+		now = db.TimeNow()
+	}
+
 	_, err = localDB.Exec(`UPDATE tasks SET status = 'done', updated_at = ? WHERE id = ?`, now, t.ID)
 	if err != nil {
-		if travErr == nil {
+		if ownTrav {
 			finishTraversal(localDB, travID, "failed", 1, 0, 1)
 		}
 		return types.Result{
@@ -211,10 +352,11 @@ func RunWithContext(ctx context.Context, projectPath, id string) types.Result {
 			Message: fmt.Sprintf("상태 저장 실패: %v", err),
 		}
 	}
+	recordEvent(localDB, t.ID, t.Status, "done", actorFromContext(ctx), traversalFromContext(ctx))
 	if err := updateTaskFileStatus(projectPath, t.ID, "done"); err != nil {
 		log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", t.ID, err)
 	}
-	gitCommitTask(projectPath, t.ID, "done")
+	gitCommitTask(ctx, projectPath, t.ID, "done")
 
 	// Clean up report file after DB save
 	if err := os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
@@ -222,19 +364,68 @@ func RunWithContext(ctx context.Context, projectPath, id string) types.Result {
 	}
 
 	// Update traversal record
-	if travErr == nil {
+	if ownTrav {
 		finishTraversal(localDB, travID, "done", 1, 1, 0)
 	}
 
+	msg := fmt.Sprintf("✅ 작업 #%d 완료: %s\n%s", t.ID, t.Title, taskButtons(projectPath, t.ID))
+	// Batch traversals roll up parents themselves (see runAllSequential/runAllParallel)
+	if !batchFromContext(ctx) {
+		if rollups := formatRollups(rollUp(ctx, localDB, projectPath)); rollups != "" {
+			msg += "\n" + rollups
+		}
+	}
+
 	return types.Result{
 		Success: true,
-		Message: fmt.Sprintf("✅ 작업 #%d 완료: %s\n[조회:task get %d]", t.ID, t.Title, t.ID),
+		Message: msg,
 		Data:    &t,
 	}
 }
 
+// failRun saves the error content and marks a task failed (DB + file), then commits
+// the task files and removes the temporary report file.
+func failRun(ctx context.Context, localDB *db.DB, projectPath string, taskID int, errContent, action, reportPath string) {
+	if err := WriteErrorContent(projectPath, taskID, errContent); err != nil {
+		log.Printf("[Task] Run 에러 파일 생성 실패 (task #%d): %v", taskID, err)
+	}
+	from := taskStatus(localDB, taskID)
+	if _, err := localDB.Exec(`UPDATE tasks SET status = 'failed', updated_at = ? WHERE id = ?`, db.TimeNow(), taskID); err != nil {
+		log.Printf("[Task] Run 상태 저장 실패 (task #%d): %v", taskID, err)
+	} else {
+		recordEvent(localDB, taskID, from, "failed", actorFromContext(ctx), traversalFromContext(ctx))
+	}
+	if err := updateTaskFileStatus(projectPath, taskID, "failed"); err != nil {
+		log.Printf("[Task] task 파일 status 갱신 실패 (#%d): %v", taskID, err)
+	}
+	gitCommitTask(ctx, projectPath, taskID, action)
+	// In batch mode the failure may still be retried; the traversal rolls up at the end
+	if !batchFromContext(ctx) {
+		rollUp(ctx, localDB, projectPath)
+	}
+	// Clean up report file
+	if err := os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
+		log.Printf("[Task] Run report 파일 삭제 실패 (task #%d): %v", taskID, err)
+	}
+}
+
 // RunAll runs all planned tasks (2회차 순회 전체 실행)
 func RunAll(projectPath string) types.Result {
+	return runAll(projectPath, 0, "")
+}
+
+// RunSubtree runs the planned leaf tasks in the subtree of a root task.
+func RunSubtree(projectPath, id string) types.Result {
+	rootID, errResult := resolveScope(projectPath, id)
+	if errResult != nil {
+		return *errResult
+	}
+	return runAll(projectPath, rootID, "")
+}
+
+// runAll runs the run traversal over the whole project (rootID 0) or a subtree.
+// trigger is recorded on the traversal ("resume:<id>" when restarted after an interruption).
+func runAll(projectPath string, rootID int, trigger string) types.Result {
 	// Check if already running for this project
 	if IsCycleRunning(projectPath) {
 		return types.Result{
@@ -243,10 +434,20 @@ func RunAll(projectPath string) types.Result {
 		}
 	}
 
-	ResetCancel()
-	SetBatchMode(true)
-	defer SetBatchMode(false)
-	ctx, cancel := context.WithCancel(context.Background())
+	// Batch mode: per-task commits are replaced by one commit at the end
+	ctx, cancel := context.WithCancel(withBatch(context.Background()))
+
+	// Insert traversal record
+	localDB, travErr := db.OpenLocal(projectPath)
+	var travID int64
+	if travErr == nil {
+		travID, travErr = insertTraversal(localDB, "run", scopeTarget(rootID), trigger)
+		if travErr != nil {
+			log.Printf("[Task] traversal INSERT 실패: %v", travErr)
+		}
+		localDB.Close()
+	}
+	ctx = withTraversal(ctx, travID)
 
 	startTime := time.Now()
 	SetCycleState(projectPath, CycleState{
@@ -254,7 +455,9 @@ func RunAll(projectPath string) types.Result {
 		Type:        "run",
 		StartedAt:   startTime,
 		ProjectPath: projectPath,
+		RootID:      rootID,
 		Phase:       "run",
+		TraversalID: travID,
 	})
 	SetCycleCancel(projectPath, cancel)
 	defer func() {
@@ -262,19 +465,8 @@ func RunAll(projectPath string) types.Result {
 		ClearCycleState(projectPath)
 	}()
 
-	// Insert traversal record
-	localDB, travErr := db.OpenLocal(projectPath)
-	var travID int64
-	if travErr == nil {
-		travID, travErr = insertTraversal(localDB, "run", nil, "")
-		if travErr != nil {
-			log.Printf("[Task] traversal INSERT 실패: %v", travErr)
-		}
-		localDB.Close()
-	}
-
-	result := runAllInternal(ctx, projectPath)
-	gitCommitBatch(projectPath, fmt.Sprintf("runAll: %s", summarizeResult(result.Message)))
+	result := runAllInternal(ctx, projectPath, rootID)
+	gitCommitBatch(projectPath, fmt.Sprintf("runAll%s: %s", scopeCommitSuffix(rootID), summarizeResult(result.Message)))
 
 	// Update traversal record
 	if travErr == nil {
@@ -284,17 +476,17 @@ func RunAll(projectPath string) types.Result {
 			if !result.Success {
 				status = "failed"
 			}
-			if IsCancelled() {
+			if ctx.Err() != nil {
 				status = "cancelled"
 			}
-			total, success, failed := countFromMessage(result.Message)
+			total, success, failed := countItems(localDB, travID)
 			finishTraversal(localDB, travID, status, total, success, failed)
 			localDB.Close()
 		}
 	}
 
 	if globalNotifier != nil {
-		notification := fmt.Sprintf("🔄 Run 순회 완료\n소요: %s\n%s", formatDuration(time.Since(startTime)), result.Message)
+		notification := fmt.Sprintf("🔄 %sRun 순회 완료\n소요: %s\n%s", scopeLabel(rootID), formatDuration(time.Since(startTime)), result.Message)
 		globalNotifier(nil, notification)
 	}
 
@@ -303,17 +495,19 @@ func RunAll(projectPath string) types.Result {
 
 // runResult holds the result of a single task run for channel communication
 type runResult struct {
-	TaskID  int
-	Title   string
-	Success bool
-	Message string
-	IsAuth  bool
+	TaskID    int
+	Title     string
+	Success   bool
+	Message   string
+	IsAuth    bool
+	ErrorType string
 }
 
 // runAllInternal is the internal implementation of RunAll without CycleState management.
 // Used by Cycle() to avoid overwriting the cycle type.
 // Supports parallel execution based on project's parallel config.
-func runAllInternal(ctx context.Context, projectPath string) types.Result {
+// rootID limits the traversal to a subtree (0 = whole project).
+func runAllInternal(ctx context.Context, projectPath string, rootID int) types.Result {
 	localDB, err := db.OpenLocal(projectPath)
 	if err != nil {
 		return types.Result{
@@ -322,15 +516,18 @@ func runAllInternal(ctx context.Context, projectPath string) types.Result {
 		}
 	}
 
-	// Read parallel config
+	// Read parallel, retry and worktree config
 	parallel := getParallel(localDB)
+	retries := newRetryQueue(getRetryPolicy(localDB))
+	isolate := getWorktreeMode(localDB) && isGitRepo(projectPath)
 
 	// Get all planned leaf tasks (priority first, then deepest)
+	scope, scopeArgs := scopeFilter(rootID)
 	rows, err := localDB.Query(`
 		SELECT id, title FROM tasks
-		WHERE status = 'planned' AND is_leaf = 1
+		WHERE status = 'planned' AND is_leaf = 1`+scope+`
 		ORDER BY priority DESC, depth DESC, id ASC
-	`)
+	`, scopeArgs...)
 	if err != nil {
 		localDB.Close()
 		return types.Result{
@@ -361,186 +558,317 @@ func runAllInternal(ctx context.Context, projectPath string) types.Result {
 		}
 	}
 	rows.Close()
-	localDB.Close()
 
 	if len(tasks) == 0 {
+		localDB.Close()
 		return types.Result{
 			Success: true,
 			Message: "실행할 작업이 없습니다. (planned 상태 작업 없음)\n[작업 목록:task list]",
 		}
 	}
 
+	// Load dependency graph: tasks are dispatched only when all dependencies are done
+	depMap, err := loadDepMap(localDB)
+	if err != nil {
+		localDB.Close()
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("의존성 조회 실패: %v", err),
+		}
+	}
+	statusMap, err := loadStatusMap(localDB)
+	localDB.Close()
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("상태 조회 실패: %v", err),
+		}
+	}
+
 	UpdatePhase(projectPath, "run", len(tasks))
 	log.Printf("[Task] RunAll: %d tasks, parallel=%d", len(tasks), parallel)
 
 	// Sequential execution when parallel=1 (original behavior)
+	var result types.Result
 	if parallel <= 1 {
-		return runAllSequential(ctx, projectPath, tasks)
+		result = runAllSequential(ctx, projectPath, tasks, depMap, statusMap, retries)
+	} else {
+		result = runAllParallel(ctx, projectPath, tasks, parallel, depMap, statusMap, retries, isolate)
+	}
+
+	// Failures are final once the traversal is over: roll them up to the parents
+	if rollups := formatRollups(rollUpProject(ctx, projectPath)); rollups != "" {
+		result.Message += rollups + "\n"
+	}
+	return result
+}
+
+// rollUpDone rolls up parents after a task finished in a batch traversal.
+// Rolled-up parents are written to statusMap so their dependents can be dispatched.
+func rollUpDone(ctx context.Context, projectPath string, statusMap map[int]string) []string {
+	var lines []string
+	for _, c := range rollUpProject(ctx, projectPath) {
+		statusMap[c.ID] = c.To
+		lines = append(lines, formatRollup(c))
+	}
+	return lines
+}
+
+// takeReady removes and returns the first pending task whose dependencies are all done.
+// Pending order (priority DESC, depth DESC, id ASC) is preserved, so the dispatch
+// sequence is a topological order of the dependency graph.
+func takeReady(pending []Task, depMap map[int][]int, statusMap map[int]string) (Task, []Task, bool) {
+	for i, t := range pending {
+		if depsSatisfied(t.ID, depMap, statusMap) {
+			rest := append(append([]Task{}, pending[:i]...), pending[i+1:]...)
+			return t, rest, true
+		}
 	}
+	return Task{}, pending, false
+}
 
-	// Parallel execution
-	return runAllParallel(ctx, projectPath, tasks, parallel)
+// formatRunSummary builds the run summary; blocked count is shown only when non-zero.
+func formatRunSummary(success, failed, blocked int, messages []string) string {
+	summary := fmt.Sprintf("✅ 작업 실행 완료: 성공 %d개, 실패 %d개", success, failed)
+	if blocked > 0 {
+		summary += fmt.Sprintf(", 차단 %d개", blocked)
+	}
+	summary += "\n"
+	for _, msg := range messages {
+		summary += msg + "\n"
+	}
+	return summary
 }
 
-// runAllSequential runs tasks one by one (original behavior)
-func runAllSequential(ctx context.Context, projectPath string, tasks []Task) types.Result {
-	var success, failed int
+// cancelledMessage is the summary line of a task cancelled on its own during a traversal.
+func cancelledMessage(t Task) string {
+	return fmt.Sprintf("🛑 #%d %s: 작업 취소됨", t.ID, t.Title)
+}
+
+// runAllSequential runs tasks one by one in dependency order.
+// Failed tasks are re-queued according to the retry policy.
+func runAllSequential(ctx context.Context, projectPath string, tasks []Task, depMap map[int][]int, statusMap map[int]string, retries *retryQueue) types.Result {
+	var success, failed, blocked int
 	var messages []string
+	pending := tasks
+	stopped := false
 
-	for _, t := range tasks {
-		if IsCancelled() || ctx.Err() != nil {
-			skipped := len(tasks) - success - failed
-			messages = append(messages, fmt.Sprintf("🛑 중단 요청으로 %d개 작업 건너뜀", skipped))
+	for len(pending) > 0 || len(retries.waiting) > 0 {
+		if ctx.Err() != nil {
+			messages = append(messages, fmt.Sprintf("🛑 중단 요청으로 %d개 작업 건너뜀", len(pending)+len(retries.waiting)))
+			stopped = true
 			break
 		}
 
+		pending = append(pending, retries.due(time.Now())...)
+		t, rest, ok := takeReady(pending, depMap, statusMap)
+		if !ok {
+			// Nothing runnable: wait for the next retry, otherwise the rest is blocked
+			at, waiting := retries.next()
+			if !waiting {
+				break
+			}
+			waitUntil(ctx, at)
+			continue
+		}
+		pending = rest
+
 		UpdateCurrentTask(projectPath, t.ID)
 		result := RunWithContext(ctx, projectPath, fmt.Sprintf("%d", t.ID))
-		IncrementCompleted(projectPath)
 		if result.Success {
+			IncrementCompleted(projectPath)
+			retries.succeeded(t.ID)
 			success++
+			statusMap[t.ID] = "done"
 			messages = append(messages, fmt.Sprintf("✅ #%d %s", t.ID, t.Title))
-		} else {
-			failed++
-			messages = append(messages, fmt.Sprintf("❌ #%d %s: %s", t.ID, t.Title, result.Message))
-			if result.ErrorType == "auth_error" {
-				skipped := len(tasks) - success - failed
-				messages = append(messages, fmt.Sprintf("🔐 인증 오류로 순회 중단, %d개 작업 건너뜀", skipped))
-				break
-			}
-			if result.ErrorType == "cancelled" {
-				skipped := len(tasks) - success - failed
-				messages = append(messages, fmt.Sprintf("🛑 중단 요청으로 %d개 작업 건너뜀", skipped))
-				break
-			}
+			messages = append(messages, rollUpDone(ctx, projectPath, statusMap)...)
+			continue
+		}
+		if result.ErrorType == "cancelled" && ctx.Err() == nil {
+			// Only this task was cancelled (task cancel): it stays planned, the traversal goes on
+			IncrementCompleted(projectPath)
+			messages = append(messages, cancelledMessage(t))
+			continue
+		}
+		if d, ok := retries.schedule(ctx, projectPath, t, result.ErrorType); ok {
+			statusMap[t.ID] = "planned"
+			messages = append(messages, retries.retryMessage(t, d, result.Message))
+			continue
+		}
+
+		IncrementCompleted(projectPath)
+		failed++
+		statusMap[t.ID] = "failed"
+		messages = append(messages, fmt.Sprintf("❌ #%d %s: %s", t.ID, t.Title, result.Message))
+		if result.ErrorType == "auth_error" {
+			messages = append(messages, fmt.Sprintf("🔐 인증 오류로 순회 중단, %d개 작업 건너뜀", len(pending)+len(retries.waiting)))
+			stopped = true
+			break
+		}
+		if result.ErrorType == "cancelled" {
+			messages = append(messages, fmt.Sprintf("🛑 중단 요청으로 %d개 작업 건너뜀", len(pending)+len(retries.waiting)))
+			stopped = true
+			break
 		}
 	}
 
-	summary := fmt.Sprintf("✅ 작업 실행 완료: 성공 %d개, 실패 %d개\n", success, failed)
-	for _, msg := range messages {
-		summary += msg + "\n"
+	// Remaining tasks could not run because their dependencies never completed
+	if !stopped {
+		for _, t := range pending {
+			blocked++
+			messages = append(messages, blockedMessage(t, depMap, statusMap))
+		}
 	}
 
 	return types.Result{
 		Success: failed == 0,
-		Message: summary,
+		Message: formatRunSummary(success, failed, blocked, messages),
 	}
 }
 
-// runAllParallel runs tasks concurrently with a worker pool
-func runAllParallel(parentCtx context.Context, projectPath string, tasks []Task, parallel int) types.Result {
+// runAllParallel runs tasks concurrently with a worker pool.
+// A task is dispatched only after all of its dependencies are done.
+// Failed tasks are re-queued according to the retry policy.
+// With isolate, each worker runs in its own git worktree (see runTask).
+func runAllParallel(parentCtx context.Context, projectPath string, tasks []Task, parallel int, depMap map[int][]int, statusMap map[int]string, retries *retryQueue, isolate bool) types.Result {
 	ctx, cancel := context.WithCancel(parentCtx)
 	defer cancel()
+	if !isolate {
+		ctx = withSharedTree(ctx)
+	}
 
 	ResetActiveWorkers()
 	defer ResetActiveWorkers()
 
-	sem := make(chan struct{}, parallel)
 	resultCh := make(chan runResult, len(tasks))
 	var wg sync.WaitGroup
 
-	for _, t := range tasks {
-		// Check cancel/stop before dispatching
-		if IsCancelled() {
-			break
-		}
-		select {
-		case <-ctx.Done():
-			break
-		default:
+	var success, failed, blocked int
+	var messages []string
+	var authDetected bool
+	pending := tasks
+	running := 0
+
+	for {
+		stopping := authDetected || ctx.Err() != nil
+		if !stopping {
+			pending = append(pending, retries.due(time.Now())...)
 		}
-		if ctx.Err() != nil {
+
+		// Dispatch ready tasks up to the parallel limit
+		for running < parallel && !stopping {
+			t, rest, ok := takeReady(pending, depMap, statusMap)
+			if !ok {
+				break
+			}
+			pending = rest
+			running++
+
+			wg.Add(1)
+			go func(t Task) {
+				defer wg.Done()
+
+				UpdateActiveWorkers(projectPath, +1)
+				log.Printf("[Task] Worker started task #%d (%s)", t.ID, t.Title)
+				defer func() {
+					UpdateActiveWorkers(projectPath, -1)
+					log.Printf("[Task] Worker finished task #%d (%s)", t.ID, t.Title)
+				}()
+
+				result := runTask(ctx, projectPath, fmt.Sprintf("%d", t.ID), isolate)
+				rr := runResult{
+					TaskID:    t.ID,
+					Title:     t.Title,
+					Success:   result.Success,
+					Message:   result.Message,
+					IsAuth:    result.ErrorType == "auth_error",
+					ErrorType: result.ErrorType,
+				}
+
+				// Auth error: cancel all other workers
+				if rr.IsAuth {
+					log.Printf("[Task] Auth error detected in task #%d, cancelling remaining workers", t.ID)
+					cancel()
+				}
+
+				resultCh <- rr
+			}(t)
+		}
+
+		// Queued retries are only waited for while the traversal is still going
+		at, waiting := retries.next()
+		waiting = waiting && !stopping
+		if running == 0 && !waiting {
 			break
 		}
 
-		wg.Add(1)
-		go func(t Task) {
-			defer wg.Done()
-
-			// Acquire semaphore
+		// Collect one result (or wake for a due retry), then dispatch newly unblocked tasks
+		var rr runResult
+		if running > 0 && waiting {
+			timer := time.NewTimer(time.Until(at))
 			select {
-			case sem <- struct{}{}:
-				// acquired
-			case <-ctx.Done():
-				resultCh <- runResult{TaskID: t.ID, Title: t.Title, Success: false, Message: "컨텍스트 취소됨"}
-				return
-			}
-			defer func() { <-sem }()
-
-			// Re-check after acquiring semaphore
-			if IsCancelled() || ctx.Err() != nil {
-				resultCh <- runResult{TaskID: t.ID, Title: t.Title, Success: false, Message: "중단됨"}
-				return
-			}
-
-			UpdateActiveWorkers(projectPath, +1)
-			log.Printf("[Task] Worker started task #%d (%s)", t.ID, t.Title)
-			defer func() {
-				UpdateActiveWorkers(projectPath, -1)
-				log.Printf("[Task] Worker finished task #%d (%s)", t.ID, t.Title)
-			}()
-
-			result := RunWithContext(ctx, projectPath, fmt.Sprintf("%d", t.ID))
-			rr := runResult{
-				TaskID:  t.ID,
-				Title:   t.Title,
-				Success: result.Success,
-				Message: result.Message,
-				IsAuth:  result.ErrorType == "auth_error",
-			}
-
-			// Auth error: cancel all other workers
-			if rr.IsAuth {
-				log.Printf("[Task] Auth error detected in task #%d, cancelling remaining workers", t.ID)
-				cancel()
+			case rr = <-resultCh:
+				timer.Stop()
+			case <-timer.C:
+				continue
 			}
-
-			resultCh <- rr
-		}(t)
-	}
-
-	// Wait for all goroutines to finish, then close results channel
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
-
-	// Collect results
-	var success, failed, skipped int
-	var messages []string
-	var authDetected bool
-
-	for rr := range resultCh {
-		if rr.Message == "중단됨" || rr.Message == "컨텍스트 취소됨" {
-			skipped++
+		} else if running > 0 {
+			rr = <-resultCh
+		} else {
+			waitUntil(ctx, at)
 			continue
 		}
-		IncrementCompleted(projectPath)
+		running--
+
+		t := Task{ID: rr.TaskID, Title: rr.Title}
 		if rr.Success {
+			IncrementCompleted(projectPath)
+			retries.succeeded(rr.TaskID)
 			success++
+			statusMap[rr.TaskID] = "done"
 			messages = append(messages, fmt.Sprintf("✅ #%d %s", rr.TaskID, rr.Title))
-		} else {
-			failed++
-			messages = append(messages, fmt.Sprintf("❌ #%d %s: %s", rr.TaskID, rr.Title, rr.Message))
-			if rr.IsAuth {
-				authDetected = true
+			messages = append(messages, rollUpDone(ctx, projectPath, statusMap)...)
+			continue
+		}
+		if rr.ErrorType == "cancelled" && ctx.Err() == nil {
+			// Only this task was cancelled (task cancel): it stays planned, the traversal goes on
+			IncrementCompleted(projectPath)
+			messages = append(messages, cancelledMessage(t))
+			continue
+		}
+		if !rr.IsAuth && ctx.Err() == nil {
+			if d, ok := retries.schedule(ctx, projectPath, t, rr.ErrorType); ok {
+				statusMap[rr.TaskID] = "planned"
+				messages = append(messages, retries.retryMessage(t, d, rr.Message))
+				continue
 			}
 		}
+
+		IncrementCompleted(projectPath)
+		failed++
+		statusMap[rr.TaskID] = "failed"
+		messages = append(messages, fmt.Sprintf("❌ #%d %s: %s", rr.TaskID, rr.Title, rr.Message))
+		if rr.IsAuth {
+			authDetected = true
+		}
 	}
+	wg.Wait()
 
+	skipped := len(pending) + len(retries.waiting)
 	if authDetected {
 		messages = append(messages, fmt.Sprintf("🔐 인증 오류로 순회 중단, %d개 작업 건너뜀", skipped))
-	} else if IsCancelled() && skipped > 0 {
+	} else if ctx.Err() != nil && skipped > 0 {
 		messages = append(messages, fmt.Sprintf("🛑 중단 요청으로 %d개 작업 건너뜀", skipped))
-	}
-
-	summary := fmt.Sprintf("✅ 작업 실행 완료: 성공 %d개, 실패 %d개\n", success, failed)
-	for _, msg := range messages {
-		summary += msg + "\n"
+	} else {
+		// Remaining tasks could not run because their dependencies never completed
+		for _, t := range pending {
+			blocked++
+			messages = append(messages, blockedMessage(t, depMap, statusMap))
+		}
 	}
 
 	return types.Result{
 		Success: failed == 0,
-		Message: summary,
+		Message: formatRunSummary(success, failed, blocked, messages),
 	}
 }
@@ -0,0 +1,76 @@
+package task
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+)
+
+// subtreeQuery selects a task and all of its descendants by walking the parent chain.
+const subtreeQuery = `WITH RECURSIVE subtree(id) AS (
+	SELECT id FROM tasks WHERE id = ?
+	UNION ALL
+	SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+) SELECT id FROM subtree`
+
+// scopeFilter returns the condition limiting a tasks query to the subtree of rootID
+// (root included), to be appended to its WHERE clause.
+// rootID 0 is the whole project: empty condition and no args.
+func scopeFilter(rootID int) (string, []interface{}) {
+	if rootID == 0 {
+		return "", nil
+	}
+	return " AND id IN (" + subtreeQuery + ")", []interface{}{rootID}
+}
+
+// scopeLabel returns the label of a traversal scope for messages ("" for the whole project).
+func scopeLabel(rootID int) string {
+	if rootID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("#%d 하위 ", rootID)
+}
+
+// resolveScope parses the root task ID of a subtree-scoped traversal and checks that it exists.
+func resolveScope(projectPath, id string) (int, *types.Result) {
+	rootID, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil || rootID <= 0 {
+		return 0, &types.Result{Success: false, Message: fmt.Sprintf("잘못된 작업 ID: %s", id)}
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return 0, &types.Result{Success: false, Message: fmt.Sprintf("DB 열기 실패: %v", err)}
+	}
+	defer localDB.Close()
+
+	var exists int
+	err = localDB.QueryRow("SELECT 1 FROM tasks WHERE id = ?", rootID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return 0, &types.Result{Success: false, Message: fmt.Sprintf("작업을 찾을 수 없습니다: #%d", rootID)}
+	}
+	if err != nil {
+		return 0, &types.Result{Success: false, Message: fmt.Sprintf("조회 실패: %v", err)}
+	}
+	return rootID, nil
+}
+
+// scopeTarget returns the traversal target of a scope (nil for the whole project).
+func scopeTarget(rootID int) *int {
+	if rootID == 0 {
+		return nil
+	}
+	return &rootID
+}
+
+// scopeCommitSuffix returns the batch commit suffix of a scope (" #12", "" for the whole project).
+func scopeCommitSuffix(rootID int) string {
+	if rootID == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" #%d", rootID)
+}
@@ -0,0 +1,99 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+// setupScopeTree creates #1 epic → #2, #3 → #4 and an unrelated root #5.
+func setupScopeTree(t *testing.T, projectPath string) *db.DB {
+	t.Helper()
+	Add(projectPath, "Epic", nil, "spec")
+	epic, child := 1, 3
+	Add(projectPath, "Child A", &epic, "spec")
+	Add(projectPath, "Child B", &epic, "spec")
+	Add(projectPath, "Grandchild", &child, "spec")
+	Add(projectPath, "Other epic", nil, "spec")
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	return localDB
+}
+
+func TestScopeFilter(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	localDB := setupScopeTree(t, projectPath)
+	defer localDB.Close()
+
+	ids := func(rootID int) []int {
+		scope, args := scopeFilter(rootID)
+		rows, err := localDB.Query("SELECT id FROM tasks WHERE 1 = 1"+scope+" ORDER BY id", args...)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		defer rows.Close()
+		var got []int
+		for rows.Next() {
+			var id int
+			rows.Scan(&id)
+			got = append(got, id)
+		}
+		return got
+	}
+
+	if got := ids(1); !sameDeps(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected subtree of #1 to be [1 2 3 4], got %v", got)
+	}
+	if got := ids(3); !sameDeps(got, []int{3, 4}) {
+		t.Errorf("Expected subtree of #3 to be [3 4], got %v", got)
+	}
+	if got := ids(0); len(got) != 5 {
+		t.Errorf("Expected whole project without scope, got %v", got)
+	}
+
+	localDB.Exec("UPDATE tasks SET status = 'review' WHERE id IN (4, 5)")
+	if n, _ := formatPendingReviews(localDB, 1); n != 1 {
+		t.Errorf("Expected 1 pending review under #1, got %d", n)
+	}
+	if n, _ := formatPendingReviews(localDB, 0); n != 2 {
+		t.Errorf("Expected 2 pending reviews in project, got %d", n)
+	}
+}
+
+func TestCycleSubtree(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	localDB := setupScopeTree(t, projectPath)
+	localDB.Close()
+	for id, status := range map[string]string{"2": "done", "4": "done", "5": "failed"} {
+		Set(projectPath, id, "status", status, ActorCLI)
+	}
+
+	if result := CycleSubtree(projectPath, "99"); result.Success {
+		t.Error("Expected error for unknown root")
+	}
+	if result := CycleSubtree(projectPath, "abc"); result.Success {
+		t.Error("Expected error for invalid root")
+	}
+
+	// Nothing to plan or run under #1; the failed task outside the subtree is not counted
+	result := CycleSubtree(projectPath, "#1")
+	if !result.Success {
+		t.Fatalf("CycleSubtree failed: %s", result.Message)
+	}
+	if !strings.Contains(result.Message, "#1 하위 Cycle 완료: done 2개, failed 0개") {
+		t.Errorf("Expected scoped summary, got: %s", result.Message)
+	}
+	if IsCycleRunning(projectPath) {
+		t.Error("Expected cycle state to be cleared")
+	}
+
+	if got := Get(projectPath, "1"); !strings.Contains(got.Message, "[하위 순회:task cycle 1]") {
+		t.Errorf("Expected subtree cycle button on parent task: %s", got.Message)
+	}
+}
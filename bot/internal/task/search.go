@@ -0,0 +1,268 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+	"parkjunwoo.com/claribot/pkg/pagination"
+)
+
+// Kinds of task file indexed in task_search
+const (
+	searchSpec   = "spec"
+	searchPlan   = "plan"
+	searchReport = "report"
+	searchError  = "error"
+)
+
+// SearchHit is one matching task file with a highlighted snippet.
+type SearchHit struct {
+	TaskID  int     `json:"task_id"`
+	Title   string  `json:"title"`
+	Status  string  `json:"status"`
+	Kind    string  `json:"kind"` // spec, plan, report, error
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"` // bm25, lower is better
+}
+
+// searchUnavailableMsg is shown when the SQLite build lacks FTS5.
+const searchUnavailableMsg = "검색 색인을 사용할 수 없습니다 (FTS5 미지원 빌드: -tags sqlite_fts5로 빌드하세요)"
+
+// searchAvailable reports whether the task_search FTS5 table exists.
+func searchAvailable(localDB *db.DB) bool {
+	var name string
+	return localDB.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'task_search'`).Scan(&name) == nil
+}
+
+// indexTaskContent replaces the index entry of one task file (empty content removes it).
+// Best effort: failures are logged, and nothing happens for a project without a local DB.
+func indexTaskContent(projectPath string, id int, kind, content string) {
+	if _, err := os.Stat(filepath.Join(projectPath, ".claribot", "db.clt")); err != nil {
+		return
+	}
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		log.Printf("[Search] DB 열기 실패 (#%d %s): %v", id, kind, err)
+		return
+	}
+	defer localDB.Close()
+	if !searchAvailable(localDB) {
+		return
+	}
+
+	if _, err := localDB.Exec(`DELETE FROM task_search WHERE task_id = ? AND kind = ?`, id, kind); err != nil {
+		log.Printf("[Search] 색인 삭제 실패 (#%d %s): %v", id, kind, err)
+		return
+	}
+	if strings.TrimSpace(content) == "" {
+		return
+	}
+	if _, err := localDB.Exec(`INSERT INTO task_search (task_id, kind, content) VALUES (?, ?, ?)`, id, kind, content); err != nil {
+		log.Printf("[Search] 색인 저장 실패 (#%d %s): %v", id, kind, err)
+	}
+}
+
+// specSearchContent is the indexed text of a task file: title and spec body.
+func specSearchContent(title, body string) string {
+	return title + "\n\n" + body
+}
+
+// reindexSearch rebuilds the whole index from the task files.
+// Returns the number of indexed files (0 without FTS5).
+func reindexSearch(localDB *db.DB, projectPath string) (int, error) {
+	return reindexSearchTasks(localDB, projectPath, nil)
+}
+
+// reindexSearchTasks re-indexes the files of the tasks in only (nil = all tasks).
+func reindexSearchTasks(localDB *db.DB, projectPath string, only map[int]bool) (int, error) {
+	if !searchAvailable(localDB) {
+		return 0, nil
+	}
+	fileMap, err := ScanTaskFiles(projectPath)
+	if err != nil {
+		return 0, fmt.Errorf("파일 스캔 실패: %w", err)
+	}
+
+	tx, err := localDB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("트랜잭션 시작 실패: %w", err)
+	}
+	if only == nil {
+		_, err = tx.Exec(`DELETE FROM task_search`)
+	} else {
+		for id := range only {
+			if _, err = tx.Exec(`DELETE FROM task_search WHERE task_id = ?`, id); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("색인 초기화 실패: %w", err)
+	}
+
+	indexed := 0
+	insert := func(id int, kind, content string) {
+		if strings.TrimSpace(content) == "" {
+			return
+		}
+		if _, err := tx.Exec(`INSERT INTO task_search (task_id, kind, content) VALUES (?, ?, ?)`, id, kind, content); err != nil {
+			log.Printf("[Search] 색인 저장 실패 (#%d %s): %v", id, kind, err)
+			return
+		}
+		indexed++
+	}
+	for id := range fileMap {
+		if only != nil && !only[id] {
+			continue
+		}
+		if tc, err := ReadTaskContent(projectPath, id); err == nil {
+			insert(id, searchSpec, specSearchContent(tc.Title, tc.Body))
+		}
+		if plan, err := ReadPlanContent(projectPath, id); err == nil {
+			insert(id, searchPlan, plan)
+		}
+		if report, err := ReadReportContent(projectPath, id); err == nil {
+			insert(id, searchReport, report)
+		}
+		if errContent, err := ReadErrorContent(projectPath, id); err == nil {
+			insert(id, searchError, errContent)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
+	}
+	return indexed, nil
+}
+
+// buildMatchQuery turns user input into an FTS5 query: every word must match,
+// as a prefix so Korean words match with particles attached ("결제" → "결제를").
+func buildMatchQuery(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		word = strings.ReplaceAll(word, `"`, "")
+		if word == "" {
+			continue
+		}
+		terms = append(terms, `"`+word+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// Search finds tasks whose spec, plan, report or error matches the query, best match first.
+func Search(projectPath, query string, req pagination.PageRequest) types.Result {
+	match := buildMatchQuery(query)
+	if match == "" {
+		return types.Result{Success: false, Message: "검색어를 입력하세요"}
+	}
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("DB 열기 실패: %v", err),
+		}
+	}
+	defer localDB.Close()
+
+	if !searchAvailable(localDB) {
+		return types.Result{Success: false, Message: searchUnavailableMsg}
+	}
+
+	// Index projects that predate the search table on first use
+	var indexed, tasks int
+	localDB.QueryRow(`SELECT COUNT(*) FROM task_search`).Scan(&indexed)
+	localDB.QueryRow(`SELECT COUNT(*) FROM tasks`).Scan(&tasks)
+	if indexed == 0 && tasks > 0 {
+		if _, err := reindexSearch(localDB, projectPath); err != nil {
+			log.Printf("[Search] 색인 생성 실패: %v", err)
+		}
+	}
+
+	var total int
+	if err := localDB.QueryRow(`
+		SELECT COUNT(*) FROM task_search JOIN tasks t ON t.id = task_search.task_id
+		WHERE task_search MATCH ?
+	`, match).Scan(&total); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("검색 실패: %v", err),
+		}
+	}
+	if total == 0 {
+		return types.Result{
+			Success: true,
+			Message: fmt.Sprintf("검색 결과가 없습니다: %s", query),
+		}
+	}
+
+	rows, err := localDB.Query(`
+		SELECT task_search.task_id, task_search.kind, t.title, t.status,
+			snippet(task_search, 2, '«', '»', '…', 16), bm25(task_search)
+		FROM task_search JOIN tasks t ON t.id = task_search.task_id
+		WHERE task_search MATCH ?
+		ORDER BY bm25(task_search), task_search.task_id DESC
+		LIMIT ? OFFSET ?
+	`, match, req.Limit(), req.Offset())
+	if err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("검색 실패: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var h SearchHit
+		if err := rows.Scan(&h.TaskID, &h.Kind, &h.Title, &h.Status, &h.Snippet, &h.Rank); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("스캔 실패: %v", err),
+			}
+		}
+		h.Snippet = strings.Join(strings.Fields(h.Snippet), " ")
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return types.Result{
+			Success: false,
+			Message: fmt.Sprintf("행 순회 오류: %v", err),
+		}
+	}
+
+	pageResp := pagination.NewPageResponse(hits, req.Page, req.PageSize, total)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔍 \"%s\" (%d/%d 페이지, 총 %d건)\n", query, pageResp.Page, pageResp.TotalPages, total))
+	for _, h := range hits {
+		sb.WriteString(fmt.Sprintf("  %s [#%d:task get %d] %s (%s)\n", statusToIcon(h.Status), h.TaskID, h.TaskID, h.Title, h.Kind))
+		sb.WriteString(fmt.Sprintf("      %s\n", h.Snippet))
+	}
+
+	if pageResp.HasPrev || pageResp.HasNext {
+		searchCmd := "task search " + query
+		if strings.ContainsAny(query, " \t") {
+			searchCmd = fmt.Sprintf("task search \"%s\"", query)
+		}
+		sb.WriteString("\n")
+		if pageResp.HasPrev {
+			sb.WriteString(fmt.Sprintf("[◀ 이전:%s -p %d]", searchCmd, pageResp.Page-1))
+		}
+		if pageResp.HasNext {
+			sb.WriteString(fmt.Sprintf("[다음 ▶:%s -p %d]", searchCmd, pageResp.Page+1))
+		}
+	}
+
+	return types.Result{
+		Success: true,
+		Message: sb.String(),
+		Data:    pageResp,
+	}
+}
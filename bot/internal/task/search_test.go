@@ -0,0 +1,127 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/pkg/pagination"
+)
+
+// requireSearch skips the test when SQLite was built without FTS5 (-tags sqlite_fts5).
+func requireSearch(t *testing.T, projectPath string) {
+	t.Helper()
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+	if !searchAvailable(localDB) {
+		t.Skip("FTS5 not available (build with -tags sqlite_fts5)")
+	}
+}
+
+func searchIDs(t *testing.T, projectPath, query string) []string {
+	t.Helper()
+	result := Search(projectPath, query, pagination.NewPageRequest(1, 20))
+	if !result.Success {
+		t.Fatalf("Search(%q) failed: %s", query, result.Message)
+	}
+	if result.Data == nil {
+		return nil
+	}
+	var out []string
+	for _, h := range result.Data.(pagination.PageResponse[SearchHit]).Items {
+		out = append(out, fmt.Sprintf("%s:%d", h.Kind, h.TaskID))
+	}
+	return out
+}
+
+func TestBuildMatchQuery(t *testing.T) {
+	if got := buildMatchQuery(`payments "webhook`); got != `"payments"* "webhook"*` {
+		t.Errorf("Unexpected match query: %s", got)
+	}
+	if got := buildMatchQuery(`  "" `); got != "" {
+		t.Errorf("Expected empty match query, got %s", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	requireSearch(t, projectPath)
+
+	Add(projectPath, "Payments webhook", nil, "Stripe 결제를 처리하는 webhook 핸들러")
+	Add(projectPath, "Login page", nil, "로그인 화면")
+	Set(projectPath, "2", "plan", "Add a retry around the payments client", ActorCLI)
+	WriteReportContent(projectPath, 2, "Done, see payments_test.go")
+
+	got := searchIDs(t, projectPath, "payments")
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 hits, got %v", got)
+	}
+	if got := searchIDs(t, projectPath, "결제"); len(got) != 1 || got[0] != "spec:1" {
+		t.Errorf("Expected prefix match on Korean word, got %v", got)
+	}
+	if got := searchIDs(t, projectPath, "payments retry"); len(got) != 1 || got[0] != "plan:2" {
+		t.Errorf("Expected AND of terms, got %v", got)
+	}
+
+	result := Search(projectPath, "webhook", pagination.NewPageRequest(1, 20))
+	hit := result.Data.(pagination.PageResponse[SearchHit]).Items[0]
+	if hit.Title != "Payments webhook" || !strings.Contains(hit.Snippet, "«webhook»") {
+		t.Errorf("Unexpected hit: %+v", hit)
+	}
+
+	// Rewriting a file replaces its entry
+	Set(projectPath, "2", "plan", "Nothing to see", ActorCLI)
+	if got := searchIDs(t, projectPath, "retry"); len(got) != 0 {
+		t.Errorf("Expected stale plan to be gone, got %v", got)
+	}
+
+	// Deleted tasks disappear from the index
+	Delete(projectPath, "1", true)
+	if got := searchIDs(t, projectPath, "webhook"); len(got) != 0 {
+		t.Errorf("Expected deleted task to be gone, got %v", got)
+	}
+
+	if result := Search(projectPath, " ", pagination.NewPageRequest(1, 20)); result.Success {
+		t.Error("Expected empty query to fail")
+	}
+}
+
+func TestSearchSync(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	requireSearch(t, projectPath)
+
+	Add(projectPath, "Billing", nil, "spec")
+
+	// A report written outside claribot is picked up by sync
+	if err := os.WriteFile(ReportFilePath(projectPath, 1), []byte("invoice numbering fixed"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if got := searchIDs(t, projectPath, "invoice"); len(got) != 0 {
+		t.Fatalf("Expected no hit before sync, got %v", got)
+	}
+	result, err := Sync(projectPath)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Indexed != 2 {
+		t.Errorf("Expected 2 indexed files, got %d", result.Indexed)
+	}
+	if got := searchIDs(t, projectPath, "invoice"); len(got) != 1 || got[0] != "report:1" {
+		t.Errorf("Expected report hit after sync, got %v", got)
+	}
+
+	// An empty index is built on first search
+	localDB, _ := db.OpenLocal(projectPath)
+	localDB.Exec(`DELETE FROM task_search`)
+	localDB.Close()
+	if got := searchIDs(t, projectPath, "billing"); len(got) != 1 {
+		t.Errorf("Expected index to be rebuilt on first search, got %v", got)
+	}
+}
@@ -1,30 +1,75 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
 	"strconv"
+	"strings"
 
 	"parkjunwoo.com/claribot/internal/db"
 	"parkjunwoo.com/claribot/internal/types"
 )
 
-// Set updates a task field
-func Set(projectPath, id, field, value string) types.Result {
+// Set updates a task field. actor is recorded when the status changes (see task_events).
+func Set(projectPath, id, field, value, actor string) types.Result {
+	ctx := context.Background() // not part of a traversal: commits on its own
 	// Allowed fields
 	allowedFields := map[string]bool{
-		"title":    true,
-		"spec":     true,
-		"plan":     true,
-		"report":   true,
-		"status":   true,
-		"priority": true,
+		"title":         true,
+		"spec":          true,
+		"plan":          true,
+		"report":        true,
+		"status":        true,
+		"priority":      true,
+		"depends_on":    true,
+		"labels":        true,
+		"model":         true,
+		"allowed_tools": true,
+		"timeout":       true,
 	}
 
 	if !allowedFields[field] {
 		return types.Result{
 			Success: false,
-			Message: fmt.Sprintf("허용되지 않는 필드: %s\n허용: title, spec, plan, report, status, priority", field),
+			Message: fmt.Sprintf("허용되지 않는 필드: %s\n허용: title, spec, plan, report, status, priority, depends_on, labels, model, allowed_tools, timeout", field),
+		}
+	}
+
+	// Validate depends_on (comma-separated task IDs, empty clears)
+	var dependsOn []int
+	if field == "depends_on" {
+		ids, err := ParseDependsOn(value)
+		if err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("depends_on 형식 오류: %v", err),
+			}
+		}
+		dependsOn = ids
+	}
+
+	// Validate labels (comma/space-separated words, empty clears)
+	var labels []string
+	if field == "labels" {
+		parsed, err := ParseLabels(value)
+		if err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("labels 형식 오류: %v", err),
+			}
+		}
+		labels = parsed
+	}
+
+	// Validate timeout (duration, empty clears)
+	if field == "timeout" {
+		if _, err := ParseRunTimeout(value); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("timeout 형식 오류: %v", err),
+			}
 		}
 	}
 
@@ -43,6 +88,7 @@ func Set(projectPath, id, field, value string) types.Result {
 		validStatus := map[string]bool{
 			"todo":    true,
 			"planned": true,
+			"review":  true,
 			"split":   true,
 			"done":    true,
 			"failed":  true,
@@ -50,7 +96,7 @@ func Set(projectPath, id, field, value string) types.Result {
 		if !validStatus[value] {
 			return types.Result{
 				Success: false,
-				Message: "허용되지 않는 상태: " + value + "\n허용: todo, planned, split, done, failed",
+				Message: "허용되지 않는 상태: " + value + "\n허용: todo, planned, review, split, done, failed",
 			}
 		}
 	}
@@ -66,7 +112,8 @@ func Set(projectPath, id, field, value string) types.Result {
 
 	// Check if task exists and get current status
 	var currentStatus string
-	err = localDB.QueryRow("SELECT status FROM tasks WHERE id = ?", id).Scan(&currentStatus)
+	var isLeaf bool
+	err = localDB.QueryRow("SELECT status, is_leaf FROM tasks WHERE id = ?", id).Scan(&currentStatus, &isLeaf)
 	if err != nil {
 		return types.Result{
 			Success: false,
@@ -74,11 +121,11 @@ func Set(projectPath, id, field, value string) types.Result {
 		}
 	}
 
-	// Prevent changing from 'split' status (has children, cannot revert)
-	if field == "status" && currentStatus == "split" && value != "split" {
+	// Prevent changing the status of a parent (it follows its children, see rollUp)
+	if field == "status" && (currentStatus == "split" || !isLeaf) && value != currentStatus {
 		return types.Result{
 			Success: false,
-			Message: fmt.Sprintf("작업 #%s는 하위 작업이 있어 split 상태를 변경할 수 없습니다", id),
+			Message: fmt.Sprintf("작업 #%s는 하위 작업이 있어 상태를 변경할 수 없습니다 (하위 작업 상태를 따름)", id),
 		}
 	}
 
@@ -89,11 +136,15 @@ func Set(projectPath, id, field, value string) types.Result {
 	case "spec":
 		tc, err := ReadTaskContent(projectPath, taskID)
 		if err != nil {
-			// File doesn't exist — query DB for meta and create new file
-			var parentID *int
-			var title string
-			localDB.QueryRow("SELECT parent_id, title FROM tasks WHERE id = ?", id).Scan(&parentID, &title)
-			fm := Frontmatter{Status: currentStatus, Parent: parentID}
+			// Rewriting a file that exists but cannot be parsed would drop its run options
+			if _, statErr := os.Stat(TaskFilePath(projectPath, taskID)); statErr == nil {
+				return types.Result{
+					Success: false,
+					Message: fmt.Sprintf("작업 파일을 읽을 수 없습니다 (#%d): %v", taskID, err),
+				}
+			}
+			// File doesn't exist — rebuild its frontmatter from the DB
+			fm, title := frontmatterFromDB(localDB, taskID)
 			if err := WriteTaskContent(projectPath, taskID, fm, title, value); err != nil {
 				log.Printf("[Task] spec 파일 생성 실패 (#%d): %v", taskID, err)
 			}
@@ -103,21 +154,21 @@ func Set(projectPath, id, field, value string) types.Result {
 			}
 		}
 		localDB.Exec("UPDATE tasks SET updated_at = ? WHERE id = ?", now, id)
-		gitCommitTask(projectPath, taskID, "spec updated")
+		gitCommitTask(ctx, projectPath, taskID, "spec updated")
 
 	case "plan":
 		if err := WritePlanContent(projectPath, taskID, value); err != nil {
 			log.Printf("[Task] plan 파일 생성 실패 (#%d): %v", taskID, err)
 		}
 		localDB.Exec("UPDATE tasks SET updated_at = ? WHERE id = ?", now, id)
-		gitCommitTask(projectPath, taskID, "plan updated")
+		gitCommitTask(ctx, projectPath, taskID, "plan updated")
 
 	case "report":
 		if err := WriteReportContent(projectPath, taskID, value); err != nil {
 			log.Printf("[Task] report 파일 생성 실패 (#%d): %v", taskID, err)
 		}
 		localDB.Exec("UPDATE tasks SET updated_at = ? WHERE id = ?", now, id)
-		gitCommitTask(projectPath, taskID, "report updated")
+		gitCommitTask(ctx, projectPath, taskID, "report updated")
 
 	case "title":
 		// DB update + file title sync
@@ -127,9 +178,10 @@ func Set(projectPath, id, field, value string) types.Result {
 				log.Printf("[Task] title 파일 업데이트 실패 (#%d): %v", taskID, err)
 			}
 		}
-		gitCommitTask(projectPath, taskID, "title updated")
+		gitCommitTask(ctx, projectPath, taskID, "title updated")
 
 	case "status":
+		from := taskStatus(localDB, taskID)
 		query := "UPDATE tasks SET status = ?, updated_at = ? WHERE id = ?"
 		_, err = localDB.Exec(query, value, now, id)
 		if err != nil {
@@ -138,8 +190,15 @@ func Set(projectPath, id, field, value string) types.Result {
 				Message: fmt.Sprintf("업데이트 실패: %v", err),
 			}
 		}
+		recordEvent(localDB, taskID, from, value, actor, 0)
 		updateTaskFileStatus(projectPath, taskID, value)
-		gitCommitTask(projectPath, taskID, value)
+		gitCommitTask(ctx, projectPath, taskID, value)
+		if rollups := formatRollups(rollUp(ctx, localDB, projectPath)); rollups != "" {
+			return types.Result{
+				Success: true,
+				Message: fmt.Sprintf("작업 #%s status 업데이트됨\n%s\n[조회:task get %s]", id, rollups, id),
+			}
+		}
 
 	case "priority":
 		query := "UPDATE tasks SET priority = ?, updated_at = ? WHERE id = ?"
@@ -150,6 +209,58 @@ func Set(projectPath, id, field, value string) types.Result {
 				Message: fmt.Sprintf("업데이트 실패: %v", err),
 			}
 		}
+
+	case "depends_on":
+		if err := SetDependencies(localDB, taskID, dependsOn); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("의존성 설정 실패: %v", err),
+			}
+		}
+		localDB.Exec("UPDATE tasks SET updated_at = ? WHERE id = ?", now, id)
+		if err := updateTaskFileDependsOn(projectPath, taskID, dependsOn); err != nil {
+			log.Printf("[Task] depends_on 파일 업데이트 실패 (#%d): %v", taskID, err)
+		}
+		gitCommitTask(ctx, projectPath, taskID, "depends_on updated")
+
+	case "labels":
+		if err := SetLabels(localDB, taskID, labels); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("라벨 설정 실패: %v", err),
+			}
+		}
+		localDB.Exec("UPDATE tasks SET updated_at = ? WHERE id = ?", now, id)
+		if err := updateTaskFileLabels(projectPath, taskID, labels); err != nil {
+			log.Printf("[Task] labels 파일 업데이트 실패 (#%d): %v", taskID, err)
+		}
+		gitCommitTask(ctx, projectPath, taskID, "labels updated")
+
+	case "model", "allowed_tools", "timeout":
+		// Frontmatter only (empty value clears and falls back to parent/project default)
+		tc, err := ReadTaskContent(projectPath, taskID)
+		if err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("작업 파일 읽기 실패: %v", err),
+			}
+		}
+		switch field {
+		case "model":
+			tc.Frontmatter.Model = strings.TrimSpace(value)
+		case "allowed_tools":
+			tc.Frontmatter.AllowedTools = ParseAllowedTools(value)
+		case "timeout":
+			tc.Frontmatter.Timeout = strings.TrimSpace(value)
+		}
+		if err := WriteTaskContent(projectPath, taskID, tc.Frontmatter, tc.Title, tc.Body); err != nil {
+			return types.Result{
+				Success: false,
+				Message: fmt.Sprintf("작업 파일 업데이트 실패: %v", err),
+			}
+		}
+		localDB.Exec("UPDATE tasks SET updated_at = ? WHERE id = ?", now, id)
+		gitCommitTask(ctx, projectPath, taskID, field+" updated")
 	}
 
 	return types.Result{
@@ -157,3 +268,20 @@ func Set(projectPath, id, field, value string) types.Result {
 		Message: fmt.Sprintf("작업 #%s %s 업데이트됨\n[조회:task get %s]", id, field, id),
 	}
 }
+
+// frontmatterFromDB rebuilds the frontmatter and title of a task whose file is missing
+// from the DB columns and tables (run options only live in the file and are lost).
+func frontmatterFromDB(localDB *db.DB, taskID int) (Frontmatter, string) {
+	var fm Frontmatter
+	var title string
+	localDB.QueryRow("SELECT status, parent_id, priority, title FROM tasks WHERE id = ?", taskID).Scan(&fm.Status, &fm.Parent, &fm.Priority, &title)
+	var err error
+	if fm.DependsOn, err = loadDependencies(localDB, taskID); err != nil {
+		log.Printf("[Task] 선행 작업 조회 실패 (#%d): %v", taskID, err)
+	}
+	if fm.Labels, err = loadLabels(localDB, taskID); err != nil {
+		log.Printf("[Task] 라벨 조회 실패 (#%d): %v", taskID, err)
+	}
+	fm.Node = lookupGraphNode(localDB, taskID)
+	return fm, title
+}
@@ -1,26 +1,68 @@
 package task
 
 import (
+	"context"
 	"fmt"
-	"sync/atomic"
+	"strconv"
+	"strings"
+	"sync"
 )
 
-// cancelRequested is the atomic flag for graceful stop
-var cancelRequested atomic.Bool
+// Running tasks per project, with the cancel function of each task's own context
+// (derived from the traversal context, so stopping the traversal still reaches them)
+var (
+	taskMu      sync.Mutex
+	taskCancels = make(map[string]map[int]context.CancelFunc)
+)
+
+// withTaskCancel derives a cancellable context for one task of a project and registers it,
+// so CancelTask can stop that task alone. The returned func must be called when the task ends.
+func withTaskCancel(ctx context.Context, projectPath string, taskID int) (context.Context, func()) {
+	taskCtx, cancel := context.WithCancel(ctx)
+	taskMu.Lock()
+	if taskCancels[projectPath] == nil {
+		taskCancels[projectPath] = make(map[int]context.CancelFunc)
+	}
+	taskCancels[projectPath][taskID] = cancel
+	taskMu.Unlock()
 
-// RequestCancel sets the cancel flag to true
-func RequestCancel() {
-	cancelRequested.Store(true)
+	return taskCtx, func() {
+		taskMu.Lock()
+		delete(taskCancels[projectPath], taskID)
+		if len(taskCancels[projectPath]) == 0 {
+			delete(taskCancels, projectPath)
+		}
+		taskMu.Unlock()
+		cancel()
+	}
 }
 
-// IsCancelled returns true if cancel has been requested
-func IsCancelled() bool {
-	return cancelRequested.Load()
+// IsTaskRunning returns true if Claude is working on the task (plan or run).
+func IsTaskRunning(projectPath string, taskID int) bool {
+	taskMu.Lock()
+	defer taskMu.Unlock()
+	_, ok := taskCancels[projectPath][taskID]
+	return ok
 }
 
-// ResetCancel clears the cancel flag
-func ResetCancel() {
-	cancelRequested.Store(false)
+// CancelTask cancels one running task of a project without stopping its traversal.
+// The task keeps its status (todo/planned), its attempt is recorded as cancelled,
+// and the traversal moves on to the next task.
+func CancelTask(projectPath, id string) (string, bool) {
+	taskID, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil {
+		return fmt.Sprintf("잘못된 작업 ID: %s", id), false
+	}
+
+	taskMu.Lock()
+	cancel := taskCancels[projectPath][taskID]
+	taskMu.Unlock()
+	if cancel == nil {
+		return fmt.Sprintf("작업 #%d은(는) 실행 중이 아닙니다", taskID), false
+	}
+
+	cancel()
+	return fmt.Sprintf("🛑 [%s] 작업 #%d 취소 요청됨", getProjectID(projectPath), taskID), true
 }
 
 // Stop requests cancellation of all running traversals.
@@ -31,7 +73,6 @@ func Stop() (string, bool) {
 		return "순회 중인 프로젝트가 없습니다.", false
 	}
 
-	RequestCancel()
 	CancelAllCycles()
 
 	var msg string
@@ -41,7 +82,7 @@ func Stop() (string, bool) {
 		if typeLabel == "" {
 			typeLabel = state.Type
 		}
-		msg = fmt.Sprintf("🛑 [%s] %s 즉시 중단 요청됨", state.ProjectID, typeLabel)
+		msg = fmt.Sprintf("🛑 [%s] %s%s 즉시 중단 요청됨", state.ProjectID, scopeLabel(state.RootID), typeLabel)
 		if state.CurrentTaskID > 0 {
 			msg += fmt.Sprintf(" (Task #%d)", state.CurrentTaskID)
 		}
@@ -69,7 +110,7 @@ func StopProject(projectPath string) (string, bool) {
 		typeLabel = state.Type
 	}
 
-	msg := fmt.Sprintf("🛑 [%s] %s 즉시 중단 요청됨", getProjectID(projectPath), typeLabel)
+	msg := fmt.Sprintf("🛑 [%s] %s%s 즉시 중단 요청됨", getProjectID(projectPath), scopeLabel(state.RootID), typeLabel)
 	if state.CurrentTaskID > 0 {
 		msg += fmt.Sprintf(" (Task #%d)", state.CurrentTaskID)
 	}
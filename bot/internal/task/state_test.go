@@ -0,0 +1,77 @@
+package task
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBatchModeOnContext(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	setupGitRepo(t, projectPath)
+
+	Add(projectPath, "Task", nil, "spec")
+	commits := func() string {
+		out, _ := runGit(projectPath, "rev-list", "--count", "HEAD")
+		return out
+	}
+	before := commits()
+
+	// A traversal's batch ctx suppresses per-task commits...
+	WriteReportContent(projectPath, 1, "report")
+	gitCommitTask(withBatch(context.Background()), projectPath, 1, "report updated")
+	if commits() != before {
+		t.Error("Expected no commit in batch mode")
+	}
+
+	// ...but a manual command in the same project still commits
+	gitCommitTask(context.Background(), projectPath, 1, "report updated")
+	if commits() == before {
+		t.Error("Expected a commit outside the batch ctx")
+	}
+}
+
+func TestCancelTask(t *testing.T) {
+	traversalCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	ctxA1, doneA1 := withTaskCancel(traversalCtx, "/tmp/project-a", 1)
+	ctxA2, doneA2 := withTaskCancel(traversalCtx, "/tmp/project-a", 2)
+	defer doneA2()
+	ctxB1, doneB1 := withTaskCancel(context.Background(), "/tmp/project-b", 1)
+	defer doneB1()
+
+	if !IsTaskRunning("/tmp/project-a", 1) || IsTaskRunning("/tmp/project-a", 3) {
+		t.Error("Unexpected running tasks for project A")
+	}
+
+	if _, ok := CancelTask("/tmp/project-a", "#1"); !ok {
+		t.Fatal("Expected task #1 of project A to be cancelled")
+	}
+	if ctxA1.Err() == nil {
+		t.Error("Expected task #1 context to be cancelled")
+	}
+	if ctxA2.Err() != nil || ctxB1.Err() != nil || traversalCtx.Err() != nil {
+		t.Error("Cancelling one task must not cancel other tasks or the traversal")
+	}
+
+	doneA1()
+	if IsTaskRunning("/tmp/project-a", 1) {
+		t.Error("Expected task #1 to be unregistered when done")
+	}
+	if msg, ok := CancelTask("/tmp/project-a", "1"); ok {
+		t.Errorf("Expected cancel of a finished task to fail: %s", msg)
+	}
+	if _, ok := CancelTask("/tmp/project-a", "abc"); ok {
+		t.Error("Expected invalid ID to be rejected")
+	}
+
+	// Stopping the traversal still reaches its tasks
+	stop()
+	if ctxA2.Err() == nil {
+		t.Error("Expected task #2 context to be cancelled with its traversal")
+	}
+	if ctxB1.Err() != nil {
+		t.Error("Stopping project A must not cancel project B")
+	}
+}
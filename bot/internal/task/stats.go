@@ -27,10 +27,11 @@ func GetStats(projectPath string) (*Stats, error) {
 			COALESCE(SUM(CASE WHEN is_leaf = 1 THEN 1 ELSE 0 END), 0),
 			COALESCE(SUM(CASE WHEN is_leaf = 1 AND status = 'todo' THEN 1 ELSE 0 END), 0),
 			COALESCE(SUM(CASE WHEN is_leaf = 1 AND status = 'planned' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN is_leaf = 1 AND status = 'review' THEN 1 ELSE 0 END), 0),
 			COALESCE(SUM(CASE WHEN is_leaf = 1 AND status = 'done' THEN 1 ELSE 0 END), 0),
 			COALESCE(SUM(CASE WHEN is_leaf = 1 AND status = 'failed' THEN 1 ELSE 0 END), 0)
 		FROM tasks
-	`).Scan(&stats.Total, &stats.Leaf, &stats.Todo, &stats.Planned, &stats.Done, &stats.Failed)
+	`).Scan(&stats.Total, &stats.Leaf, &stats.Todo, &stats.Planned, &stats.Review, &stats.Done, &stats.Failed)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"path/filepath"
@@ -16,6 +17,7 @@ type SyncResult struct {
 	Deleted  int
 	Restored int
 	Skipped  int
+	Indexed  int // task files in the search index
 	Warnings []string
 }
 
@@ -30,6 +32,34 @@ type dbTask struct {
 // Sync synchronizes task files with the database.
 // Files are the source of truth — DB is updated to match.
 func Sync(projectPath string) (*SyncResult, error) {
+	return syncTasks(context.Background(), projectPath, nil, ActorSync)
+}
+
+// SyncWithContext is Sync on behalf of someone else: status changes are recorded
+// with the actor set by WithActor (ActorSync if none). The scheduler uses it after a run.
+func SyncWithContext(ctx context.Context, projectPath string) (*SyncResult, error) {
+	actor, ok := ctx.Value(actorCtxKey{}).(string)
+	if !ok {
+		actor = ActorSync
+	}
+	return syncTasks(ctx, projectPath, nil, actor)
+}
+
+// SyncTasks synchronizes only the given tasks (used by the file watcher: status
+// changes are recorded with ActorWatch).
+// Tree-wide fields (is_leaf, depth) are still recomputed for the whole tree.
+func SyncTasks(projectPath string, ids []int) (*SyncResult, error) {
+	only := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		only[id] = true
+	}
+	return syncTasks(context.Background(), projectPath, only, ActorWatch)
+}
+
+// syncTasks is Sync limited to the tasks in only (nil = all tasks).
+// actor is recorded on the status changes taken from the files; in a traversal
+// (batch ctx) the roll-up commits are left to its batch commit.
+func syncTasks(ctx context.Context, projectPath string, only map[int]bool, actor string) (*SyncResult, error) {
 	result := &SyncResult{}
 
 	// 1. Scan task files
@@ -37,6 +67,13 @@ func Sync(projectPath string) (*SyncResult, error) {
 	if err != nil {
 		return nil, fmt.Errorf("파일 스캔 실패: %w", err)
 	}
+	if only != nil {
+		for id := range fileMap {
+			if !only[id] {
+				delete(fileMap, id)
+			}
+		}
+	}
 
 	// 2. Load DB tasks
 	localDB, err := db.OpenLocal(projectPath)
@@ -57,10 +94,22 @@ func Sync(projectPath string) (*SyncResult, error) {
 			log.Printf("[Sync] scan 실패: %v", err)
 			continue
 		}
+		if only != nil && !only[t.ID] {
+			continue
+		}
 		dbMap[t.ID] = t
 	}
 	rows.Close()
 
+	depMap, err := loadDepMap(localDB)
+	if err != nil {
+		return nil, err
+	}
+	labelMap, err := loadLabelMap(localDB)
+	if err != nil {
+		return nil, err
+	}
+
 	// 3. Begin transaction
 	tx, err := localDB.Begin()
 	if err != nil {
@@ -68,6 +117,9 @@ func Sync(projectPath string) (*SyncResult, error) {
 	}
 
 	parentMap := make(map[int]*int)
+	fileDeps := make(map[int][]int)
+	fileNodes := make(map[int]string)
+	fileLabels := make(map[int][]string)
 
 	// Case A: file exists, DB missing → INSERT
 	for id, filePath := range fileMap {
@@ -83,6 +135,9 @@ func Sync(projectPath string) (*SyncResult, error) {
 			continue
 		}
 		parentMap[id] = tc.Frontmatter.Parent
+		fileDeps[id] = tc.Frontmatter.DependsOn
+		fileNodes[id] = tc.Frontmatter.Node
+		fileLabels[id] = normalizeLabels(tc.Frontmatter.Labels)
 
 		if _, exists := dbMap[id]; !exists {
 			// INSERT
@@ -126,6 +181,7 @@ func Sync(projectPath string) (*SyncResult, error) {
 					result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: UPDATE 실패: %v", id, err))
 				} else {
 					result.Updated++
+					recordEvent(tx, id, dbt.Status, tc.Frontmatter.Status, actor, 0)
 				}
 			}
 		}
@@ -156,6 +212,87 @@ func Sync(projectPath string) (*SyncResult, error) {
 		}
 	}
 
+	// Dependencies: frontmatter depends_on → task_deps (cycles are skipped with a warning)
+	for id, deps := range fileDeps {
+		if sameDeps(depMap[id], deps) {
+			continue
+		}
+		others := make(map[int][]int, len(depMap))
+		for k, v := range depMap {
+			if k != id {
+				others[k] = v
+			}
+		}
+		if cycle := findDepCycle(others, id, deps); cycle != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: 순환 의존성으로 depends_on 무시: %s", id, formatDependsOn(cycle)))
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM task_deps WHERE task_id = ?`, id); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: depends_on 갱신 실패: %v", id, err))
+			continue
+		}
+		now := db.TimeNow()
+		saved := make([]int, 0, len(deps))
+		for _, dep := range deps {
+			if _, err := tx.Exec(`INSERT INTO task_deps (task_id, depends_on, created_at) VALUES (?, ?, ?)`, id, dep, now); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: 선행 작업 #%d 저장 실패: %v", id, dep, err))
+				continue
+			}
+			saved = append(saved, dep)
+		}
+		// Later cycle checks see what was stored; only a complete update counts
+		depMap[id] = saved
+		if len(saved) == len(deps) {
+			result.Updated++
+		}
+	}
+
+	// Labels: frontmatter labels → task_labels
+	for id, labels := range fileLabels {
+		if sameLabels(labelMap[id], labels) {
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM task_labels WHERE task_id = ?`, id); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: labels 갱신 실패: %v", id, err))
+			continue
+		}
+		for _, label := range labels {
+			if _, err := tx.Exec(`INSERT INTO task_labels (task_id, label) VALUES (?, ?)`, id, label); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: 라벨 %s 저장 실패: %v", id, label, err))
+			}
+		}
+		result.Updated++
+	}
+
+	// Graph nodes: frontmatter node → graph_nodes
+	dbNodes := make(map[int]string)
+	if nodes, err := loadGraphNodes(localDB); err == nil {
+		for _, n := range nodes {
+			dbNodes[n.TaskID] = n.NodeID
+		}
+	}
+	for id, nodeID := range fileNodes {
+		if dbNodes[id] == nodeID {
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM graph_nodes WHERE task_id = ?`, id); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: node 갱신 실패: %v", id, err))
+			continue
+		}
+		if nodeID != "" {
+			layer, feature, ok := parseNodeID(nodeID)
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: 잘못된 node ID 무시: %s", id, nodeID))
+				continue
+			}
+			if _, err := tx.Exec(`INSERT INTO graph_nodes (task_id, node_id, feature_id, layer) VALUES (?, ?, ?, ?)`, id, nodeID, feature, layer); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("#%d: node %s 저장 실패: %v", id, nodeID, err))
+				continue
+			}
+		}
+		result.Updated++
+	}
+
 	// 4. Post-processing: is_leaf + depth
 	tx.Exec(`UPDATE tasks SET is_leaf = 1`)
 	tx.Exec(`UPDATE tasks SET is_leaf = 0 WHERE id IN (SELECT DISTINCT parent_id FROM tasks WHERE parent_id IS NOT NULL)`)
@@ -182,10 +319,26 @@ func Sync(projectPath string) (*SyncResult, error) {
 		return nil, fmt.Errorf("트랜잭션 커밋 실패: %w", err)
 	}
 
-	// 6. Git commit if changes
+	// 6. Refresh the search index (files may have been edited by hand)
+	var indexed int
+	if only != nil {
+		indexed, err = reindexSearchTasks(localDB, projectPath, only)
+	} else {
+		indexed, err = reindexSearch(localDB, projectPath)
+	}
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("검색 색인 갱신 실패: %v", err))
+	} else {
+		result.Indexed = indexed
+	}
+
+	// 7. Git commit if changes
 	changes := result.Inserted + result.Updated + result.Deleted
 	if changes > 0 {
 		gitCommitBatch(projectPath, fmt.Sprintf("sync: +%d ~%d -%d", result.Inserted, result.Updated, result.Deleted))
+
+		// Edited files may have changed child statuses
+		rollUp(ctx, localDB, projectPath)
 	}
 
 	return result, nil
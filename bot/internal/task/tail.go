@@ -0,0 +1,110 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+// tailShowBytes is how much of the output task tail shows when it starts
+const tailShowBytes = 4096
+
+// tailWait is how long task tail waits for new output when following (offset given)
+var tailWait = 25 * time.Second
+
+// TailOutput is the data of task tail.
+type TailOutput struct {
+	TaskID int    `json:"task_id"`
+	RunID  string `json:"run_id"`
+	Output string `json:"output"` // ANSI-stripped
+	Offset int64  `json:"offset"` // pass back as offset to continue
+	Done   bool   `json:"done"`   // the run ended, no more output follows
+	Live   bool   `json:"live"`   // false: read from the transcript of the last attempt
+}
+
+// Tail returns the output of a task's Claude run. Without offset it shows the
+// last part; with an offset and wait it waits (up to tailWait, or until ctx is
+// done) for output past it, so calling it again with the returned offset follows
+// the run until done. Without wait it returns what is there right away.
+// When no run is live, the end of the last attempt's transcript is shown.
+func Tail(ctx context.Context, projectPath, id, offsetStr string, wait bool) types.Result {
+	taskID, err := strconv.Atoi(id)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("잘못된 작업 ID: %s", id)}
+	}
+	offset := int64(-tailShowBytes)
+	follow := offsetStr != ""
+	if follow {
+		if offset, err = strconv.ParseInt(offsetStr, 10, 64); err != nil || offset < 0 {
+			return types.Result{Success: false, Message: fmt.Sprintf("잘못된 offset: %s", offsetStr)}
+		}
+	}
+
+	if live := claude.GetLiveForTask(projectPath, taskID); live != nil {
+		if follow && wait {
+			waitCtx, cancel := context.WithTimeout(ctx, tailWait)
+			live.Wait(waitCtx, offset)
+			cancel()
+		}
+		text, next, done := live.Text(offset)
+		out := &TailOutput{TaskID: taskID, RunID: live.Info().RunID, Output: text, Offset: next, Done: done, Live: true}
+		return types.Result{Success: true, Message: formatTail(out, follow), Data: out}
+	}
+
+	// Not running: show the transcript of the last attempt
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("DB 열기 실패: %v", err)}
+	}
+	defer localDB.Close()
+
+	var runID string
+	err = localDB.QueryRow(`SELECT COALESCE(run_id, '') FROM task_attempts WHERE task_id = ? AND run_id != '' ORDER BY id DESC LIMIT 1`, taskID).Scan(&runID)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("작업 #%d의 실행 출력이 없습니다", taskID)}
+	}
+	data, _, err := claude.ReadTranscript(projectPath, runID)
+	if err != nil {
+		return types.Result{Success: false, Message: fmt.Sprintf("실행 로그를 읽을 수 없습니다 (run %s): %v", runID, err)}
+	}
+	out := &TailOutput{TaskID: taskID, RunID: runID, Offset: int64(len(data)), Done: true}
+	if !follow {
+		out.Output = strings.ToValidUTF8(tailBytes(string(data), tailShowBytes), "")
+	}
+	return types.Result{Success: true, Message: formatTail(out, follow), Data: out}
+}
+
+// formatTail renders task tail output. While following only new output is printed.
+func formatTail(out *TailOutput, follow bool) string {
+	if follow {
+		if out.Done && out.Output == "" {
+			return fmt.Sprintf("✅ 작업 #%d 실행 종료 (run %s)", out.TaskID, out.RunID)
+		}
+		return out.Output
+	}
+
+	var sb strings.Builder
+	state := "진행 중"
+	if out.Done {
+		state = "종료"
+	}
+	if !out.Live {
+		state = "마지막 실행"
+	}
+	sb.WriteString(fmt.Sprintf("📜 작업 #%d 실행 출력 (run %s, %s)\n\n", out.TaskID, out.RunID, state))
+	if strings.TrimSpace(out.Output) == "" {
+		sb.WriteString("(출력 없음)")
+	} else {
+		sb.WriteString(strings.TrimRight(out.Output, "\n"))
+	}
+	if !out.Done {
+		sb.WriteString(fmt.Sprintf("\n\n[새로고침:task tail %d]", out.TaskID))
+	}
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package task
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+func TestTail(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	useFakeAgent(t, projectPath,
+		claude.FakeStep{Output: "\x1b[1m로그인 구현 중\x1b[0m\n", Report: "[PLANNED]\n간단한 수정"},
+	)
+	if r := Plan(projectPath, "1", ActorCLI); !r.Success {
+		t.Fatalf("Plan failed: %s", r.Message)
+	}
+
+	// The finished run stays live for a while
+	r := Tail(context.Background(), projectPath, "1", "", true)
+	if !r.Success {
+		t.Fatalf("Tail failed: %s", r.Message)
+	}
+	out := r.Data.(*TailOutput)
+	if !out.Live || !out.Done || !strings.Contains(out.Output, "로그인 구현 중") || strings.Contains(out.Output, "\x1b") {
+		t.Errorf("Unexpected tail output: %+v", out)
+	}
+	if !strings.Contains(r.Message, "작업 #1 실행 출력") {
+		t.Errorf("Unexpected message: %s", r.Message)
+	}
+
+	// Following from the end reports the end of the run without waiting
+	r = Tail(context.Background(), projectPath, "1", "999999", true)
+	if !r.Success || !r.Data.(*TailOutput).Done || !strings.Contains(r.Message, "실행 종료") {
+		t.Errorf("Expected end of run, got %s", r.Message)
+	}
+
+	if r := Tail(context.Background(), projectPath, "1", "abc", true); r.Success {
+		t.Error("Expected invalid offset to fail")
+	}
+}
+
+func TestTailTranscriptFallback(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	if r := Tail(context.Background(), projectPath, "1", "", true); r.Success {
+		t.Error("Expected no output before any run")
+	}
+
+	// An attempt whose run is no longer live: the transcript is read
+	runID := claude.NewRunID()
+	localDB, _ := db.OpenLocal(projectPath)
+	startAttempt(localDB, 1, "run", runID)
+	localDB.Close()
+	os.MkdirAll(claude.RunDir(projectPath), 0755)
+	os.WriteFile(claude.TranscriptPath(projectPath, runID, false), []byte("# run "+runID+"\n\nold output\n"), 0644)
+
+	r := Tail(context.Background(), projectPath, "1", "", true)
+	if !r.Success {
+		t.Fatalf("Tail failed: %s", r.Message)
+	}
+	out := r.Data.(*TailOutput)
+	if out.Live || !out.Done || out.RunID != runID || !strings.Contains(out.Output, "old output") {
+		t.Errorf("Unexpected fallback output: %+v", out)
+	}
+	if !strings.Contains(r.Message, "마지막 실행") {
+		t.Errorf("Unexpected message: %s", r.Message)
+	}
+}
+
+func TestTailFollowWait(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// A run of task #1 that stays live for a while without new output
+	fake := useFakeAgent(t, projectPath, claude.FakeStep{Output: "시작\n", Delay: 1500 * time.Millisecond})
+	done := make(chan struct{})
+	go func() {
+		claude.Run(claude.Options{Agent: fake.Name(), RunID: claude.NewRunID(), Origin: claude.Origin{Project: projectPath, TaskID: 1}})
+		close(done)
+	}()
+	defer func() { <-done }()
+	deadline := time.Now().Add(time.Second)
+	for claude.GetLiveForTask(projectPath, 1) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Run was not published")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	offset := strconv.FormatInt(Tail(context.Background(), projectPath, "1", "", false).Data.(*TailOutput).Offset, 10)
+
+	// Without wait (Telegram) a follow answers right away
+	start := time.Now()
+	r := Tail(context.Background(), projectPath, "1", offset, false)
+	if !r.Success || r.Data.(*TailOutput).Done || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected an immediate answer, got %q after %v", r.Message, time.Since(start))
+	}
+
+	// A waiting follow ends with the request
+	reqCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if r := Tail(reqCtx, projectPath, "1", offset, true); !r.Success || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected the wait to end with the request, got %q after %v", r.Message, time.Since(start))
+	}
+}
@@ -31,11 +31,23 @@ type Task struct {
 	Spec      string `json:"spec"`      // 요구사항 명세서 (불변)
 	Plan      string `json:"plan"`      // 계획서 (1회차 순회에서 생성, leaf만)
 	Report    string `json:"report"`    // 완료 보고서 (2회차 순회 후 생성)
-	Status    string `json:"status"`    // todo → split/planned → done
+	Status    string `json:"status"`    // todo → split/planned(/review) → done
 	Error     string `json:"error,omitempty"`
+	Verify    string `json:"verify,omitempty"` // 검증 명령 출력 (build/test/lint)
+	Review    string `json:"review,omitempty"` // 반려 코멘트 (다음 Plan에 반영)
 	IsLeaf    bool   `json:"is_leaf"`   // true: 실행 대상, false: 분할됨
 	Depth     int    `json:"depth"`     // 트리 깊이 (root=0)
 	Priority  int    `json:"priority"`  // 실행 우선순위 (높을수록 먼저 실행)
+	DependsOn []int  `json:"depends_on,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Node      string `json:"node,omitempty"` // Node Graph ID ({layer}-{feature_id})
+	Model        string   `json:"model,omitempty"`         // 실행 모델 (frontmatter → 상위 작업 → 프로젝트 기본값)
+	AllowedTools []string `json:"allowed_tools,omitempty"` // 허용 도구 제한
+	Timeout      string   `json:"timeout,omitempty"`       // idle timeout override
+	Attempts  []Attempt `json:"attempts,omitempty"`
+	Events    []TaskEvent `json:"events,omitempty"` // status timeline (task_events)
+	Replans   int       `json:"replans,omitempty"` // 실패 후 재계획 횟수
+	Changes   []FileChange `json:"changes,omitempty"` // {id}.diff 파일별 요약
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -46,6 +58,7 @@ type Stats struct {
 	Leaf       int `json:"leaf"`        // 실행 대상 (is_leaf=1)
 	Todo       int `json:"todo"`        // 명세 작성됨 (plan 대기)
 	Planned    int `json:"planned"`     // plan 작성됨 (실행 대기)
+	Review     int `json:"review"`      // plan 승인 대기
 	Done       int `json:"done"`        // 완료
 	Failed     int `json:"failed"`      // 실패
 	InProgress int `json:"in_progress"` // 현재 실행 중 (Claude 점유)
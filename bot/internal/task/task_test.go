@@ -127,7 +127,7 @@ func TestTaskSet(t *testing.T) {
 	Add(projectPath, "Test Task", nil, "")
 
 	// Set spec
-	result := Set(projectPath, "1", "spec", "Test specification")
+	result := Set(projectPath, "1", "spec", "Test specification", ActorCLI)
 	if !result.Success {
 		t.Errorf("Set spec failed: %s", result.Message)
 	}
@@ -147,13 +147,13 @@ func TestTaskSetStatus(t *testing.T) {
 	Add(projectPath, "Test Task", nil, "")
 
 	// Set invalid status
-	result := Set(projectPath, "1", "status", "invalid")
+	result := Set(projectPath, "1", "status", "invalid", ActorCLI)
 	if result.Success {
 		t.Error("Expected failure for invalid status")
 	}
 
 	// Set valid status
-	result = Set(projectPath, "1", "status", "planned")
+	result = Set(projectPath, "1", "status", "planned", ActorCLI)
 	if !result.Success {
 		t.Errorf("Set status failed: %s", result.Message)
 	}
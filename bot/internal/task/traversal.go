@@ -1,12 +1,13 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"regexp"
-	"strconv"
+	"time"
 
 	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
 )
 
 // insertTraversal inserts a new traversal record and returns its ID.
@@ -34,16 +35,134 @@ func finishTraversal(localDB *db.DB, travID int64, status string, total, success
 	}
 }
 
-// countFromMessage extracts total/success/failed counts from result message.
-// Matches patterns like "성공 3개, 실패 1개"
-var reSuccessFailed = regexp.MustCompile(`성공\s*(\d+)개.*실패\s*(\d+)개`)
-
-func countFromMessage(msg string) (total, success, failed int) {
-	m := reSuccessFailed.FindStringSubmatch(msg)
-	if len(m) == 3 {
-		success, _ = strconv.Atoi(m[1])
-		failed, _ = strconv.Atoi(m[2])
-		total = success + failed
+// countItems returns the total/success/failed counts of a traversal from its items
+// (cancelled items count toward total only). A retried task is counted once, by
+// the last item of its phase.
+func countItems(localDB *db.DB, travID int64) (total, success, failed int) {
+	err := localDB.QueryRow(`
+		SELECT COUNT(*),
+			COALESCE(SUM(CASE WHEN result NOT IN ('failed', 'cancelled') THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN result = 'failed' THEN 1 ELSE 0 END), 0)
+		FROM traversal_items
+		WHERE id IN (SELECT MAX(id) FROM traversal_items WHERE traversal_id = ? GROUP BY task_id, phase)
+	`, travID).Scan(&total, &success, &failed)
+	if err != nil {
+		log.Printf("[Task] traversal_items 집계 실패 (id=%d): %v", travID, err)
 	}
 	return
 }
+
+// traversalCtxKey carries the ID of the traversal a task is processed in.
+type traversalCtxKey struct{}
+
+// withTraversal returns ctx carrying the traversal whose items the tasks are logged under.
+func withTraversal(ctx context.Context, travID int64) context.Context {
+	if travID == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, traversalCtxKey{}, travID)
+}
+
+// traversalFromContext returns the traversal carried by ctx (0 if none).
+func traversalFromContext(ctx context.Context) int64 {
+	travID, _ := ctx.Value(traversalCtxKey{}).(int64)
+	return travID
+}
+
+// batchCtxKey marks a batch traversal: per-task commits are suppressed in favor of
+// one batch commit at the end (only for the work done under this ctx, so a manual
+// command in the same project still commits on its own).
+type batchCtxKey struct{}
+
+// withBatch returns ctx in batch mode.
+func withBatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, batchCtxKey{}, true)
+}
+
+// batchFromContext reports whether ctx is in batch mode.
+func batchFromContext(ctx context.Context) bool {
+	batch, _ := ctx.Value(batchCtxKey{}).(bool)
+	return batch
+}
+
+// sharedTreeCtxKey marks runs whose parallel workers share the project working tree.
+type sharedTreeCtxKey struct{}
+
+// withSharedTree returns ctx marking a parallel run without worktree isolation.
+func withSharedTree(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sharedTreeCtxKey{}, true)
+}
+
+// sharedTreeFromContext reports whether other workers edit the same working tree.
+func sharedTreeFromContext(ctx context.Context) bool {
+	shared, _ := ctx.Value(sharedTreeCtxKey{}).(bool)
+	return shared
+}
+
+// itemRecorder records one task processed by a traversal (one traversal_items row).
+type itemRecorder struct {
+	travID   int64
+	taskID   int
+	title    string
+	phase    string
+	start    time.Time
+	outcome  string // result on success: planned, split, review (plan) or done (run)
+	exitCode *int   // Claude exit code, nil if Claude did not finish
+	recorded bool
+}
+
+// startItem starts the item of a task in the traversal carried by ctx (nil outside a traversal).
+func startItem(ctx context.Context, t *Task, phase string) *itemRecorder {
+	travID := traversalFromContext(ctx)
+	if travID == 0 {
+		return nil
+	}
+	return &itemRecorder{travID: travID, taskID: t.ID, title: t.Title, phase: phase, start: time.Now()}
+}
+
+// finish writes the item with the task's result, once (later calls are no-ops).
+func (it *itemRecorder) finish(localDB *db.DB, ret types.Result) {
+	if it == nil || it.recorded {
+		return
+	}
+	it.recorded = true
+
+	result := "done"
+	switch {
+	case ret.Success && it.outcome != "":
+		result = it.outcome
+	case ret.Success:
+	case ret.ErrorType == "cancelled":
+		result = "cancelled"
+	default:
+		result = "failed"
+	}
+	errorType := ret.ErrorType
+	if ret.Success {
+		errorType = ""
+	}
+
+	finished := time.Now()
+	_, err := localDB.Exec(`
+		INSERT INTO traversal_items (traversal_id, task_id, title, phase, result, error_type, exit_code, duration_ms, started_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, it.travID, it.taskID, it.title, it.phase, result, errorType, it.exitCode,
+		finished.Sub(it.start).Milliseconds(), it.start.UTC().Format(time.RFC3339), finished.UTC().Format(time.RFC3339))
+	if err != nil {
+		log.Printf("[Task] traversal_items INSERT 실패 (traversal=%d, #%d): %v", it.travID, it.taskID, err)
+	}
+}
+
+// setExitCode keeps the exit code of the Claude run behind the item.
+func (it *itemRecorder) setExitCode(code int) {
+	if it != nil {
+		it.exitCode = &code
+	}
+}
+
+// setOutcome sets the result recorded when the task succeeds.
+func (it *itemRecorder) setOutcome(outcome string) {
+	if it != nil {
+		it.outcome = outcome
+	}
+}
@@ -0,0 +1,160 @@
+package task
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/internal/types"
+	"parkjunwoo.com/claribot/pkg/pagination"
+)
+
+func TestTraversalItems(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	defer localDB.Close()
+
+	// Outside a traversal nothing is recorded
+	if it := startItem(context.Background(), &Task{ID: 1}, "run"); it != nil {
+		t.Fatal("Expected no item outside a traversal")
+	}
+
+	travID, err := insertTraversal(localDB, "cycle", nil, "")
+	if err != nil {
+		t.Fatalf("insertTraversal failed: %v", err)
+	}
+	ctx := withTraversal(context.Background(), travID)
+
+	split := startItem(ctx, &Task{ID: 1, Title: "Root"}, "plan")
+	split.setExitCode(0)
+	split.setOutcome("split")
+	split.finish(localDB, types.Result{Success: true})
+	split.finish(localDB, types.Result{Success: false}) // recorded once
+
+	done := startItem(ctx, &Task{ID: 2, Title: "Leaf"}, "run")
+	done.finish(localDB, types.Result{Success: true, ErrorType: "ignored"})
+
+	failed := startItem(ctx, &Task{ID: 3, Title: "Broken"}, "run")
+	failed.setExitCode(2)
+	failed.finish(localDB, types.Result{Success: false, ErrorType: "timeout"})
+
+	cancelled := startItem(ctx, &Task{ID: 4, Title: "Slow"}, "run")
+	cancelled.finish(localDB, types.Result{Success: false, ErrorType: "cancelled"})
+
+	total, success, failedCount := countItems(localDB, travID)
+	if total != 4 || success != 2 || failedCount != 1 {
+		t.Errorf("Expected 4/2/1, got %d/%d/%d", total, success, failedCount)
+	}
+	finishTraversal(localDB, travID, "done", total, success, failedCount)
+
+	items, err := loadTraversalItems(localDB, travID)
+	if err != nil {
+		t.Fatalf("loadTraversalItems failed: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("Expected 4 items, got %d", len(items))
+	}
+	want := []struct {
+		taskID    int
+		phase     string
+		result    string
+		errorType string
+	}{
+		{1, "plan", "split", ""},
+		{2, "run", "done", ""},
+		{3, "run", "failed", "timeout"},
+		{4, "run", "cancelled", "cancelled"},
+	}
+	for i, w := range want {
+		it := items[i]
+		if it.TaskID != w.taskID || it.Phase != w.phase || it.Result != w.result || it.ErrorType != w.errorType {
+			t.Errorf("Item %d: expected %+v, got %+v", i, w, it)
+		}
+	}
+	if items[0].ExitCode == nil || *items[0].ExitCode != 0 {
+		t.Errorf("Expected exit code 0 on split item, got %v", items[0].ExitCode)
+	}
+	if items[1].ExitCode != nil {
+		t.Errorf("Expected no exit code when Claude did not finish, got %d", *items[1].ExitCode)
+	}
+	if items[2].ExitCode == nil || *items[2].ExitCode != 2 {
+		t.Errorf("Expected exit code 2 on failed item, got %v", items[2].ExitCode)
+	}
+
+	// A retry that passes replaces the failure in the counts, but both attempts stay listed
+	retried := startItem(ctx, &Task{ID: 3, Title: "Broken"}, "run")
+	retried.finish(localDB, types.Result{Success: true})
+	total, success, failedCount = countItems(localDB, travID)
+	if total != 4 || success != 3 || failedCount != 0 {
+		t.Errorf("Expected 4/3/0 after the retry, got %d/%d/%d", total, success, failedCount)
+	}
+	if items, _ := loadTraversalItems(localDB, travID); len(items) != 5 {
+		t.Errorf("Expected 5 items after the retry, got %d", len(items))
+	}
+}
+
+func TestTraversalHistory(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if result := ListTraversals(projectPath, pagination.NewPageRequest(1, 10)); !result.Success || result.Data != nil {
+		t.Errorf("Expected empty history, got %+v", result)
+	}
+
+	for i := 0; i < 5; i++ {
+		Add(projectPath, "Task", nil, "spec")
+	}
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("OpenLocal failed: %v", err)
+	}
+	rootID := 5
+	firstID, _ := insertTraversal(localDB, "run", &rootID, "")
+	finishTraversal(localDB, firstID, "done", 1, 1, 0)
+	secondID, _ := insertTraversal(localDB, "cycle", nil, "schedule:1")
+	item := startItem(withTraversal(context.Background(), secondID), &Task{ID: 7, Title: "Leaf"}, "run")
+	item.setExitCode(1)
+	item.finish(localDB, types.Result{Success: false, ErrorType: "claude_error"})
+	localDB.Close()
+
+	result := ListTraversals(projectPath, pagination.NewPageRequest(1, 10))
+	if !result.Success {
+		t.Fatalf("ListTraversals failed: %s", result.Message)
+	}
+	page, ok := result.Data.(pagination.PageResponse[Traversal])
+	if !ok {
+		t.Fatalf("Expected PageResponse, got %T", result.Data)
+	}
+	travs := page.Items
+	if len(travs) != 2 || travs[0].ID != secondID || travs[1].ID != firstID {
+		t.Fatalf("Expected most recent first, got %+v", travs)
+	}
+	if !strings.Contains(result.Message, "run #5") || !strings.Contains(result.Message, "[#1:task history 1]") {
+		t.Errorf("Unexpected list message: %s", result.Message)
+	}
+
+	result = GetTraversal(projectPath, "2")
+	if !result.Success {
+		t.Fatalf("GetTraversal failed: %s", result.Message)
+	}
+	tr := result.Data.(Traversal)
+	if tr.Status != "running" || tr.Trigger != "schedule:1" || len(tr.Items) != 1 {
+		t.Fatalf("Unexpected traversal: %+v", tr)
+	}
+	if !strings.Contains(result.Message, "#7") || !strings.Contains(result.Message, "claude_error, exit 1") {
+		t.Errorf("Unexpected detail message: %s", result.Message)
+	}
+
+	if result := GetTraversal(projectPath, "99"); result.Success {
+		t.Error("Expected missing traversal to fail")
+	}
+	if result := GetTraversal(projectPath, "abc"); result.Success {
+		t.Error("Expected invalid ID to fail")
+	}
+}
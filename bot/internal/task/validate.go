@@ -11,6 +11,7 @@ var ValidStatus = map[string]bool{
 	"todo":    true,
 	"split":   true,
 	"planned": true,
+	"review":  true,
 	"done":    true,
 	"failed":  true,
 }
@@ -0,0 +1,267 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"parkjunwoo.com/claribot/internal/db"
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+// VerifyStages are the verification stages, run in this order after a successful task run.
+// Each stage is configured with the project config key "verify_{stage}".
+var VerifyStages = []string{"build", "test", "lint"}
+
+// Verification defaults
+const (
+	defaultVerifyTimeout = 10 * time.Minute
+	maxVerifyOutput      = 8 * 1024 // bytes kept per command (tail)
+)
+
+// VerifyCommand is one configured verification command.
+type VerifyCommand struct {
+	Stage   string
+	Command string
+}
+
+// VerifyConfig is the per-project post-run verification gate.
+type VerifyConfig struct {
+	Commands []VerifyCommand
+	Fix      bool          // run one fix-up Claude run on failure
+	Timeout  time.Duration // per command
+}
+
+// verifyStep is the result of one verification command.
+type verifyStep struct {
+	VerifyCommand
+	ExitCode int
+	Output   string
+	Err      error
+}
+
+// getVerifyConfig reads the verification gate from project local DB config.
+// No commands configured means the gate is disabled.
+func getVerifyConfig(localDB *db.DB) VerifyConfig {
+	config := make(map[string]string)
+	rows, err := localDB.Query(`SELECT key, value FROM config WHERE key LIKE 'verify_%'`)
+	if err == nil {
+		for rows.Next() {
+			var k, v string
+			if rows.Scan(&k, &v) == nil {
+				config[k] = v
+			}
+		}
+		rows.Close()
+	}
+
+	cfg := VerifyConfig{Timeout: defaultVerifyTimeout}
+	for _, stage := range VerifyStages {
+		if cmd := strings.TrimSpace(config["verify_"+stage]); cmd != "" {
+			cfg.Commands = append(cfg.Commands, VerifyCommand{Stage: stage, Command: cmd})
+		}
+	}
+	cfg.Fix = config["verify_fix"] == "true"
+	if v, ok := config["verify_timeout"]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.Timeout = d
+		}
+	}
+	return cfg
+}
+
+// runVerify runs the verification commands in the project directory, stopping at the
+// first failure. Returns the failed step (nil if all passed) and the markdown-formatted output.
+func runVerify(ctx context.Context, projectPath string, cfg VerifyConfig) (*verifyStep, string) {
+	var sb strings.Builder
+	for _, c := range cfg.Commands {
+		step := runVerifyCommand(ctx, projectPath, c, cfg.Timeout)
+		sb.WriteString(formatVerifyStep(step))
+		if step.failed() {
+			return &step, sb.String()
+		}
+	}
+	return nil, sb.String()
+}
+
+// failed reports whether the command exited non-zero or could not run.
+func (s verifyStep) failed() bool {
+	return s.Err != nil || s.ExitCode != 0
+}
+
+// status returns "passed", "exit N" or the execution error.
+func (s verifyStep) status() string {
+	if s.Err != nil {
+		return s.Err.Error()
+	}
+	if s.ExitCode != 0 {
+		return fmt.Sprintf("exit %d", s.ExitCode)
+	}
+	return "passed"
+}
+
+// runVerifyCommand runs one command with sh -c and captures its combined output.
+func runVerifyCommand(ctx context.Context, projectPath string, c VerifyCommand, timeout time.Duration) verifyStep {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	cmd.Dir = projectPath
+	// Child processes (e.g. test binaries) may hold the output pipe after sh is killed
+	cmd.WaitDelay = 5 * time.Second
+	out, err := cmd.CombinedOutput()
+
+	step := verifyStep{VerifyCommand: c, Output: tailBytes(string(out), maxVerifyOutput)}
+	if exitErr, ok := err.(*exec.ExitError); ok && ctx.Err() == nil {
+		step.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		step.ExitCode = -1
+		step.Err = err
+		if ctx.Err() == context.DeadlineExceeded {
+			step.Err = fmt.Errorf("timeout after %s", timeout)
+		}
+	}
+	return step
+}
+
+// formatVerifyStep renders a verification step as a markdown section.
+func formatVerifyStep(s verifyStep) string {
+	icon := "✅"
+	if s.failed() {
+		icon = "❌"
+	}
+	out := strings.TrimRight(s.Output, "\n")
+	if out == "" {
+		out = "(출력 없음)"
+	}
+	return fmt.Sprintf("### %s %s: `%s` (%s)\n\n```\n%s\n```\n\n", icon, s.Stage, s.Command, s.status(), out)
+}
+
+// tailBytes keeps the last max bytes of s (the end of build/test output matters most).
+// The cut moves forward to a rune boundary so multi-byte characters are not split.
+func tailBytes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := len(s) - max
+	for cut < len(s) && !utf8.RuneStart(s[cut]) {
+		cut++
+	}
+	return "...(앞부분 생략)\n" + s[cut:]
+}
+
+// verifyTask runs the verification gate after a successful run and, if configured,
+// one fix-up run fed with the failure output. The output of every round is saved to
+// {id}.verify.md, and a Claude attempt whose result fails verification is marked failed.
+// Commands and the fix-up run use workDir (the task's worktree when isolated).
+// Returns nil if the task passed, otherwise the failed step and the error type.
+func verifyTask(ctx context.Context, localDB *db.DB, projectPath, workDir string, t *Task, cfg VerifyConfig, attemptID int64, contextMap, upstream, reportPath string) (*verifyStep, string) {
+	failed, output := runVerify(ctx, workDir, cfg)
+	content := "## 검증\n\n" + output
+	errorType := ""
+
+	if failed != nil {
+		failVerifiedAttempt(localDB, attemptID, failed)
+		if cfg.Fix && ctx.Err() == nil {
+			log.Printf("[Task] 검증 실패, 수정 실행 시작 (#%d)", t.ID)
+			var fixID int64
+			fixID, errorType = runFixUp(ctx, localDB, projectPath, workDir, t, contextMap, upstream, output, reportPath)
+			if errorType == "" {
+				failed, output = runVerify(ctx, workDir, cfg)
+				content += "## 수정 후 재검증\n\n" + output
+				if failed != nil {
+					failVerifiedAttempt(localDB, fixID, failed)
+				}
+			} else {
+				content += fmt.Sprintf("## 수정 실행 실패\n\n%s\n", errorType)
+			}
+		}
+	}
+
+	if err := WriteVerifyContent(projectPath, t.ID, content); err != nil {
+		log.Printf("[Task] 검증 파일 생성 실패 (#%d): %v", t.ID, err)
+	}
+	if failed == nil {
+		return nil, ""
+	}
+	// Auth/cancel from the fix-up run take precedence so traversals react to them
+	if errorType == "auth_error" || errorType == "cancelled" {
+		return failed, errorType
+	}
+	return failed, "verify_failed"
+}
+
+// runFixUp runs Claude once more with the verification failure in the prompt.
+// The new report replaces the previous one. Returns the attempt ID and, on failure, the error type.
+func runFixUp(ctx context.Context, localDB *db.DB, projectPath, workDir string, t *Task, contextMap, upstream, verifyOutput, reportPath string) (int64, string) {
+	// The previous run's report must not be mistaken for the fix-up's
+	if err := os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
+		log.Printf("[Task] Run report 파일 삭제 실패 (task #%d): %v", t.ID, err)
+	}
+
+	opts := claude.Options{
+		UserPrompt: BuildFixPrompt(t, contextMap, upstream, verifyOutput, reportPath),
+		WorkDir:    workDir,
+		ReportPath: reportPath,
+		Origin:     claude.Origin{Project: projectPath, TaskID: t.ID},
+	}
+	resolveRunOptions(localDB, projectPath, t.ID).apply(&opts)
+
+	opts.RunID = claude.NewRunID()
+	attemptID, _, attemptErr := startAttempt(localDB, t.ID, "run", opts.RunID)
+	if attemptErr != nil {
+		log.Printf("[Task] attempt INSERT 실패 (#%d): %v", t.ID, attemptErr)
+	}
+
+	result, err := claude.RunContext(ctx, opts)
+	if err != nil {
+		errorType := classifyRunError(ctx, err)
+		if attemptErr == nil {
+			status := "failed"
+			if errorType == "cancelled" {
+				status = "cancelled"
+			}
+			finishAttempt(localDB, projectPath, attemptID, status, nil, errorType, err.Error(), "")
+		}
+		return attemptID, errorType
+	}
+
+	exitCode := result.ExitCode
+	if result.ExitCode != 0 {
+		errorType := "exit_error"
+		if claude.IsAuthError(result) {
+			errorType = "auth_error"
+		}
+		if attemptErr == nil {
+			finishAttempt(localDB, projectPath, attemptID, "failed", &exitCode, errorType, truncateLine(result.Output, 200), result.Output)
+		}
+		return attemptID, errorType
+	}
+
+	if attemptErr == nil {
+		finishAttempt(localDB, projectPath, attemptID, "done", &exitCode, "", "", result.Output)
+	}
+	if err := WriteReportContent(projectPath, t.ID, result.Output); err != nil {
+		log.Printf("[Task] report 파일 생성 실패 (#%d): %v", t.ID, err)
+	}
+	return attemptID, ""
+}
+
+// failVerifiedAttempt marks a Claude attempt whose result did not pass verification.
+func failVerifiedAttempt(localDB *db.DB, attemptID int64, step *verifyStep) {
+	if attemptID == 0 {
+		return
+	}
+	msg := fmt.Sprintf("%s: %s (%s)", step.Stage, step.Command, step.status())
+	if _, err := localDB.Exec(`
+		UPDATE task_attempts SET status = 'failed', error_type = 'verify_failed', error = ?
+		WHERE id = ?
+	`, msg, attemptID); err != nil {
+		log.Printf("[Task] attempt 갱신 실패 (id=%d): %v", attemptID, err)
+	}
+}
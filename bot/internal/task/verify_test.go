@@ -0,0 +1,115 @@
+package task
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+func TestGetVerifyConfig(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer localDB.Close()
+
+	if cfg := getVerifyConfig(localDB); len(cfg.Commands) != 0 || cfg.Fix || cfg.Timeout != defaultVerifyTimeout {
+		t.Errorf("Expected disabled gate by default, got %+v", cfg)
+	}
+
+	for k, v := range map[string]string{"verify_lint": "go vet ./...", "verify_build": "go build ./...", "verify_test": "", "verify_fix": "true", "verify_timeout": "30s"} {
+		localDB.Exec("INSERT OR REPLACE INTO config (key, value, updated_at) VALUES (?, ?, ?)", k, v, db.TimeNow())
+	}
+	cfg := getVerifyConfig(localDB)
+	if len(cfg.Commands) != 2 || cfg.Commands[0].Stage != "build" || cfg.Commands[1].Stage != "lint" {
+		t.Errorf("Expected build then lint, got %+v", cfg.Commands)
+	}
+	if !cfg.Fix || cfg.Timeout != 30*time.Second {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+func TestRunVerify(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cfg := VerifyConfig{
+		Commands: []VerifyCommand{
+			{Stage: "build", Command: "echo built"},
+			{Stage: "test", Command: "echo FAIL: TestX; exit 3"},
+			{Stage: "lint", Command: "echo never"},
+		},
+		Timeout: 10 * time.Second,
+	}
+	failed, output := runVerify(context.Background(), projectPath, cfg)
+	if failed == nil || failed.Stage != "test" || failed.ExitCode != 3 {
+		t.Fatalf("Expected test stage to fail with exit 3, got %+v", failed)
+	}
+	if !strings.Contains(output, "built") || !strings.Contains(output, "FAIL: TestX") || strings.Contains(output, "never") {
+		t.Errorf("Unexpected output: %s", output)
+	}
+
+	// Commands run in the project directory
+	cfg.Commands = []VerifyCommand{{Stage: "build", Command: "test -d .claribot"}}
+	if failed, output := runVerify(context.Background(), projectPath, cfg); failed != nil {
+		t.Errorf("Expected pass in project dir, got %s", output)
+	}
+
+	cfg = VerifyConfig{Commands: []VerifyCommand{{Stage: "test", Command: "exec sleep 5"}}, Timeout: 50 * time.Millisecond}
+	if failed, _ := runVerify(context.Background(), projectPath, cfg); failed == nil || !strings.Contains(failed.status(), "timeout") {
+		t.Errorf("Expected timeout, got %+v", failed)
+	}
+}
+
+func TestVerifyTaskRecordsFailure(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Break the build", nil, "spec")
+	localDB, err := db.OpenLocal(projectPath)
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer localDB.Close()
+
+	attemptID, _, _ := startAttempt(localDB, 1, "run", "")
+	exitCode := 0
+	finishAttempt(localDB, projectPath, attemptID, "done", &exitCode, "", "", "report")
+
+	task := &Task{ID: 1, Title: "Break the build"}
+	cfg := VerifyConfig{Commands: []VerifyCommand{{Stage: "build", Command: "echo undefined: Foo; exit 1"}}, Timeout: 10 * time.Second}
+	failed, errorType := verifyTask(context.Background(), localDB, projectPath, projectPath, task, cfg, attemptID, "", "", "")
+	if failed == nil || errorType != "verify_failed" {
+		t.Fatalf("Expected verify_failed, got %+v %s", failed, errorType)
+	}
+
+	// Output is saved next to the report
+	content, _ := ReadVerifyContent(projectPath, 1)
+	if !strings.Contains(content, "undefined: Foo") {
+		t.Errorf("Expected verify file with command output, got %q", content)
+	}
+
+	// The Claude attempt that produced the result is marked failed
+	attempts, _ := loadAttempts(localDB, 1)
+	if len(attempts) != 1 || attempts[0].Status != "failed" || attempts[0].ErrorType != "verify_failed" {
+		t.Errorf("Expected attempt marked verify_failed, got %+v", attempts)
+	}
+}
+
+func TestTailBytes(t *testing.T) {
+	if got := tailBytes("short", 10); got != "short" {
+		t.Errorf("Expected short text unchanged, got %q", got)
+	}
+	// "빌드 실패" is 13 bytes: keeping the last 8 would start inside "드"
+	got := tailBytes("빌드 실패", 8)
+	if !utf8.ValidString(got) || got != "...(앞부분 생략)\n 실패" {
+		t.Errorf("Expected cut at a rune boundary, got %q", got)
+	}
+}
@@ -0,0 +1,214 @@
+package task
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"parkjunwoo.com/claribot/pkg/claude"
+)
+
+// watchDebounce is how long the watcher waits for further changes before syncing.
+var watchDebounce = 500 * time.Millisecond
+
+// watchHold is how often a sync held back by a running traversal checks again.
+// The traversal commits task files in one batch; a sync commit in between would sweep them in.
+var watchHold = 2 * time.Second
+
+// watchFileRe matches the task files the watcher reacts to: {id}.md, {id}.plan.md, {id}.report.md, {id}.error.md
+var watchFileRe = regexp.MustCompile(`^(\d+)(\.(plan|report|error))?\.md$`)
+
+// dirWatcher reports the names of files changed in one directory.
+type dirWatcher interface {
+	Events() <-chan string // closed after Close
+	Close() error
+}
+
+// selfWrites holds the hash of the last content claribot wrote to each task file,
+// so the watcher can tell its own writes from external edits.
+var selfWrites = struct {
+	sync.Mutex
+	m map[string][sha256.Size]byte
+}{m: make(map[string][sha256.Size]byte)}
+
+// writeTaskFile writes a file in the task directory and records it as a claribot write.
+func writeTaskFile(path string, content string) error {
+	selfWrites.Lock()
+	selfWrites.m[path] = sha256.Sum256([]byte(content))
+	selfWrites.Unlock()
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// isSelfWrite reports whether data is what claribot last wrote to path.
+func isSelfWrite(path string, data []byte) bool {
+	selfWrites.Lock()
+	defer selfWrites.Unlock()
+	sum, ok := selfWrites.m[path]
+	return ok && sum == sha256.Sum256(data)
+}
+
+// projectWatcher syncs one project's task directory on external changes.
+type projectWatcher struct {
+	projectPath string
+	w           dirWatcher
+	done        chan struct{}
+}
+
+// watchers holds the running watchers by project path.
+var watchers = struct {
+	sync.Mutex
+	m map[string]*projectWatcher
+}{m: make(map[string]*projectWatcher)}
+
+// Watch starts watching the project's task directory. Changed task files are synced
+// to the DB after a short debounce; claribot's own writes are ignored.
+// Calling Watch for an already watched project is a no-op.
+func Watch(projectPath string) error {
+	watchers.Lock()
+	defer watchers.Unlock()
+	if _, ok := watchers.m[projectPath]; ok {
+		return nil
+	}
+
+	if err := EnsureTaskDir(projectPath); err != nil {
+		return err
+	}
+	w, err := newDirWatcher(TaskDir(projectPath))
+	if err != nil {
+		return fmt.Errorf("watch task dir: %w", err)
+	}
+
+	pw := &projectWatcher{projectPath: projectPath, w: w, done: make(chan struct{})}
+	watchers.m[projectPath] = pw
+	go pw.run()
+	log.Printf("[Watch] 작업 파일 감시 시작: %s", TaskDir(projectPath))
+	return nil
+}
+
+// Unwatch stops watching the project's task directory. Pending changes are dropped.
+func Unwatch(projectPath string) {
+	watchers.Lock()
+	pw := watchers.m[projectPath]
+	delete(watchers.m, projectPath)
+	watchers.Unlock()
+
+	if pw != nil {
+		pw.stop()
+	}
+}
+
+// StopWatchers stops all watchers (called on shutdown).
+func StopWatchers() {
+	watchers.Lock()
+	all := watchers.m
+	watchers.m = make(map[string]*projectWatcher)
+	watchers.Unlock()
+
+	for _, pw := range all {
+		pw.stop()
+	}
+}
+
+func (pw *projectWatcher) stop() {
+	pw.w.Close()
+	<-pw.done
+}
+
+// run collects changed files until no event arrived for watchDebounce, then syncs them
+// (once no traversal or scheduled run is going on in the project).
+func (pw *projectWatcher) run() {
+	defer close(pw.done)
+
+	pending := make(map[string]int) // file path → task ID
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case name, ok := <-pw.w.Events():
+			if !ok {
+				return
+			}
+			m := watchFileRe.FindStringSubmatch(name)
+			if m == nil {
+				continue
+			}
+			id, err := strconv.Atoi(m[1])
+			if err != nil {
+				continue
+			}
+			pending[filepath.Join(TaskDir(pw.projectPath), name)] = id
+			timer.Reset(watchDebounce)
+		case <-timer.C:
+			if IsCycleRunning(pw.projectPath) || scheduledRunLive(pw.projectPath) {
+				timer.Reset(watchHold)
+				continue
+			}
+			pw.flush(pending)
+			pending = make(map[string]int)
+		}
+	}
+}
+
+// scheduledRunLive reports whether a scheduled Claude run of the project is still
+// listed as live (until liveKeep after it ended): the scheduler syncs its edits itself.
+func scheduledRunLive(projectPath string) bool {
+	for _, run := range claude.GetLiveRuns() {
+		if run.Origin.ScheduleID != 0 && run.Origin.Project == projectPath {
+			return true
+		}
+	}
+	return false
+}
+
+// flush syncs the tasks whose files changed outside claribot and reports problems to the notifier.
+func (pw *projectWatcher) flush(pending map[string]int) {
+	seen := make(map[int]bool)
+	var ids []int
+	for path, id := range pending {
+		if data, err := os.ReadFile(path); err == nil && isSelfWrite(path, data) {
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	sort.Ints(ids)
+
+	result, err := SyncTasks(pw.projectPath, ids)
+	if err != nil {
+		log.Printf("[Watch] 동기화 실패 (%s): %v", pw.projectPath, err)
+		pw.notify([]string{fmt.Sprintf("동기화 실패: %v", err)})
+		return
+	}
+	log.Printf("[Watch] 동기화 (%s) %v: 추가 %d, 수정 %d, 삭제 %d, 복구 %d, 건너뜀 %d",
+		pw.projectPath, ids, result.Inserted, result.Updated, result.Deleted, result.Restored, result.Skipped)
+	if len(result.Warnings) > 0 {
+		pw.notify(result.Warnings)
+	}
+}
+
+func (pw *projectWatcher) notify(lines []string) {
+	if globalNotifier == nil {
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⚠️ 작업 파일 감시 [%s]\n", filepath.Base(pw.projectPath)))
+	for _, line := range lines {
+		sb.WriteString("  - " + line + "\n")
+	}
+	globalNotifier(nil, strings.TrimRight(sb.String(), "\n"))
+}
@@ -0,0 +1,68 @@
+//go:build linux
+
+package task
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyMask covers finished writes, renames (editors, git checkout) and deletions.
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_DELETE
+
+// inotifyWatcher is a dirWatcher backed by inotify.
+type inotifyWatcher struct {
+	file   *os.File
+	events chan string
+}
+
+func newDirWatcher(dir string) (dirWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init: %w", err)
+	}
+	if _, err := syscall.InotifyAddWatch(fd, dir, inotifyMask); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("inotify add watch: %w", err)
+	}
+
+	// A non-blocking fd wrapped in os.File goes through the runtime poller,
+	// so Close unblocks a pending Read.
+	w := &inotifyWatcher{
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan string, 64),
+	}
+	go w.readLoop()
+	return w, nil
+}
+
+func (w *inotifyWatcher) Events() <-chan string { return w.events }
+
+func (w *inotifyWatcher) Close() error { return w.file.Close() }
+
+func (w *inotifyWatcher) readLoop() {
+	defer close(w.events)
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			start := off + syscall.SizeofInotifyEvent
+			end := start + int(ev.Len)
+			if end > n {
+				break
+			}
+			if name := strings.TrimRight(string(buf[start:end]), "\x00"); name != "" {
+				w.events <- name
+			}
+			off = end
+		}
+	}
+}
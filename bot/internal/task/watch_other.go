@@ -0,0 +1,9 @@
+//go:build !linux
+
+package task
+
+import "errors"
+
+func newDirWatcher(dir string) (dirWatcher, error) {
+	return nil, errors.New("파일 감시는 linux에서만 지원됩니다")
+}
@@ -0,0 +1,145 @@
+//go:build linux
+
+package task
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// editTitle rewrites a task file's title the way an editor would (outside claribot).
+func editTitle(t *testing.T, projectPath string, id int, title string) {
+	t.Helper()
+	tc, err := ReadTaskContent(projectPath, id)
+	if err != nil {
+		t.Fatalf("ReadTaskContent failed: %v", err)
+	}
+	content := FormatFrontmatter(tc.Frontmatter, title, tc.Body)
+	if err := os.WriteFile(TaskFilePath(projectPath, id), []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
+
+func dbTitle(t *testing.T, projectPath string, id int) string {
+	t.Helper()
+	result := Get(projectPath, strconv.Itoa(id))
+	if !result.Success {
+		t.Fatalf("Get failed: %s", result.Message)
+	}
+	return result.Data.(*Task).Title
+}
+
+// waitFor polls cond until it holds or the timeout expires.
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestSyncTasks(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	Add(projectPath, "Billing", nil, "spec")
+	editTitle(t, projectPath, 1, "Auth v2")
+	editTitle(t, projectPath, 2, "Billing v2")
+
+	result, err := SyncTasks(projectPath, []int{1})
+	if err != nil {
+		t.Fatalf("SyncTasks failed: %v", err)
+	}
+	if result.Updated != 1 {
+		t.Errorf("Expected 1 update, got %d", result.Updated)
+	}
+	if got := dbTitle(t, projectPath, 1); got != "Auth v2" {
+		t.Errorf("Expected #1 synced, got %q", got)
+	}
+	if got := dbTitle(t, projectPath, 2); got != "Billing" {
+		t.Errorf("Expected #2 untouched, got %q", got)
+	}
+}
+
+func TestSelfWrites(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	Add(projectPath, "Auth", nil, "spec")
+	path := TaskFilePath(projectPath, 1)
+	data, _ := os.ReadFile(path)
+	if !isSelfWrite(path, data) {
+		t.Error("Expected claribot write to be recognized")
+	}
+
+	editTitle(t, projectPath, 1, "Auth v2")
+	data, _ = os.ReadFile(path)
+	if isSelfWrite(path, data) {
+		t.Error("Expected external edit not to be recognized as claribot write")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	oldDebounce, oldHold, oldNotifier := watchDebounce, watchHold, globalNotifier
+	defer func() { watchDebounce, watchHold, globalNotifier = oldDebounce, oldHold, oldNotifier }()
+	watchDebounce = 50 * time.Millisecond
+	watchHold = 50 * time.Millisecond
+	notes := make(chan string, 10)
+	globalNotifier = func(projectID *string, msg string) { notes <- msg }
+
+	Add(projectPath, "Auth", nil, "spec")
+	if err := Watch(projectPath); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	defer Unwatch(projectPath)
+
+	// claribot's own writes don't trigger a sync
+	Set(projectPath, "1", "priority", "3", ActorCLI)
+	time.Sleep(4 * watchDebounce)
+	select {
+	case msg := <-notes:
+		t.Errorf("Unexpected notification: %s", msg)
+	default:
+	}
+
+	// An external edit reaches the DB
+	editTitle(t, projectPath, 1, "Auth v2")
+	if !waitFor(3*time.Second, func() bool { return dbTitle(t, projectPath, 1) == "Auth v2" }) {
+		t.Fatalf("Expected watcher to sync the edited title, got %q", dbTitle(t, projectPath, 1))
+	}
+
+	// While a traversal runs the sync waits, so its batch commit is not swept in
+	SetCycleState(projectPath, CycleState{Running: true, Type: "cycle"})
+	editTitle(t, projectPath, 1, "Auth v3")
+	time.Sleep(6 * watchDebounce)
+	if got := dbTitle(t, projectPath, 1); got != "Auth v2" {
+		t.Errorf("Expected sync held during the traversal, got %q", got)
+	}
+	ClearCycleState(projectPath)
+	if !waitFor(3*time.Second, func() bool { return dbTitle(t, projectPath, 1) == "Auth v3" }) {
+		t.Fatalf("Expected held sync after the traversal, got %q", dbTitle(t, projectPath, 1))
+	}
+
+	// An invalid file is reported to the notifier
+	if err := os.WriteFile(TaskFilePath(projectPath, 2), []byte("no frontmatter"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	select {
+	case msg := <-notes:
+		if !strings.Contains(msg, "#2") || !strings.Contains(msg, "frontmatter") {
+			t.Errorf("Unexpected notification: %s", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("Expected notification for invalid task file")
+	}
+}
@@ -0,0 +1,173 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"parkjunwoo.com/claribot/internal/db"
+)
+
+// worktreeDirName is the directory (under .claribot) holding per-task worktrees.
+const worktreeDirName = "worktrees"
+
+// getWorktreeMode reads the worktree isolation config from project local DB.
+// When enabled, parallel workers run in their own git worktree on a per-task branch.
+func getWorktreeMode(localDB *db.DB) bool {
+	var val string
+	if err := localDB.QueryRow("SELECT value FROM config WHERE key = 'worktree'").Scan(&val); err != nil {
+		return false
+	}
+	return val == "true"
+}
+
+// worktreeBranch returns the per-task branch name.
+func worktreeBranch(taskID int) string {
+	return fmt.Sprintf("claribot/task-%d", taskID)
+}
+
+// worktreePath returns the worktree directory of a task.
+func worktreePath(projectPath string, taskID int) string {
+	return filepath.Join(projectPath, ".claribot", worktreeDirName, fmt.Sprintf("%d", taskID))
+}
+
+// runGit runs a git command and returns its trimmed combined output.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.CombinedOutput()
+	outStr := strings.TrimSpace(string(out))
+	if err != nil {
+		return outStr, fmt.Errorf("git %s failed: %s: %w", args[0], outStr, err)
+	}
+	return outStr, nil
+}
+
+// createWorktree checks out a new branch for the task at the current HEAD in
+// .claribot/worktrees/{id}. Leftovers of an interrupted run are removed first;
+// a branch left with unmerged work (kept after a failed merge) is renamed, not deleted.
+func createWorktree(projectPath string, taskID int) (string, error) {
+	gitMu.Lock()
+	defer gitMu.Unlock()
+
+	dir := filepath.Join(projectPath, ".claribot", worktreeDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("worktree 디렉토리 생성 실패: %w", err)
+	}
+	// Keep worktrees out of the main tree's status
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		os.WriteFile(ignore, []byte("*\n"), 0644)
+	}
+
+	path := worktreePath(projectPath, taskID)
+	branch := worktreeBranch(taskID)
+	runGit(projectPath, "worktree", "remove", "--force", path)
+	runGit(projectPath, "worktree", "prune")
+	if kept := keepUnmergedBranch(projectPath, branch); kept != "" {
+		log.Printf("[Task] 병합되지 않은 브랜치 보존 (#%d): %s → %s", taskID, branch, kept)
+	} else {
+		runGit(projectPath, "branch", "-D", branch)
+	}
+
+	if _, err := runGit(projectPath, "worktree", "add", "-b", branch, path, "HEAD"); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// keepUnmergedBranch renames branch to the first free {branch}-{n} when it has
+// commits not merged into HEAD, and returns the new name ("" = nothing to keep).
+func keepUnmergedBranch(projectPath, branch string) string {
+	if _, err := runGit(projectPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		return ""
+	}
+	if _, err := runGit(projectPath, "merge-base", "--is-ancestor", branch, "HEAD"); err == nil {
+		return ""
+	}
+	for n := 1; ; n++ {
+		kept := fmt.Sprintf("%s-%d", branch, n)
+		if _, err := runGit(projectPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+kept); err == nil {
+			continue
+		}
+		if _, err := runGit(projectPath, "branch", "-m", branch, kept); err != nil {
+			log.Printf("[Task] 브랜치 이름 변경 실패 (%s): %v", branch, err)
+			return ""
+		}
+		return kept
+	}
+}
+
+// commitWorktree commits all changes made in the task's worktree.
+// Returns false if there was nothing to commit.
+func commitWorktree(path string, taskID int, title string) (bool, error) {
+	gitMu.Lock()
+	defer gitMu.Unlock()
+
+	if _, err := runGit(path, "add", "-A"); err != nil {
+		return false, err
+	}
+	if out, _ := runGit(path, "status", "--porcelain"); out == "" {
+		return false, nil
+	}
+	if _, err := runGit(path, "commit", "-m", fmt.Sprintf("task(#%d): %s", taskID, title)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// mergeWorktree merges the task branch into the branch checked out in the main tree.
+// On conflict the merge is aborted and the conflicting files are returned.
+func mergeWorktree(projectPath string, taskID int, title string) ([]string, error) {
+	gitMu.Lock()
+	defer gitMu.Unlock()
+
+	branch := worktreeBranch(taskID)
+	msg := fmt.Sprintf("task(#%d): merge %s", taskID, title)
+	out, err := runGit(projectPath, "merge", "--no-ff", "--no-edit", "-m", msg, branch)
+	if err == nil {
+		return nil, nil
+	}
+
+	conflicts, _ := runGit(projectPath, "diff", "--name-only", "--diff-filter=U")
+	if _, abortErr := runGit(projectPath, "merge", "--abort"); abortErr != nil {
+		log.Printf("[Task] merge --abort 실패 (#%d): %v", taskID, abortErr)
+	}
+	if conflicts != "" {
+		return strings.Split(conflicts, "\n"), fmt.Errorf("merge conflict: %s", out)
+	}
+	return nil, err
+}
+
+// removeWorktree removes the task's worktree. The branch is deleted unless
+// keepBranch is set (the work is kept for inspection after a failed merge).
+func removeWorktree(projectPath string, taskID int, keepBranch bool) {
+	gitMu.Lock()
+	defer gitMu.Unlock()
+
+	if _, err := runGit(projectPath, "worktree", "remove", "--force", worktreePath(projectPath, taskID)); err != nil {
+		log.Printf("[Task] worktree 삭제 실패 (#%d): %v", taskID, err)
+	}
+	if !keepBranch {
+		if _, err := runGit(projectPath, "branch", "-D", worktreeBranch(taskID)); err != nil {
+			log.Printf("[Task] 브랜치 삭제 실패 (#%d): %v", taskID, err)
+		}
+	}
+}
+
+// formatMergeConflict builds the error content for a task whose branch could not be merged.
+func formatMergeConflict(taskID int, conflicts []string, err error) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Merge 충돌\n\n브랜치 `%s`를 병합하지 못했습니다. 작업 내용은 브랜치에 보존됩니다.\n\n", worktreeBranch(taskID)))
+	if len(conflicts) > 0 {
+		sb.WriteString("### 충돌 파일\n\n")
+		for _, f := range conflicts {
+			sb.WriteString(fmt.Sprintf("- `%s`\n", f))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(fmt.Sprintf("### Git 출력\n\n```\n%v\n```\n", err))
+	return sb.String()
+}
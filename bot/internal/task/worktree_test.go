@@ -0,0 +1,139 @@
+package task
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupGitRepo initializes a git repository with one commit in projectPath.
+func setupGitRepo(t *testing.T, projectPath string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+	} {
+		if _, err := runGit(projectPath, args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	os.WriteFile(filepath.Join(projectPath, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(projectPath, ".gitignore"), []byte(".claribot/db.clt*\n"), 0644)
+	if _, err := runGit(projectPath, "add", "-A"); err != nil {
+		t.Fatalf("git add failed: %v", err)
+	}
+	if _, err := runGit(projectPath, "commit", "-qm", "init"); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+}
+
+func TestWorktreeMerge(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	setupGitRepo(t, projectPath)
+
+	wt, err := createWorktree(projectPath, 7)
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(wt, "feature.go"), []byte("package main\n"), 0644)
+
+	// Edits stay in the worktree until merged
+	if _, err := os.Stat(filepath.Join(projectPath, "feature.go")); err == nil {
+		t.Fatal("Worktree edit leaked into the main tree")
+	}
+
+	changed, err := commitWorktree(wt, 7, "Add feature")
+	if err != nil || !changed {
+		t.Fatalf("commitWorktree failed: changed=%v err=%v", changed, err)
+	}
+	if conflicts, err := mergeWorktree(projectPath, 7, "Add feature"); err != nil {
+		t.Fatalf("mergeWorktree failed: %v %v", conflicts, err)
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, "feature.go")); err != nil {
+		t.Error("Expected merged file in the main tree")
+	}
+
+	removeWorktree(projectPath, 7, false)
+	if _, err := os.Stat(wt); !os.IsNotExist(err) {
+		t.Error("Expected worktree to be removed")
+	}
+	if out, _ := runGit(projectPath, "branch", "--list", worktreeBranch(7)); out != "" {
+		t.Errorf("Expected branch to be deleted, got %q", out)
+	}
+
+	// The worktree directory never shows up in the main tree's status
+	if out, _ := runGit(projectPath, "status", "--porcelain", ".claribot/worktrees"); out != "" {
+		t.Errorf("Expected worktrees to be ignored, got %q", out)
+	}
+}
+
+func TestWorktreeMergeConflict(t *testing.T) {
+	projectPath, cleanup := setupTestDB(t)
+	defer cleanup()
+	setupGitRepo(t, projectPath)
+
+	wt, err := createWorktree(projectPath, 3)
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(wt, "main.go"), []byte("package worktree\n"), 0644)
+	if _, err := commitWorktree(wt, 3, "Edit main"); err != nil {
+		t.Fatalf("commitWorktree failed: %v", err)
+	}
+
+	// Another worker changed the same file in the main tree meanwhile
+	os.WriteFile(filepath.Join(projectPath, "main.go"), []byte("package other\n"), 0644)
+	runGit(projectPath, "commit", "-qam", "other worker")
+
+	conflicts, err := mergeWorktree(projectPath, 3, "Edit main")
+	if err == nil {
+		t.Fatal("Expected merge conflict")
+	}
+	if len(conflicts) != 1 || conflicts[0] != "main.go" {
+		t.Errorf("Expected conflict on main.go, got %v", conflicts)
+	}
+
+	// The merge is aborted: main tree is left clean
+	if out, _ := runGit(projectPath, "status", "--porcelain", "main.go"); out != "" {
+		t.Errorf("Expected aborted merge, got status %q", out)
+	}
+	if msg := formatMergeConflict(3, conflicts, err); !strings.Contains(msg, "main.go") || !strings.Contains(msg, "claribot/task-3") {
+		t.Errorf("Unexpected conflict details: %s", msg)
+	}
+
+	// Failed work is kept on the branch
+	removeWorktree(projectPath, 3, true)
+	if out, _ := runGit(projectPath, "branch", "--list", worktreeBranch(3)); out == "" {
+		t.Error("Expected branch to be kept after conflict")
+	}
+
+	// A retry starts a fresh branch and keeps the unmerged one under another name
+	if _, err := createWorktree(projectPath, 3); err != nil {
+		t.Fatalf("createWorktree on retry failed: %v", err)
+	}
+	if out, _ := runGit(projectPath, "log", "-1", "--format=%s", "claribot/task-3-1"); out != "task(#3): Edit main" {
+		t.Errorf("Expected failed work kept on claribot/task-3-1, got %q", out)
+	}
+	if out, _ := runGit(projectPath, "log", "-1", "--format=%s", worktreeBranch(3)); out != "other worker" {
+		t.Errorf("Expected new branch at HEAD, got %q", out)
+	}
+	removeWorktree(projectPath, 3, false)
+
+	// A merged branch is simply replaced
+	if _, err := createWorktree(projectPath, 3); err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	removeWorktree(projectPath, 3, true)
+	if _, err := createWorktree(projectPath, 3); err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	if out, _ := runGit(projectPath, "branch", "--list", "claribot/task-3-2"); out != "" {
+		t.Errorf("Expected no copy of a merged branch, got %q", out)
+	}
+}
@@ -171,6 +171,13 @@ func (h *Handler) projectStatusHeader() string {
 	return "📌 글로벌 모드"
 }
 
+// snapshot returns the router context for a command sent by a Telegram user.
+func (h *Handler) snapshot(userID int64) *handler.Context {
+	ctx := h.router.SnapshotContext()
+	ctx.Actor = task.TelegramActor(userID)
+	return ctx
+}
+
 // sendResult sends a result message, converting [name:value] to buttons
 func (h *Handler) sendResult(chatID int64, result types.Result) {
 	cleanMsg, buttons := parseButtons(result.Message)
@@ -229,12 +236,13 @@ func (h *Handler) cleanExpiredContexts() {
 
 // quickCommands are commands that don't require Claude execution (fast response)
 var quickCommands = []string{
-	"project", "task list", "task get", "task stop",
+	"project", "task list", "task get", "task diff", "task events", "task tail", "task stop", "task cancel", "task history", "task query", "task search", "task move", "task approve", "task reject",
 	"spec",
 	"message list", "message get", "message status",
 	"schedule list", "schedule get", "schedule runs", "schedule run",
 	"status",
 	"usage",
+	"queue",
 }
 
 // needsClaudeExecution checks if a command requires Claude execution
@@ -246,7 +254,7 @@ func needsClaudeExecution(cmd string) bool {
 	}
 	// Commands that run Claude
 	claudeCommands := []string{
-		"task plan", "task run", "task cycle",
+		"task plan", "task replan", "task run", "task cycle",
 		"message send", "send",
 	}
 	for _, cc := range claudeCommands {
@@ -295,7 +303,7 @@ func (h *Handler) HandleMessage(msg telegram.Message) {
 		}
 
 		// Quick commands: synchronous processing
-		snapshot := h.router.SnapshotContext()
+		snapshot := h.snapshot(msg.UserID)
 		if !needsClaudeExecution(cmd) {
 			result := h.router.Execute(snapshot, cmd)
 			h.sendResult(msg.ChatID, result)
@@ -329,7 +337,7 @@ func (h *Handler) HandleMessage(msg telegram.Message) {
 
 	if ok {
 		cmd := entry.context + " " + msg.Text
-		snapshot := h.router.SnapshotContext()
+		snapshot := h.snapshot(msg.UserID)
 
 		// Quick commands: synchronous
 		if !needsClaudeExecution(cmd) {
@@ -363,7 +371,7 @@ func (h *Handler) HandleMessage(msg telegram.Message) {
 		label = "global"
 	}
 
-	snapshot := h.router.SnapshotContext()
+	snapshot := h.snapshot(msg.UserID)
 	procMsgID, _ := h.bot.SendAndGetID(msg.ChatID, fmt.Sprintf("[%s] 메시지 처리 중...", label))
 	h.safeGo(msg.ChatID, func() {
 		result := h.router.Execute(snapshot, "message send telegram "+msg.Text)
@@ -611,7 +619,7 @@ func (h *Handler) HandleCallback(cb telegram.Callback) {
 		}
 
 		// Quick commands: synchronous
-		snapshot := h.router.SnapshotContext()
+		snapshot := h.snapshot(cb.UserID)
 		if !needsClaudeExecution(cmd) {
 			result := h.router.Execute(snapshot, cmd)
 			h.sendResult(cb.ChatID, result)
@@ -650,7 +658,7 @@ func (h *Handler) HandleCallback(cb telegram.Callback) {
 		}
 
 		// Clear interrupted state and re-execute
-		snapshot := h.router.SnapshotContext()
+		snapshot := h.snapshot(cb.UserID)
 		if snapshot.ProjectPath != "" {
 			task.ClearCycleState(snapshot.ProjectPath)
 		}
@@ -665,7 +673,7 @@ func (h *Handler) HandleCallback(cb telegram.Callback) {
 	// Handle project switch (quick command, synchronous)
 	if strings.HasPrefix(cb.Data, "switch:") {
 		projectID := strings.TrimPrefix(cb.Data, "switch:")
-		snapshot := h.router.SnapshotContext()
+		snapshot := h.snapshot(cb.UserID)
 		result := h.router.Execute(snapshot, "project switch "+projectID)
 		h.bot.AnswerCallback(cb.ID, projectID+" 선택됨")
 		h.bot.Send(cb.ChatID, result.Message)
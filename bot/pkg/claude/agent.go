@@ -0,0 +1,94 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultAgent is the backend used when Options.Agent is empty
+const DefaultAgent = "claude"
+
+// Agent is a coding agent backend executed by Manager.
+// Manager owns the concurrency limit and the absolute timeout (applied to ctx);
+// the agent only runs the prompt.
+type Agent interface {
+	// Name identifies the agent in project config (key "agent")
+	Name() string
+
+	// Run executes a single prompt. opts.Timeout is the idle timeout (already resolved).
+	// When opts.ReportPath is set, the report file content is the output.
+	Run(ctx context.Context, opts Options) (*Result, error)
+
+	// StartSession starts an interactive session that ends when ctx is done
+	StartSession(ctx context.Context, opts Options) (AgentSession, error)
+
+	// IsAuthError checks if a failed result means the agent is not logged in
+	IsAuthError(result *Result) bool
+}
+
+// AgentSession is the backend side of an interactive Session
+type AgentSession interface {
+	// Send writes a message and reads the response
+	Send(message string) (string, error)
+
+	// Alive reports whether the backend is still running (checked by the watchdog)
+	Alive() bool
+
+	// Close terminates the backend
+	Close() error
+}
+
+// agent registry
+var (
+	agentsMu sync.RWMutex
+	agents   = make(map[string]Agent)
+)
+
+func init() {
+	RegisterAgent(ptyAgent{})
+}
+
+// RegisterAgent adds an agent backend, replacing any agent with the same name
+func RegisterAgent(a Agent) {
+	agentsMu.Lock()
+	defer agentsMu.Unlock()
+	agents[a.Name()] = a
+}
+
+// UnregisterAgent removes an agent backend (the default agent cannot be removed)
+func UnregisterAgent(name string) {
+	if name == DefaultAgent {
+		return
+	}
+	agentsMu.Lock()
+	defer agentsMu.Unlock()
+	delete(agents, name)
+}
+
+// LookupAgent returns the agent registered under name (empty = DefaultAgent)
+func LookupAgent(name string) (Agent, error) {
+	if name == "" {
+		name = DefaultAgent
+	}
+	agentsMu.RLock()
+	defer agentsMu.RUnlock()
+	a, ok := agents[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown agent: %s", name)
+	}
+	return a, nil
+}
+
+// AgentNames returns the names of the registered agents, sorted
+func AgentNames() []string {
+	agentsMu.RLock()
+	defer agentsMu.RUnlock()
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,104 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAgentRegistry(t *testing.T) {
+	if a, err := LookupAgent(""); err != nil || a.Name() != DefaultAgent {
+		t.Fatalf("Expected default agent, got %v (%v)", a, err)
+	}
+	if _, err := LookupAgent("nope"); err == nil {
+		t.Error("Expected unknown agent to fail")
+	}
+
+	RegisterAgent(NewFakeAgent("fake"))
+	defer UnregisterAgent("fake")
+	if names := strings.Join(AgentNames(), ","); names != "claude,claude-stream,fake" {
+		t.Errorf("Unexpected agents: %s", names)
+	}
+
+	UnregisterAgent(DefaultAgent)
+	if _, err := LookupAgent(DefaultAgent); err != nil {
+		t.Error("Expected default agent to stay registered")
+	}
+}
+
+func TestFakeAgentRun(t *testing.T) {
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1})
+	fake := NewFakeAgent("fake",
+		FakeStep{Report: "# Report"},
+		FakeStep{Output: "Error: not authenticated", ExitCode: 1},
+		FakeStep{Err: errors.New("crashed")},
+		FakeStep{Delay: time.Minute},
+	)
+	RegisterAgent(fake)
+	defer UnregisterAgent("fake")
+
+	reportPath := filepath.Join(t.TempDir(), "report.md")
+	result, err := mgr.Run(context.Background(), Options{Agent: "fake", UserPrompt: "go", ReportPath: reportPath})
+	if err != nil || result.Output != "# Report" || result.Agent != "fake" {
+		t.Fatalf("Unexpected result: %+v (%v)", result, err)
+	}
+	if data, _ := os.ReadFile(reportPath); string(data) != "# Report" {
+		t.Errorf("Expected report file, got %q", data)
+	}
+
+	result, _ = mgr.Run(context.Background(), Options{Agent: "fake"})
+	if !IsAuthError(result) {
+		t.Error("Expected auth error from fake agent")
+	}
+
+	if _, err := mgr.Run(context.Background(), Options{Agent: "fake"}); err == nil || err.Error() != "crashed" {
+		t.Errorf("Expected scripted error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := mgr.Run(ctx, Options{Agent: "fake"}); err == nil {
+		t.Error("Expected cancelled run to fail")
+	}
+
+	if _, err := mgr.Run(context.Background(), Options{Agent: "fake"}); err == nil {
+		t.Error("Expected error when the script is exhausted")
+	}
+	if _, err := mgr.Run(context.Background(), Options{Agent: "nope"}); err == nil {
+		t.Error("Expected unknown agent to fail")
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 5 || calls[0].UserPrompt != "go" || calls[0].Timeout != time.Minute {
+		t.Errorf("Unexpected calls: %+v", calls)
+	}
+	if mgr.Available() != 1 {
+		t.Errorf("Expected slot released, got %d available", mgr.Available())
+	}
+}
+
+func TestFakeAgentSession(t *testing.T) {
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1})
+	RegisterAgent(NewFakeAgent("fake", FakeStep{Output: "hello"}))
+	defer UnregisterAgent("fake")
+
+	session, err := mgr.StartSession(context.Background(), Options{Agent: "fake"})
+	if err != nil {
+		t.Fatalf("StartSession failed: %v", err)
+	}
+	if out, err := session.Send("hi"); err != nil || out != "hello" {
+		t.Errorf("Unexpected response: %q (%v)", out, err)
+	}
+	if mgr.ActiveSessions() != 1 || mgr.Available() != 0 {
+		t.Errorf("Expected 1 session holding the slot")
+	}
+
+	session.Close()
+	if mgr.ActiveSessions() != 0 || mgr.Available() != 1 {
+		t.Errorf("Expected session closed and slot released")
+	}
+}
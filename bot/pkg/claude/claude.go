@@ -4,8 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log"
-	"os"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -13,8 +13,6 @@ import (
 	"syscall"
 	"time"
 	"unicode/utf8"
-
-	"github.com/creack/pty"
 )
 
 // ansiEscapePattern matches ANSI escape sequences
@@ -25,6 +23,11 @@ type Config struct {
 	Timeout    time.Duration // idle timeout (no output)
 	MaxTimeout time.Duration // absolute timeout (total execution time)
 	Max        int           // max concurrent instances
+
+	// Run transcripts (project/.claribot/runs/{run-id}.log)
+	TranscriptMaxBytes int64 // raw output cap per run (0 = no transcripts)
+	TranscriptKeepDays int   // delete transcripts older than this (0 = keep)
+	TranscriptKeepRuns int   // transcripts kept per project (0 = no limit)
 }
 
 // DefaultConfig returns default configuration
@@ -33,15 +36,24 @@ func DefaultConfig() Config {
 		Timeout:    1200 * time.Second, // 20 minutes idle
 		MaxTimeout: 1800 * time.Second, // 30 minutes absolute
 		Max:        3,
+
+		TranscriptMaxBytes: 10 << 20,
+		TranscriptKeepDays: 14,
+		TranscriptKeepRuns: 200,
 	}
 }
 
 // Manager manages Claude Code execution with concurrency control
 type Manager struct {
 	config   Config
-	sem      chan struct{}
+	qmu      sync.Mutex            // guards the queue fields below
+	waiting  []*queueEntry         // enqueue order
+	running  map[int64]*queueEntry // entries holding a slot
+	nextID   int64                 // last queue entry ID
 	mu       sync.RWMutex
 	sessions map[*Session]struct{} // track active sessions
+	lmu      sync.Mutex
+	live     map[string]*LiveOutput // live output by run ID
 	closed   bool
 	stopCh   chan struct{}   // signal to stop watchdog
 	wg       sync.WaitGroup // wait for watchdog goroutine
@@ -56,17 +68,23 @@ var (
 // Init initializes the global manager with config
 func Init(cfg Config) {
 	managerOnce.Do(func() {
-		globalManager = &Manager{
-			config:   cfg,
-			sem:      make(chan struct{}, cfg.Max),
-			sessions: make(map[*Session]struct{}),
-			stopCh:   make(chan struct{}),
-		}
+		globalManager = newManager(cfg)
 		globalManager.wg.Add(1)
 		go globalManager.watchdog()
 	})
 }
 
+// newManager creates a manager without starting the watchdog
+func newManager(cfg Config) *Manager {
+	return &Manager{
+		config:   cfg,
+		running:  make(map[int64]*queueEntry),
+		sessions: make(map[*Session]struct{}),
+		live:     make(map[string]*LiveOutput),
+		stopCh:   make(chan struct{}),
+	}
+}
+
 // Shutdown gracefully shuts down all active sessions
 func Shutdown() {
 	if globalManager == nil {
@@ -121,6 +139,16 @@ func GetManager() *Manager {
 type Result struct {
 	Output   string
 	ExitCode int
+	Agent    string // agent that produced the result
+	RunID    string // run ID (transcript and live output)
+
+	// Structured run details (stream-json mode only, zero under PTY)
+	SessionID  string
+	Turns      int
+	ToolCalls  []ToolCall
+	Usage      *TokenUsage
+	CostUSD    float64
+	DurationMs int64
 }
 
 // Options for Claude Code execution
@@ -132,11 +160,19 @@ type Options struct {
 	Timeout      time.Duration // override idle timeout (0 = use config)
 	AllowedTools []string      // optional: limit available tools
 	ReportPath   string        // optional: report file path for completion detection
+	Agent        string        // optional: agent backend (empty = DefaultAgent)
+	Priority     Priority      // queue priority (default: PriorityBatch)
+	Origin       Origin        // what the run is for (queue listing, project quota)
+	RunID        string        // optional: run ID, set by callers that link it before the run (empty = generated)
+
+	// Transcript receives the raw output of the run (run transcript and live output).
+	// Set by Manager; agents write everything they read from Claude to it (nil = none).
+	Transcript io.Writer
 }
 
 // Run executes Claude Code with PTY and returns the result
 // Uses print mode (-p) for single-shot execution
-// Blocks if max concurrent instances reached (FIFO queue)
+// Blocks if max concurrent instances reached (priority queue)
 func Run(opts Options) (*Result, error) {
 	return RunContext(context.Background(), opts)
 }
@@ -149,26 +185,23 @@ func RunContext(ctx context.Context, opts Options) (*Result, error) {
 
 // Run executes Claude Code with concurrency control
 func (m *Manager) Run(ctx context.Context, opts Options) (*Result, error) {
-	// Acquire semaphore (blocks if max reached, FIFO order)
-	select {
-	case m.sem <- struct{}{}:
-		// acquired
-		log.Printf("[Claude] Semaphore acquired (used: %d/%d)", len(m.sem), m.config.Max)
-	case <-ctx.Done():
-		return nil, fmt.Errorf("cancelled while waiting in queue: %w", ctx.Err())
+	agent, err := LookupAgent(opts.Agent)
+	if err != nil {
+		return nil, err
 	}
-	defer func() {
-		<-m.sem // release
-		log.Printf("[Claude] Semaphore released (used: %d/%d)", len(m.sem), m.config.Max)
-	}()
 
-	return m.execute(ctx, opts)
-}
+	// Acquire a slot (blocks if max reached, by priority then FIFO)
+	entry, err := m.acquire(ctx, opts.Priority, opts.Origin)
+	if err != nil {
+		return nil, err
+	}
+	defer m.release(entry)
 
-// execute runs Claude Code with PTY and idle timeout + absolute timeout
-func (m *Manager) execute(ctx context.Context, opts Options) (*Result, error) {
-	args := buildArgs(opts)
+	return m.execute(ctx, agent, opts)
+}
 
+// execute runs the agent with idle timeout + absolute timeout
+func (m *Manager) execute(ctx context.Context, agent Agent, opts Options) (*Result, error) {
 	// Apply absolute timeout to prevent infinite execution
 	absTimeout := m.config.MaxTimeout
 	if absTimeout > 0 {
@@ -178,238 +211,72 @@ func (m *Manager) execute(ctx context.Context, opts Options) (*Result, error) {
 		log.Printf("[Claude] Absolute timeout set: %v", absTimeout)
 	}
 
-	cmd := exec.CommandContext(ctx, "claude", args...)
-	if opts.WorkDir != "" {
-		cmd.Dir = opts.WorkDir
-	}
-
-	// Start with PTY
-	ptmx, err := pty.Start(cmd)
-	if err != nil {
-		return nil, fmt.Errorf("failed to start claude with pty: %w", err)
-	}
-	defer ptmx.Close()
-
 	// Determine idle timeout
-	idleTimeout := m.config.Timeout
-	if opts.Timeout > 0 {
-		idleTimeout = opts.Timeout
+	if opts.Timeout <= 0 {
+		opts.Timeout = m.config.Timeout
 	}
 
-	// If ReportPath is set, watch for the report file
-	if opts.ReportPath != "" {
-		return m.executeWithReportWatch(ctx, ptmx, cmd, opts.ReportPath, idleTimeout)
+	// Raw output goes to the run transcript and the live output (keyed by run ID)
+	if !ValidRunID(opts.RunID) {
+		opts.RunID = NewRunID()
 	}
+	tr := m.openTranscript(agent, opts)
+	live := m.startLive(opts.RunID, agent.Name(), opts.Origin)
+	opts.Transcript = runOutput{transcript: tr, live: live}
 
-	// Read output with idle timeout
-	output, err := m.readWithIdleTimeout(ctx, ptmx, cmd, idleTimeout)
+	result, err := agent.Run(ctx, opts)
+	if tr != nil {
+		tr.finish(result, err)
+		pruneTranscripts(RunDir(tr.base), m.config.TranscriptKeepDays, m.config.TranscriptKeepRuns)
+	}
+	m.endLive(live, result, err)
 	if err != nil {
-		// Kill process on read error to prevent zombie
-		cmd.Process.Kill()
-		cmd.Wait()
 		return nil, err
 	}
-
-	// Wait for completion with timeout (prevent infinite block)
-	waitDone := make(chan error, 1)
-	go func() {
-		waitDone <- cmd.Wait()
-	}()
-
-	var waitErr error
-	select {
-	case waitErr = <-waitDone:
-		// Process exited normally
-	case <-time.After(10 * time.Second):
-		// Wait timeout - force kill
-		cmd.Process.Kill()
-		<-waitDone // drain
-		waitErr = fmt.Errorf("wait timeout, process killed")
-	}
-
-	// Get exit code
-	exitCode := 0
-	if waitErr != nil {
-		if exitErr, ok := waitErr.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		} else if ctx.Err() != nil {
-			return nil, fmt.Errorf("execution cancelled: %w", ctx.Err())
-		}
+	result.Agent = agent.Name()
+	result.RunID = opts.RunID
+	if result.Usage != nil {
+		log.Printf("[Claude] Run finished (session: %s, turns: %d, tool calls: %d, tokens: %d in / %d out, cost: $%.4f)",
+			result.SessionID, result.Turns, len(result.ToolCalls), result.Usage.InputTokens, result.Usage.OutputTokens, result.CostUSD)
 	}
-
-	return &Result{
-		Output:   stripANSI(output),
-		ExitCode: exitCode,
-	}, nil
+	return result, nil
 }
 
-// executeWithReportWatch runs Claude and watches for a report file to detect completion.
-// When the report file is created, it reads the file content, kills the Claude process, and returns.
-func (m *Manager) executeWithReportWatch(ctx context.Context, ptmx *os.File, cmd *exec.Cmd, reportPath string, idleTimeout time.Duration) (*Result, error) {
-	log.Printf("[Claude] Watching for report file: %s", reportPath)
-
-	// Remove report file if it exists from previous run
-	os.Remove(reportPath)
-
-	// Start reading PTY output in background (to keep PTY alive)
-	var output bytes.Buffer
-	ptyDone := make(chan error, 1)
-	go func() {
-		buf := make([]byte, 4096)
-		for {
-			ptmx.SetReadDeadline(time.Now().Add(idleTimeout))
-			n, err := ptmx.Read(buf)
-			if n > 0 {
-				output.Write(buf[:n])
-			}
-			if err != nil {
-				if os.IsTimeout(err) {
-					cmd.Process.Kill()
-					ptyDone <- fmt.Errorf("idle timeout: no output for %v", idleTimeout)
-					return
-				}
-				ptyDone <- nil
-				return
-			}
-		}
-	}()
-
-	// Watch for report file
-	reportCh := make(chan string, 1)
-	stopWatch := make(chan struct{})
-	go func() {
-		ticker := time.NewTicker(1 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				if data, err := os.ReadFile(reportPath); err == nil && len(data) > 0 {
-					reportCh <- string(data)
-					return
-				}
-			case <-stopWatch:
-				return
-			}
-		}
-	}()
-
-	// Wait for: report file, process exit, or context cancellation
-	select {
-	case reportContent := <-reportCh:
-		close(stopWatch)
-		log.Printf("[Claude] Report file detected: %s (%d bytes)", reportPath, len(reportContent))
-		// Give Claude a moment to finish writing
-		time.Sleep(500 * time.Millisecond)
-		// Re-read to ensure complete content
-		if data, err := os.ReadFile(reportPath); err == nil && len(data) > 0 {
-			reportContent = string(data)
-		}
-		// Kill the process
-		cmd.Process.Kill()
-		cmd.Wait()
-		return &Result{
-			Output:   reportContent,
-			ExitCode: 0,
-		}, nil
-
-	case err := <-ptyDone:
-		close(stopWatch)
-		if err != nil {
-			return nil, err
-		}
-		// Process ended - check if report file was created
-		if data, readErr := os.ReadFile(reportPath); readErr == nil && len(data) > 0 {
-			log.Printf("[Claude] Report file found after process exit: %s", reportPath)
-			return &Result{
-				Output:   string(data),
-				ExitCode: 0,
-			}, nil
-		}
-		// No report file - fall back to PTY output
-		log.Printf("[Claude] No report file, using PTY output")
-
-		// Wait for cmd exit
-		waitDone := make(chan error, 1)
-		go func() { waitDone <- cmd.Wait() }()
-		var waitErr error
-		select {
-		case waitErr = <-waitDone:
-		case <-time.After(10 * time.Second):
-			cmd.Process.Kill()
-			<-waitDone
-			waitErr = fmt.Errorf("wait timeout")
-		}
-		exitCode := 0
-		if waitErr != nil {
-			if exitErr, ok := waitErr.(*exec.ExitError); ok {
-				exitCode = exitErr.ExitCode()
-			}
-		}
-		return &Result{
-			Output:   stripANSI(output.String()),
-			ExitCode: exitCode,
-		}, nil
-
-	case <-ctx.Done():
-		close(stopWatch)
-		cmd.Process.Kill()
-		cmd.Wait()
-		return nil, fmt.Errorf("execution cancelled: %w", ctx.Err())
+// openTranscript starts the transcript of a run in the origin project
+// (or the working directory); nil if transcripts are off or it can't be created
+func (m *Manager) openTranscript(agent Agent, opts Options) *transcript {
+	base := opts.Origin.Project
+	if base == "" {
+		base = opts.WorkDir
 	}
-}
-
-// readWithIdleTimeout reads from PTY with idle timeout
-// Kills process if no output for idleTimeout duration
-func (m *Manager) readWithIdleTimeout(ctx context.Context, ptmx *os.File, cmd *exec.Cmd, idleTimeout time.Duration) (string, error) {
-	var output bytes.Buffer
-	buf := make([]byte, 4096)
-
-	resultCh := make(chan error, 1)
-
-	go func() {
-		for {
-			// Set read deadline for idle timeout
-			ptmx.SetReadDeadline(time.Now().Add(idleTimeout))
-
-			n, err := ptmx.Read(buf)
-			if n > 0 {
-				output.Write(buf[:n])
-			}
-
-			if err != nil {
-				if os.IsTimeout(err) {
-					// Idle timeout - kill process
-					cmd.Process.Kill()
-					resultCh <- fmt.Errorf("idle timeout: no output for %v", idleTimeout)
-					return
-				}
-				// EOF or other error - process ended
-				resultCh <- nil
-				return
-			}
-		}
-	}()
-
-	select {
-	case err := <-resultCh:
-		if err != nil {
-			return output.String(), err
-		}
-		return output.String(), nil
-	case <-ctx.Done():
-		cmd.Process.Kill()
-		return output.String(), ctx.Err()
+	if m.config.TranscriptMaxBytes <= 0 || base == "" {
+		return nil
 	}
+	tr, err := openTranscript(base, opts.RunID, m.config.TranscriptMaxBytes, agent.Name(), opts)
+	if err != nil {
+		log.Printf("[Claude] Transcript open failed (run %s): %v", opts.RunID, err)
+		return nil
+	}
+	return tr
 }
 
 // QueueLength returns current number of waiting executions
 func (m *Manager) QueueLength() int {
-	return len(m.sem)
+	m.qmu.Lock()
+	defer m.qmu.Unlock()
+	return len(m.waiting)
+}
+
+// Used returns number of running executions (including sessions)
+func (m *Manager) Used() int {
+	m.qmu.Lock()
+	defer m.qmu.Unlock()
+	return len(m.running)
 }
 
 // Available returns number of available slots
 func (m *Manager) Available() int {
-	return m.config.Max - len(m.sem)
+	return m.config.Max - m.Used()
 }
 
 // Max returns max concurrent instances
@@ -422,24 +289,27 @@ type Status struct {
 	Max       int `json:"max"`
 	Used      int `json:"used"`
 	Available int `json:"available"`
+	Queued    int `json:"queued"`
 	Sessions  int `json:"sessions"`
 }
 
 // GetStatus returns global manager status
 func GetStatus() Status {
 	mgr := GetManager()
+	used := mgr.Used()
 	return Status{
 		Max:       mgr.config.Max,
-		Used:      len(mgr.sem),
-		Available: mgr.config.Max - len(mgr.sem),
+		Used:      used,
+		Available: mgr.config.Max - used,
+		Queued:    mgr.QueueLength(),
 		Sessions:  mgr.ActiveSessions(),
 	}
 }
 
 // Session represents an interactive Claude Code session
 type Session struct {
-	cmd          *exec.Cmd
-	pty          *os.File
+	conn         AgentSession
+	entry        *queueEntry // queue slot held until Close
 	cancel       context.CancelFunc
 	manager      *Manager
 	mu           sync.Mutex
@@ -467,12 +337,15 @@ func (m *Manager) StartSession(ctx context.Context, opts Options) (*Session, err
 	}
 	m.mu.RUnlock()
 
-	// Acquire semaphore
-	select {
-	case m.sem <- struct{}{}:
-		// acquired
-	case <-ctx.Done():
-		return nil, fmt.Errorf("cancelled while waiting in queue: %w", ctx.Err())
+	agent, err := LookupAgent(opts.Agent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Acquire a slot (held until the session is closed)
+	entry, err := m.acquire(ctx, opts.Priority, opts.Origin)
+	if err != nil {
+		return nil, err
 	}
 
 	// Apply MaxTimeout: use context.WithTimeout if configured, else context.WithCancel
@@ -485,22 +358,19 @@ func (m *Manager) StartSession(ctx context.Context, opts Options) (*Session, err
 		sessionCtx, cancel = context.WithCancel(context.Background())
 	}
 
-	args := buildInteractiveArgs(opts)
-	cmd := exec.CommandContext(sessionCtx, "claude", args...)
-	if opts.WorkDir != "" {
-		cmd.Dir = opts.WorkDir
+	if opts.Timeout <= 0 {
+		opts.Timeout = m.config.Timeout
 	}
-
-	ptmx, err := pty.Start(cmd)
+	conn, err := agent.StartSession(sessionCtx, opts)
 	if err != nil {
 		cancel()
-		<-m.sem // release
-		return nil, fmt.Errorf("failed to start interactive session: %w", err)
+		m.release(entry)
+		return nil, err
 	}
 
 	session := &Session{
-		cmd:          cmd,
-		pty:          ptmx,
+		conn:         conn,
+		entry:        entry,
 		cancel:       cancel,
 		manager:      m,
 		lastActivity: time.Now(),
@@ -525,50 +395,13 @@ func (m *Manager) StartSession(ctx context.Context, opts Options) (*Session, err
 	return session, nil
 }
 
-// Send sends a message to the Claude Code session and reads response
+// Send sends a message to the session and reads response
 func (s *Session) Send(message string) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.lastActivity = time.Now()
-
-	// Write message
-	_, err := s.pty.Write([]byte(message + "\n"))
-	if err != nil {
-		return "", fmt.Errorf("failed to send message: %w", err)
-	}
-
-	// Read response with timeout
-	var output bytes.Buffer
-	buf := make([]byte, 4096)
-
-	// Use manager's idle timeout
-	idleTimeout := s.manager.config.Timeout
-
-	for {
-		s.pty.SetReadDeadline(time.Now().Add(idleTimeout))
-
-		n, err := s.pty.Read(buf)
-		if n > 0 {
-			output.Write(buf[:n])
-		}
-
-		if err != nil {
-			if os.IsTimeout(err) {
-				// Check if we have any output - if so, probably response complete
-				if output.Len() > 0 {
-					break
-				}
-				return "", fmt.Errorf("idle timeout waiting for response")
-			}
-			break
-		}
-
-		// Reset deadline on each read (only timeout if no output)
-		s.pty.SetReadDeadline(time.Now().Add(2 * time.Second))
-	}
-
-	return stripANSI(output.String()), nil
+	return s.conn.Send(message)
 }
 
 // watchdog periodically checks active sessions for dead processes
@@ -593,14 +426,9 @@ func (m *Manager) watchdog() {
 			m.mu.RUnlock()
 
 			for _, s := range sessions {
-				if s.cmd.Process == nil {
-					continue
-				}
-				// Signal(0) checks if process is alive without sending a signal
-				err := s.cmd.Process.Signal(syscall.Signal(0))
-				if err != nil {
-					log.Printf("[Claude] Watchdog: process %d is dead (last activity: %s), closing session",
-						s.cmd.Process.Pid, s.lastActivity.Format("15:04:05"))
+				if !s.conn.Alive() {
+					log.Printf("[Claude] Watchdog: session is dead (last activity: %s), closing session",
+						s.lastActivity.Format("15:04:05"))
 					s.Close()
 				}
 			}
@@ -608,35 +436,35 @@ func (m *Manager) watchdog() {
 	}
 }
 
-// Close terminates the Claude Code session and releases semaphore
+// Close terminates the Claude Code session and releases its slot
 func (s *Session) Close() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.cancel()
-	s.pty.Close()
-	err := s.cmd.Wait()
+	err := s.conn.Close()
 
 	// Untrack session
 	s.manager.mu.Lock()
 	delete(s.manager.sessions, s)
 	s.manager.mu.Unlock()
 
-	// Release semaphore
-	<-s.manager.sem
+	// Release slot (no-op when already closed)
+	s.manager.release(s.entry)
 
 	return err
 }
 
-// authErrorPattern matches authentication/authorization error messages from Claude Code
-var authErrorPattern = regexp.MustCompile(`(?i)(does not have access|please login again|authentication failed|unauthorized|token expired|invalid.{0,20}credentials|API key.{0,20}(invalid|expired|missing)|not authenticated|session expired|access denied)`)
-
-// IsAuthError checks if a Claude Code result indicates an authentication error
+// IsAuthError checks if a result indicates an authentication error of the agent that produced it
 func IsAuthError(result *Result) bool {
-	if result == nil || result.ExitCode == 0 {
+	if result == nil {
+		return false
+	}
+	agent, err := LookupAgent(result.Agent)
+	if err != nil {
 		return false
 	}
-	return authErrorPattern.MatchString(result.Output)
+	return agent.IsAuthError(result)
 }
 
 // stripANSI removes ANSI escape sequences and ensures valid UTF-8 output
@@ -702,51 +530,3 @@ func RunPrintOnly(prompt string, workDir string) (string, error) {
 		return "", fmt.Errorf("timeout waiting for claude")
 	}
 }
-
-// buildArgs constructs command line arguments for print mode
-func buildArgs(opts Options) []string {
-	args := []string{"-p", "--dangerously-skip-permissions"} // print mode, skip permission prompts
-
-	if opts.SystemPrompt != "" {
-		args = append(args, "--system-prompt", opts.SystemPrompt)
-	}
-
-	if opts.Model != "" {
-		args = append(args, "--model", opts.Model)
-	}
-
-	if len(opts.AllowedTools) > 0 {
-		args = append(args, "--tools")
-		args = append(args, opts.AllowedTools...)
-	}
-
-	if opts.UserPrompt != "" {
-		args = append(args, opts.UserPrompt)
-	}
-
-	return args
-}
-
-// buildInteractiveArgs constructs command line arguments for interactive mode
-func buildInteractiveArgs(opts Options) []string {
-	var args []string
-
-	if opts.SystemPrompt != "" {
-		args = append(args, "--system-prompt", opts.SystemPrompt)
-	}
-
-	if opts.Model != "" {
-		args = append(args, "--model", opts.Model)
-	}
-
-	if len(opts.AllowedTools) > 0 {
-		args = append(args, "--tools")
-		args = append(args, opts.AllowedTools...)
-	}
-
-	if opts.UserPrompt != "" {
-		args = append(args, opts.UserPrompt)
-	}
-
-	return args
-}
@@ -1,6 +1,7 @@
 package claude
 
 import (
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -122,7 +123,7 @@ func TestIsAuthError(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got := IsAuthError(tt.result)
 			if got != tt.expected {
-				t.Errorf("IsAuthError() = %v, want %v (output: %q)", got, tt.expected, tt.result)
+				t.Errorf("IsAuthError() = %v, want %v (result: %+v)", got, tt.expected, tt.result)
 			}
 		})
 	}
@@ -146,3 +147,20 @@ func TestConcurrencyLimit(t *testing.T) {
 
 	t.Logf("Max: 2, Available: %d", mgr.Available())
 }
+
+func TestBuildArgs(t *testing.T) {
+	opts := Options{Model: "opus", AllowedTools: []string{"Read", "Edit"}, UserPrompt: "fix the bug"}
+	for name, args := range map[string][]string{
+		"print":       buildArgs(opts),
+		"interactive": buildInteractiveArgs(opts),
+		"stream":      buildStreamArgs(opts),
+	} {
+		// --tools takes one comma-separated value, so the prompt stays the last positional argument
+		if !slices.Contains(args, "Read,Edit") || slices.Contains(args, "Read") {
+			t.Errorf("%s: expected tools as one value, got %q", name, args)
+		}
+		if args[len(args)-1] != "fix the bug" || args[len(args)-2] != "Read,Edit" || args[len(args)-3] != "--tools" {
+			t.Errorf("%s: expected the prompt after the tools value, got %q", name, args)
+		}
+	}
+}
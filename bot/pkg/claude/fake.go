@@ -0,0 +1,149 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FakeStep is one scripted response of a FakeAgent
+type FakeStep struct {
+	Output   string        // printed output (also written to the transcript)
+	Report   string        // written to Options.ReportPath (and returned as output) when set
+	ExitCode int           // process exit code
+	Err      error         // execution error (returned instead of a result)
+	Delay    time.Duration // simulated run time (cancelled with ctx)
+}
+
+// FakeAgent is a scripted agent backend for offline tests.
+// Each Run (or Session.Send) consumes the next step; register it with
+// RegisterAgent and select it by name via Options.Agent or the project config.
+type FakeAgent struct {
+	name  string
+	mu    sync.Mutex
+	steps []FakeStep
+	calls []Options
+}
+
+// NewFakeAgent creates a fake agent with the given scripted steps
+func NewFakeAgent(name string, steps ...FakeStep) *FakeAgent {
+	return &FakeAgent{name: name, steps: steps}
+}
+
+// Name returns the agent name
+func (f *FakeAgent) Name() string { return f.name }
+
+// Push appends scripted steps
+func (f *FakeAgent) Push(steps ...FakeStep) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.steps = append(f.steps, steps...)
+}
+
+// Calls returns the options of every Run and StartSession so far
+func (f *FakeAgent) Calls() []Options {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Options(nil), f.calls...)
+}
+
+// next records a call and pops the next step
+func (f *FakeAgent) next(opts *Options) (FakeStep, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if opts != nil {
+		f.calls = append(f.calls, *opts)
+	}
+	if len(f.steps) == 0 {
+		return FakeStep{}, fmt.Errorf("fake agent %s: no scripted step left", f.name)
+	}
+	step := f.steps[0]
+	f.steps = f.steps[1:]
+	return step, nil
+}
+
+// play waits for the step's delay and returns its output
+func (f *FakeAgent) play(ctx context.Context, step FakeStep, reportPath string) (*Result, error) {
+	if step.Delay > 0 {
+		select {
+		case <-time.After(step.Delay):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("execution cancelled: %w", ctx.Err())
+		}
+	}
+	if step.Err != nil {
+		return nil, step.Err
+	}
+
+	output := step.Output
+	if reportPath != "" && step.Report != "" {
+		if err := os.WriteFile(reportPath, []byte(step.Report), 0644); err != nil {
+			return nil, fmt.Errorf("fake agent %s: write report: %w", f.name, err)
+		}
+		output = step.Report
+	}
+	return &Result{Output: output, ExitCode: step.ExitCode}, nil
+}
+
+// Run plays the next scripted step
+func (f *FakeAgent) Run(ctx context.Context, opts Options) (*Result, error) {
+	step, err := f.next(&opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Transcript != nil && step.Output != "" {
+		opts.Transcript.Write([]byte(step.Output))
+	}
+	return f.play(ctx, step, opts.ReportPath)
+}
+
+// StartSession starts a session whose messages play the following steps
+func (f *FakeAgent) StartSession(ctx context.Context, opts Options) (AgentSession, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, opts)
+	f.mu.Unlock()
+	return &fakeSession{agent: f, ctx: ctx}, nil
+}
+
+// IsAuthError uses the same patterns as Claude Code
+func (f *FakeAgent) IsAuthError(result *Result) bool {
+	return ptyAgent{}.IsAuthError(result)
+}
+
+// fakeSession is the session of a FakeAgent
+type fakeSession struct {
+	agent  *FakeAgent
+	ctx    context.Context
+	mu     sync.Mutex
+	closed bool
+}
+
+// Send plays the next scripted step (the message is not recorded)
+func (s *fakeSession) Send(message string) (string, error) {
+	step, err := s.agent.next(nil)
+	if err != nil {
+		return "", err
+	}
+	result, err := s.agent.play(s.ctx, step, "")
+	if err != nil {
+		return "", err
+	}
+	return result.Output, nil
+}
+
+// Alive reports whether the session is still open
+func (s *fakeSession) Alive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.closed && s.ctx.Err() == nil
+}
+
+// Close closes the session
+func (s *fakeSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
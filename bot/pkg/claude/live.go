@@ -0,0 +1,261 @@
+package claude
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// liveBufferSize is the output kept per run for late subscribers (most recent bytes)
+const liveBufferSize = 256 * 1024
+
+// liveKeep is how long a finished run's output stays available for tails
+var liveKeep = time.Minute
+
+// LiveOutput is the output of a running (or just finished) run. Readers
+// address it by byte offset, so they can resume where they left off;
+// only the most recent liveBufferSize bytes are kept.
+type LiveOutput struct {
+	id        string
+	origin    Origin
+	agent     string
+	startedAt time.Time
+
+	mu       sync.Mutex
+	buf      []byte // most recent output
+	start    int64  // stream offset of buf[0]
+	done     bool
+	exitCode int
+	err      string
+	changed  chan struct{} // closed (and replaced) on every write and on finish
+}
+
+// LiveInfo describes a live run
+type LiveInfo struct {
+	RunID     string    `json:"run_id"`
+	Agent     string    `json:"agent"`
+	Origin    Origin    `json:"origin"`
+	StartedAt time.Time `json:"started_at"`
+	Offset    int64     `json:"offset"` // bytes published so far
+	Done      bool      `json:"done"`
+	ExitCode  int       `json:"exit_code"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func newLiveOutput(id, agent string, origin Origin) *LiveOutput {
+	return &LiveOutput{
+		id:        id,
+		origin:    origin,
+		agent:     agent,
+		startedAt: time.Now(),
+		changed:   make(chan struct{}),
+	}
+}
+
+// Write publishes output (ignored after the run finished)
+func (l *LiveOutput) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done || len(p) == 0 {
+		return len(p), nil
+	}
+	l.buf = append(l.buf, p...)
+	// Trim to the buffer size once it doubled (amortized copying)
+	if len(l.buf) > 2*liveBufferSize {
+		drop := len(l.buf) - liveBufferSize
+		l.buf = append([]byte(nil), l.buf[drop:]...)
+		l.start += int64(drop)
+	}
+	l.notifyLocked()
+	return len(p), nil
+}
+
+// finish marks the run as ended and wakes up all readers
+func (l *LiveOutput) finish(result *Result, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.done = true
+	if err != nil {
+		l.err = err.Error()
+		l.exitCode = -1
+	} else if result != nil {
+		l.exitCode = result.ExitCode
+	}
+	l.notifyLocked()
+}
+
+func (l *LiveOutput) notifyLocked() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// Since returns the output from offset on and the offset to continue from.
+// A negative offset reads the last -offset bytes; output older than the
+// buffer is skipped (the returned data then starts at the oldest kept byte).
+func (l *LiveOutput) Since(offset int64) ([]byte, int64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	end := l.start + int64(len(l.buf))
+	if offset < 0 {
+		offset += end
+	}
+	if offset < l.start {
+		offset = l.start
+	}
+	if offset > end {
+		offset = end
+	}
+	data := append([]byte(nil), l.buf[offset-l.start:]...)
+	return data, end, l.done
+}
+
+// Text is Since with the output ANSI-stripped. An incomplete UTF-8
+// sequence at the end is held back until the rest of it arrives.
+func (l *LiveOutput) Text(offset int64) (string, int64, bool) {
+	data, next, done := l.Since(offset)
+	if !done {
+		for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					next -= int64(len(data) - i)
+					data = data[:i]
+				}
+				break
+			}
+		}
+	}
+	return stripANSI(string(data)), next, done
+}
+
+// Wait blocks until there is output beyond offset, the run finished,
+// or ctx is done. Returns false if ctx ended first.
+func (l *LiveOutput) Wait(ctx context.Context, offset int64) bool {
+	l.mu.Lock()
+	if l.done || l.start+int64(len(l.buf)) > offset {
+		l.mu.Unlock()
+		return true
+	}
+	changed := l.changed
+	l.mu.Unlock()
+
+	select {
+	case <-changed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Info returns the run description and progress
+func (l *LiveOutput) Info() LiveInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return LiveInfo{
+		RunID:     l.id,
+		Agent:     l.agent,
+		Origin:    l.origin,
+		StartedAt: l.startedAt,
+		Offset:    l.start + int64(len(l.buf)),
+		Done:      l.done,
+		ExitCode:  l.exitCode,
+		Error:     l.err,
+	}
+}
+
+// startLive registers the live output of a run
+func (m *Manager) startLive(id, agent string, origin Origin) *LiveOutput {
+	l := newLiveOutput(id, agent, origin)
+	m.lmu.Lock()
+	m.live[id] = l
+	m.lmu.Unlock()
+	return l
+}
+
+// endLive finishes a live output; it is dropped after liveKeep
+func (m *Manager) endLive(l *LiveOutput, result *Result, err error) {
+	l.finish(result, err)
+	time.AfterFunc(liveKeep, func() {
+		m.lmu.Lock()
+		if m.live[l.id] == l {
+			delete(m.live, l.id)
+		}
+		m.lmu.Unlock()
+	})
+}
+
+// Live returns the live output of a run (nil if unknown or expired)
+func (m *Manager) Live(runID string) *LiveOutput {
+	m.lmu.Lock()
+	defer m.lmu.Unlock()
+	return m.live[runID]
+}
+
+// LiveFor returns the newest live output whose origin matches (nil if none),
+// e.g. the current run of a task or message
+func (m *Manager) LiveFor(match func(Origin) bool) *LiveOutput {
+	m.lmu.Lock()
+	defer m.lmu.Unlock()
+	var newest *LiveOutput
+	for _, l := range m.live {
+		if match(l.origin) && (newest == nil || l.startedAt.After(newest.startedAt)) {
+			newest = l
+		}
+	}
+	return newest
+}
+
+// LiveRuns lists the live outputs, oldest first
+func (m *Manager) LiveRuns() []LiveInfo {
+	m.lmu.Lock()
+	runs := make([]*LiveOutput, 0, len(m.live))
+	for _, l := range m.live {
+		runs = append(runs, l)
+	}
+	m.lmu.Unlock()
+
+	infos := make([]LiveInfo, 0, len(runs))
+	for _, l := range runs {
+		infos = append(infos, l.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].StartedAt.Before(infos[j].StartedAt) })
+	return infos
+}
+
+// GetLive returns the live output of a run of the global manager
+func GetLive(runID string) *LiveOutput {
+	return GetManager().Live(runID)
+}
+
+// GetLiveRuns lists the live outputs of the global manager
+func GetLiveRuns() []LiveInfo {
+	return GetManager().LiveRuns()
+}
+
+// GetLiveForTask returns the current (or just finished) run of a task
+func GetLiveForTask(projectPath string, taskID int) *LiveOutput {
+	return GetManager().LiveFor(func(o Origin) bool {
+		return o.Project == projectPath && o.TaskID == taskID
+	})
+}
+
+// GetLiveForMessage returns the run of a message
+func GetLiveForMessage(messageID int64) *LiveOutput {
+	return GetManager().LiveFor(func(o Origin) bool {
+		return o.MessageID == messageID
+	})
+}
+
+// runOutput fans the raw output of a run out to its transcript and live output
+type runOutput struct {
+	transcript *transcript
+	live       *LiveOutput
+}
+
+func (o runOutput) Write(p []byte) (int, error) {
+	if o.transcript != nil {
+		o.transcript.Write(p)
+	}
+	return o.live.Write(p)
+}
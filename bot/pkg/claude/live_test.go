@@ -0,0 +1,155 @@
+package claude
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLiveOutputSince(t *testing.T) {
+	l := newLiveOutput("run", "fake", Origin{})
+	l.Write([]byte("hello "))
+	l.Write([]byte("world"))
+
+	data, next, done := l.Since(0)
+	if string(data) != "hello world" || next != 11 || done {
+		t.Errorf("Unexpected Since(0): %q %d %v", data, next, done)
+	}
+	if data, next, _ = l.Since(6); string(data) != "world" || next != 11 {
+		t.Errorf("Unexpected Since(6): %q %d", data, next)
+	}
+	if data, _, _ = l.Since(-5); string(data) != "world" {
+		t.Errorf("Expected last 5 bytes, got %q", data)
+	}
+	if data, next, _ = l.Since(100); len(data) != 0 || next != 11 {
+		t.Errorf("Expected nothing past the end, got %q %d", data, next)
+	}
+
+	l.finish(&Result{ExitCode: 2}, nil)
+	l.Write([]byte("late")) // after finish: ignored
+	if data, _, done = l.Since(0); string(data) != "hello world" || !done {
+		t.Errorf("Unexpected output after finish: %q %v", data, done)
+	}
+	if info := l.Info(); !info.Done || info.ExitCode != 2 || info.Offset != 11 {
+		t.Errorf("Unexpected info: %+v", info)
+	}
+}
+
+func TestLiveOutputTrim(t *testing.T) {
+	l := newLiveOutput("run", "fake", Origin{})
+	chunk := []byte(strings.Repeat("x", liveBufferSize))
+	for i := 0; i < 3; i++ {
+		l.Write(chunk)
+	}
+	total := int64(3 * liveBufferSize)
+
+	// Offsets older than the buffer start at the oldest kept byte
+	data, next, _ := l.Since(0)
+	if next != total || int64(len(data)) != total-l.start || len(data) > 2*liveBufferSize {
+		t.Errorf("Unexpected trimmed output: %d bytes, next %d, start %d", len(data), next, l.start)
+	}
+	if l.start == 0 {
+		t.Error("Expected old output dropped")
+	}
+}
+
+func TestLiveOutputText(t *testing.T) {
+	l := newLiveOutput("run", "fake", Origin{})
+	l.Write([]byte("\x1b[32m완료\x1b[0m "))
+	l.Write([]byte("작업"[:4])) // "작" and the first byte of "업"
+
+	text, next, _ := l.Text(0)
+	if text != "완료 작" {
+		t.Errorf("Expected incomplete rune held back, got %q", text)
+	}
+	l.Write([]byte("작업"[4:]))
+	if text, _, _ = l.Text(next); text != "업" {
+		t.Errorf("Expected rest of the rune, got %q", text)
+	}
+}
+
+func TestLiveOutputWait(t *testing.T) {
+	l := newLiveOutput("run", "fake", Origin{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if l.Wait(ctx, 0) {
+		t.Error("Expected Wait to time out without output")
+	}
+
+	woke := make(chan bool, 1)
+	go func() { woke <- l.Wait(context.Background(), 0) }()
+	time.Sleep(5 * time.Millisecond)
+	l.Write([]byte("x"))
+	select {
+	case ok := <-woke:
+		if !ok {
+			t.Error("Expected Wait to report new output")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not wake up on write")
+	}
+
+	// Nothing past offset 1 yet, but finishing wakes readers up
+	go func() { woke <- l.Wait(context.Background(), 1) }()
+	time.Sleep(5 * time.Millisecond)
+	l.finish(&Result{}, nil)
+	select {
+	case <-woke:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not wake up on finish")
+	}
+}
+
+func TestRunLiveOutput(t *testing.T) {
+	saved := liveKeep
+	liveKeep = 20 * time.Millisecond
+	defer func() { liveKeep = saved }()
+
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1})
+	RegisterAgent(NewFakeAgent("fake", FakeStep{Output: "\x1b[1mstep one\x1b[0m\n", Delay: 50 * time.Millisecond}))
+	defer UnregisterAgent("fake")
+
+	runID := NewRunID()
+	origin := Origin{Project: "/p", TaskID: 5}
+	done := make(chan *Result, 1)
+	go func() {
+		result, _ := mgr.Run(context.Background(), Options{Agent: "fake", RunID: runID, Origin: origin})
+		done <- result
+	}()
+
+	// The run is visible by run ID and origin while it runs
+	deadline := time.Now().Add(time.Second)
+	for mgr.Live(runID) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Run was not published")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	live := mgr.LiveFor(func(o Origin) bool { return o.Project == "/p" && o.TaskID == 5 })
+	if live == nil || live.Info().RunID != runID {
+		t.Fatalf("Expected live output of task #5, got %+v", live)
+	}
+	if runs := mgr.LiveRuns(); len(runs) != 1 || runs[0].Agent != "fake" {
+		t.Errorf("Unexpected live runs: %+v", runs)
+	}
+
+	result := <-done
+	if result == nil || result.RunID != runID {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	text, _, finished := live.Text(0)
+	if text != "step one\n" || !finished {
+		t.Errorf("Unexpected live output after the run: %q done=%v", text, finished)
+	}
+
+	// Dropped after liveKeep
+	deadline = time.Now().Add(time.Second)
+	for mgr.Live(runID) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Finished run was not dropped")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
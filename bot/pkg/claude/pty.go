@@ -0,0 +1,389 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// ptyAgent runs the Claude Code CLI under a PTY (the default agent)
+type ptyAgent struct{}
+
+// Name returns the agent name
+func (ptyAgent) Name() string { return DefaultAgent }
+
+// Run executes Claude Code in print mode with an idle timeout
+// (the absolute timeout is already applied to ctx by Manager)
+func (ptyAgent) Run(ctx context.Context, opts Options) (*Result, error) {
+	args := buildArgs(opts)
+
+	cmd := exec.CommandContext(ctx, "claude", args...)
+	if opts.WorkDir != "" {
+		cmd.Dir = opts.WorkDir
+	}
+
+	// Start with PTY
+	ptmx, err := pty.Start(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start claude with pty: %w", err)
+	}
+	defer ptmx.Close()
+
+	idleTimeout := opts.Timeout
+
+	// If ReportPath is set, watch for the report file
+	if opts.ReportPath != "" {
+		return executeWithReportWatch(ctx, ptmx, cmd, opts.ReportPath, idleTimeout, opts.Transcript)
+	}
+
+	// Read output with idle timeout
+	output, err := readWithIdleTimeout(ctx, ptmx, cmd, idleTimeout, opts.Transcript)
+	if err != nil {
+		// Kill process on read error to prevent zombie
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+
+	// Wait for completion with timeout (prevent infinite block)
+	waitDone := make(chan error, 1)
+	go func() {
+		waitDone <- cmd.Wait()
+	}()
+
+	var waitErr error
+	select {
+	case waitErr = <-waitDone:
+		// Process exited normally
+	case <-time.After(10 * time.Second):
+		// Wait timeout - force kill
+		cmd.Process.Kill()
+		<-waitDone // drain
+		waitErr = fmt.Errorf("wait timeout, process killed")
+	}
+
+	// Get exit code
+	exitCode := 0
+	if waitErr != nil {
+		if exitErr, ok := waitErr.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		} else if ctx.Err() != nil {
+			return nil, fmt.Errorf("execution cancelled: %w", ctx.Err())
+		}
+	}
+
+	return &Result{
+		Output:   stripANSI(output),
+		ExitCode: exitCode,
+	}, nil
+}
+
+// executeWithReportWatch runs Claude and watches for a report file to detect completion.
+// When the report file is created, it reads the file content, kills the Claude process, and returns.
+// PTY output is also copied to transcript (if set).
+func executeWithReportWatch(ctx context.Context, ptmx *os.File, cmd *exec.Cmd, reportPath string, idleTimeout time.Duration, transcript io.Writer) (*Result, error) {
+	log.Printf("[Claude] Watching for report file: %s", reportPath)
+
+	// Remove report file if it exists from previous run
+	os.Remove(reportPath)
+
+	// Start reading PTY output in background (to keep PTY alive)
+	var output bytes.Buffer
+	ptyDone := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			ptmx.SetReadDeadline(time.Now().Add(idleTimeout))
+			n, err := ptmx.Read(buf)
+			if n > 0 {
+				output.Write(buf[:n])
+				if transcript != nil {
+					transcript.Write(buf[:n])
+				}
+			}
+			if err != nil {
+				if os.IsTimeout(err) {
+					cmd.Process.Kill()
+					ptyDone <- fmt.Errorf("idle timeout: no output for %v", idleTimeout)
+					return
+				}
+				ptyDone <- nil
+				return
+			}
+		}
+	}()
+
+	// Watch for report file
+	reportCh := make(chan string, 1)
+	stopWatch := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if data, err := os.ReadFile(reportPath); err == nil && len(data) > 0 {
+					reportCh <- string(data)
+					return
+				}
+			case <-stopWatch:
+				return
+			}
+		}
+	}()
+
+	// Wait for: report file, process exit, or context cancellation
+	select {
+	case reportContent := <-reportCh:
+		close(stopWatch)
+		log.Printf("[Claude] Report file detected: %s (%d bytes)", reportPath, len(reportContent))
+		// Give Claude a moment to finish writing
+		time.Sleep(500 * time.Millisecond)
+		// Re-read to ensure complete content
+		if data, err := os.ReadFile(reportPath); err == nil && len(data) > 0 {
+			reportContent = string(data)
+		}
+		// Kill the process
+		cmd.Process.Kill()
+		cmd.Wait()
+		return &Result{
+			Output:   reportContent,
+			ExitCode: 0,
+		}, nil
+
+	case err := <-ptyDone:
+		close(stopWatch)
+		if err != nil {
+			return nil, err
+		}
+		// Process ended - check if report file was created
+		if data, readErr := os.ReadFile(reportPath); readErr == nil && len(data) > 0 {
+			log.Printf("[Claude] Report file found after process exit: %s", reportPath)
+			return &Result{
+				Output:   string(data),
+				ExitCode: 0,
+			}, nil
+		}
+		// No report file - fall back to PTY output
+		log.Printf("[Claude] No report file, using PTY output")
+
+		// Wait for cmd exit
+		waitDone := make(chan error, 1)
+		go func() { waitDone <- cmd.Wait() }()
+		var waitErr error
+		select {
+		case waitErr = <-waitDone:
+		case <-time.After(10 * time.Second):
+			cmd.Process.Kill()
+			<-waitDone
+			waitErr = fmt.Errorf("wait timeout")
+		}
+		exitCode := 0
+		if waitErr != nil {
+			if exitErr, ok := waitErr.(*exec.ExitError); ok {
+				exitCode = exitErr.ExitCode()
+			}
+		}
+		return &Result{
+			Output:   stripANSI(output.String()),
+			ExitCode: exitCode,
+		}, nil
+
+	case <-ctx.Done():
+		close(stopWatch)
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("execution cancelled: %w", ctx.Err())
+	}
+}
+
+// readWithIdleTimeout reads from PTY with idle timeout, copying output to transcript (if set)
+// Kills process if no output for idleTimeout duration
+func readWithIdleTimeout(ctx context.Context, ptmx *os.File, cmd *exec.Cmd, idleTimeout time.Duration, transcript io.Writer) (string, error) {
+	var output bytes.Buffer
+	buf := make([]byte, 4096)
+
+	resultCh := make(chan error, 1)
+
+	go func() {
+		for {
+			// Set read deadline for idle timeout
+			ptmx.SetReadDeadline(time.Now().Add(idleTimeout))
+
+			n, err := ptmx.Read(buf)
+			if n > 0 {
+				output.Write(buf[:n])
+				if transcript != nil {
+					transcript.Write(buf[:n])
+				}
+			}
+
+			if err != nil {
+				if os.IsTimeout(err) {
+					// Idle timeout - kill process
+					cmd.Process.Kill()
+					resultCh <- fmt.Errorf("idle timeout: no output for %v", idleTimeout)
+					return
+				}
+				// EOF or other error - process ended
+				resultCh <- nil
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-resultCh:
+		if err != nil {
+			return output.String(), err
+		}
+		return output.String(), nil
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		return output.String(), ctx.Err()
+	}
+}
+
+// ptySession is an interactive Claude Code process under a PTY
+type ptySession struct {
+	cmd         *exec.Cmd
+	pty         *os.File
+	idleTimeout time.Duration
+}
+
+// StartSession starts Claude Code in interactive mode
+func (ptyAgent) StartSession(ctx context.Context, opts Options) (AgentSession, error) {
+	args := buildInteractiveArgs(opts)
+	cmd := exec.CommandContext(ctx, "claude", args...)
+	if opts.WorkDir != "" {
+		cmd.Dir = opts.WorkDir
+	}
+
+	ptmx, err := pty.Start(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start interactive session: %w", err)
+	}
+	return &ptySession{cmd: cmd, pty: ptmx, idleTimeout: opts.Timeout}, nil
+}
+
+// Send writes a message to the PTY and reads the response
+func (s *ptySession) Send(message string) (string, error) {
+	// Write message
+	_, err := s.pty.Write([]byte(message + "\n"))
+	if err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+
+	// Read response with timeout
+	var output bytes.Buffer
+	buf := make([]byte, 4096)
+
+	for {
+		s.pty.SetReadDeadline(time.Now().Add(s.idleTimeout))
+
+		n, err := s.pty.Read(buf)
+		if n > 0 {
+			output.Write(buf[:n])
+		}
+
+		if err != nil {
+			if os.IsTimeout(err) {
+				// Check if we have any output - if so, probably response complete
+				if output.Len() > 0 {
+					break
+				}
+				return "", fmt.Errorf("idle timeout waiting for response")
+			}
+			break
+		}
+
+		// Reset deadline on each read (only timeout if no output)
+		s.pty.SetReadDeadline(time.Now().Add(2 * time.Second))
+	}
+
+	return stripANSI(output.String()), nil
+}
+
+// Alive checks if the process is still running
+func (s *ptySession) Alive() bool {
+	if s.cmd.Process == nil {
+		return true
+	}
+	// Signal(0) checks if process is alive without sending a signal
+	return s.cmd.Process.Signal(syscall.Signal(0)) == nil
+}
+
+// Close closes the PTY and waits for the process to exit
+func (s *ptySession) Close() error {
+	s.pty.Close()
+	return s.cmd.Wait()
+}
+
+// authErrorPattern matches authentication/authorization error messages from Claude Code
+var authErrorPattern = regexp.MustCompile(`(?i)(does not have access|please login again|authentication failed|unauthorized|token expired|invalid.{0,20}credentials|API key.{0,20}(invalid|expired|missing)|not authenticated|session expired|access denied)`)
+
+// IsAuthError checks if a Claude Code result indicates an authentication error
+func (ptyAgent) IsAuthError(result *Result) bool {
+	if result == nil || result.ExitCode == 0 {
+		return false
+	}
+	return authErrorPattern.MatchString(result.Output)
+}
+
+// buildArgs constructs command line arguments for print mode
+func buildArgs(opts Options) []string {
+	args := []string{"-p", "--dangerously-skip-permissions"} // print mode, skip permission prompts
+
+	if opts.SystemPrompt != "" {
+		args = append(args, "--system-prompt", opts.SystemPrompt)
+	}
+
+	if opts.Model != "" {
+		args = append(args, "--model", opts.Model)
+	}
+
+	if len(opts.AllowedTools) > 0 {
+		// One comma-separated value: --tools takes a variable number of values and
+		// would read a following prompt as one more tool name
+		args = append(args, "--tools", strings.Join(opts.AllowedTools, ","))
+	}
+
+	if opts.UserPrompt != "" {
+		args = append(args, opts.UserPrompt)
+	}
+
+	return args
+}
+
+// buildInteractiveArgs constructs command line arguments for interactive mode
+func buildInteractiveArgs(opts Options) []string {
+	var args []string
+
+	if opts.SystemPrompt != "" {
+		args = append(args, "--system-prompt", opts.SystemPrompt)
+	}
+
+	if opts.Model != "" {
+		args = append(args, "--model", opts.Model)
+	}
+
+	if len(opts.AllowedTools) > 0 {
+		args = append(args, "--tools", strings.Join(opts.AllowedTools, ","))
+	}
+
+	if opts.UserPrompt != "" {
+		args = append(args, opts.UserPrompt)
+	}
+
+	return args
+}
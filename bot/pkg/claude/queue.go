@@ -0,0 +1,263 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Priority orders waiting runs (higher first, FIFO within a priority)
+type Priority int
+
+const (
+	PriorityBatch       Priority = iota // task plan/run, cycles (default)
+	PrioritySchedule                    // scheduled jobs
+	PriorityInteractive                 // messages and prompts a user is waiting for
+)
+
+// String returns the priority name
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PrioritySchedule:
+		return "schedule"
+	default:
+		return "batch"
+	}
+}
+
+// Origin identifies what a run is for (shown in the queue, used for project quotas)
+type Origin struct {
+	Project    string `json:"project,omitempty"` // project path
+	TaskID     int    `json:"task_id,omitempty"`
+	MessageID  int64  `json:"message_id,omitempty"`
+	ScheduleID int    `json:"schedule_id,omitempty"`
+}
+
+// String returns a short label like "myproj task #12"
+func (o Origin) String() string {
+	var parts []string
+	if o.Project != "" {
+		parts = append(parts, filepath.Base(o.Project))
+	}
+	switch {
+	case o.TaskID > 0:
+		parts = append(parts, fmt.Sprintf("task #%d", o.TaskID))
+	case o.MessageID > 0:
+		parts = append(parts, fmt.Sprintf("message #%d", o.MessageID))
+	case o.ScheduleID > 0:
+		parts = append(parts, fmt.Sprintf("schedule #%d", o.ScheduleID))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+// ErrQueueCancelled is returned to a run whose queue entry was cancelled
+var ErrQueueCancelled = errors.New("cancelled in queue")
+
+// queueEntry is a run waiting for or holding a slot
+type queueEntry struct {
+	id         int64
+	priority   Priority
+	origin     Origin
+	quota      int // max concurrent runs of the origin project (0 = no limit)
+	enqueuedAt time.Time
+	startedAt  time.Time
+	ready      chan struct{} // closed when a slot is granted
+	cancelled  chan struct{} // closed when cancelled while waiting
+}
+
+// QueueEntry is the public view of a queue entry
+type QueueEntry struct {
+	ID         int64      `json:"id"`
+	State      string     `json:"state"`    // running, waiting
+	Position   int        `json:"position"` // 1-based among waiting entries (0 = running)
+	Priority   string     `json:"priority"`
+	Origin     Origin     `json:"origin"`
+	EnqueuedAt time.Time  `json:"enqueued_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+}
+
+// QueueStatus lists the running and waiting entries
+type QueueStatus struct {
+	Max     int          `json:"max"`
+	Running []QueueEntry `json:"running"`
+	Waiting []QueueEntry `json:"waiting"`
+}
+
+// quotaFunc returns the concurrency quota of a project (0 = no limit)
+var quotaFunc func(project string) int
+
+// SetQuotaFunc sets the per-project concurrency quota lookup (called once per enqueued run)
+func SetQuotaFunc(fn func(project string) int) {
+	quotaFunc = fn
+}
+
+// acquire waits for a slot. The entry must be released with release.
+func (m *Manager) acquire(ctx context.Context, priority Priority, origin Origin) (*queueEntry, error) {
+	quota := 0
+	if quotaFunc != nil && origin.Project != "" {
+		quota = quotaFunc(origin.Project)
+	}
+
+	m.qmu.Lock()
+	m.nextID++
+	e := &queueEntry{
+		id:         m.nextID,
+		priority:   priority,
+		origin:     origin,
+		quota:      quota,
+		enqueuedAt: time.Now(),
+		ready:      make(chan struct{}),
+		cancelled:  make(chan struct{}),
+	}
+	m.waiting = append(m.waiting, e)
+	m.dispatchLocked()
+	m.qmu.Unlock()
+
+	select {
+	case <-e.ready:
+	case <-e.cancelled:
+		return nil, ErrQueueCancelled
+	case <-ctx.Done():
+		m.qmu.Lock()
+		select {
+		case <-e.ready:
+			// Granted while cancelling: hand the slot back
+			m.releaseLocked(e)
+		default:
+			m.removeWaitingLocked(e.id)
+		}
+		m.qmu.Unlock()
+		return nil, fmt.Errorf("cancelled while waiting in queue: %w", ctx.Err())
+	}
+
+	log.Printf("[Claude] Slot acquired (#%d %s %s, used: %d/%d, waiting: %d)", e.id, e.priority, e.origin, m.Used(), m.config.Max, m.QueueLength())
+	return e, nil
+}
+
+// release frees the slot of an entry and starts the next waiting run
+func (m *Manager) release(e *queueEntry) {
+	m.qmu.Lock()
+	released := m.releaseLocked(e)
+	m.qmu.Unlock()
+	if !released {
+		return
+	}
+	log.Printf("[Claude] Slot released (#%d, used: %d/%d, waiting: %d)", e.id, m.Used(), m.config.Max, m.QueueLength())
+}
+
+// releaseLocked frees the slot (false if the entry no longer holds one)
+func (m *Manager) releaseLocked(e *queueEntry) bool {
+	if _, ok := m.running[e.id]; !ok {
+		return false
+	}
+	delete(m.running, e.id)
+	m.dispatchLocked()
+	return true
+}
+
+// dispatchLocked grants free slots to the best waiting entries:
+// highest priority first, oldest first, skipping projects at their quota.
+func (m *Manager) dispatchLocked() {
+	for len(m.running) < m.config.Max {
+		best := -1
+		for i, e := range m.waiting {
+			if e.quota > 0 && m.projectRunningLocked(e.origin.Project) >= e.quota {
+				continue
+			}
+			if best < 0 || e.priority > m.waiting[best].priority {
+				best = i
+			}
+		}
+		if best < 0 {
+			return
+		}
+		e := m.waiting[best]
+		m.waiting = append(m.waiting[:best], m.waiting[best+1:]...)
+		e.startedAt = time.Now()
+		m.running[e.id] = e
+		close(e.ready)
+	}
+}
+
+// projectRunningLocked counts the running entries of a project
+func (m *Manager) projectRunningLocked(project string) int {
+	n := 0
+	for _, e := range m.running {
+		if e.origin.Project == project {
+			n++
+		}
+	}
+	return n
+}
+
+// removeWaitingLocked removes a waiting entry, returning it (nil if not waiting)
+func (m *Manager) removeWaitingLocked(id int64) *queueEntry {
+	for i, e := range m.waiting {
+		if e.id == id {
+			m.waiting = append(m.waiting[:i], m.waiting[i+1:]...)
+			return e
+		}
+	}
+	return nil
+}
+
+// CancelQueued cancels a waiting entry; its run returns ErrQueueCancelled
+func (m *Manager) CancelQueued(id int64) error {
+	m.qmu.Lock()
+	defer m.qmu.Unlock()
+	if e := m.removeWaitingLocked(id); e != nil {
+		close(e.cancelled)
+		log.Printf("[Claude] Queue entry #%d cancelled (%s)", id, e.priority)
+		return nil
+	}
+	if _, ok := m.running[id]; ok {
+		return fmt.Errorf("queue entry #%d is already running", id)
+	}
+	return fmt.Errorf("queue entry #%d not found", id)
+}
+
+// Queue returns the running entries (oldest first) and the waiting entries in dispatch order
+func (m *Manager) Queue() QueueStatus {
+	m.qmu.Lock()
+	defer m.qmu.Unlock()
+
+	status := QueueStatus{Max: m.config.Max, Running: []QueueEntry{}, Waiting: []QueueEntry{}}
+	for _, e := range m.running {
+		started := e.startedAt
+		status.Running = append(status.Running, QueueEntry{
+			ID: e.id, State: "running", Priority: e.priority.String(),
+			Origin: e.origin, EnqueuedAt: e.enqueuedAt, StartedAt: &started,
+		})
+	}
+	sort.Slice(status.Running, func(i, j int) bool { return status.Running[i].ID < status.Running[j].ID })
+
+	waiting := append([]*queueEntry(nil), m.waiting...)
+	sort.SliceStable(waiting, func(i, j int) bool { return waiting[i].priority > waiting[j].priority })
+	for i, e := range waiting {
+		status.Waiting = append(status.Waiting, QueueEntry{
+			ID: e.id, State: "waiting", Position: i + 1, Priority: e.priority.String(),
+			Origin: e.origin, EnqueuedAt: e.enqueuedAt,
+		})
+	}
+	return status
+}
+
+// GetQueue returns the global manager's queue
+func GetQueue() QueueStatus {
+	return GetManager().Queue()
+}
+
+// CancelQueued cancels a waiting entry of the global manager
+func CancelQueued(id int64) error {
+	return GetManager().CancelQueued(id)
+}
@@ -0,0 +1,193 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// enqueue starts acquiring a slot in the background and waits until the entry is queued
+func enqueue(t *testing.T, mgr *Manager, priority Priority, origin Origin) <-chan *queueEntry {
+	t.Helper()
+	before := mgr.QueueLength() + mgr.Used()
+	got := make(chan *queueEntry, 1)
+	go func() {
+		e, err := mgr.acquire(context.Background(), priority, origin)
+		if err != nil {
+			e = nil
+		}
+		got <- e
+	}()
+	deadline := time.Now().Add(time.Second)
+	for mgr.QueueLength()+mgr.Used() == before {
+		if time.Now().After(deadline) {
+			t.Fatal("Entry was not queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return got
+}
+
+func TestQueuePriorityOrder(t *testing.T) {
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1})
+	first, err := mgr.acquire(context.Background(), PriorityBatch, Origin{TaskID: 1})
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+
+	batch := enqueue(t, mgr, PriorityBatch, Origin{TaskID: 2})
+	schedule := enqueue(t, mgr, PrioritySchedule, Origin{ScheduleID: 3})
+	interactive := enqueue(t, mgr, PriorityInteractive, Origin{MessageID: 4})
+
+	q := mgr.Queue()
+	if len(q.Running) != 1 || len(q.Waiting) != 3 {
+		t.Fatalf("Unexpected queue: %+v", q)
+	}
+	if q.Waiting[0].Priority != "interactive" || q.Waiting[1].Priority != "schedule" || q.Waiting[2].Priority != "batch" {
+		t.Errorf("Expected dispatch order interactive, schedule, batch: %+v", q.Waiting)
+	}
+	if q.Waiting[0].Position != 1 || q.Waiting[2].Position != 3 {
+		t.Errorf("Unexpected positions: %+v", q.Waiting)
+	}
+
+	// Each release hands the slot to the next entry in priority order
+	mgr.release(first)
+	e := <-interactive
+	if e == nil || e.origin.MessageID != 4 {
+		t.Fatalf("Expected interactive entry first, got %+v", e)
+	}
+	mgr.release(e)
+	e = <-schedule
+	if e == nil || e.origin.ScheduleID != 3 {
+		t.Fatalf("Expected schedule entry second, got %+v", e)
+	}
+	mgr.release(e)
+	e = <-batch
+	if e == nil || e.origin.TaskID != 2 {
+		t.Fatalf("Expected batch entry last, got %+v", e)
+	}
+	mgr.release(e)
+	mgr.release(e) // idempotent
+
+	if mgr.Used() != 0 || mgr.QueueLength() != 0 {
+		t.Errorf("Expected empty queue, used %d waiting %d", mgr.Used(), mgr.QueueLength())
+	}
+}
+
+func TestQueueFIFO(t *testing.T) {
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1})
+	first, _ := mgr.acquire(context.Background(), PriorityBatch, Origin{})
+
+	a := enqueue(t, mgr, PriorityBatch, Origin{TaskID: 1})
+	b := enqueue(t, mgr, PriorityBatch, Origin{TaskID: 2})
+
+	mgr.release(first)
+	e := <-a
+	if e == nil || e.origin.TaskID != 1 {
+		t.Fatalf("Expected oldest entry first, got %+v", e)
+	}
+	mgr.release(e)
+	if e = <-b; e == nil {
+		t.Fatal("Expected second entry to run")
+	}
+	mgr.release(e)
+}
+
+func TestQueueProjectQuota(t *testing.T) {
+	SetQuotaFunc(func(project string) int {
+		if project == "/p/busy" {
+			return 1
+		}
+		return 0
+	})
+	defer SetQuotaFunc(nil)
+
+	mgr := newManager(Config{Timeout: time.Minute, Max: 2})
+	busy, _ := mgr.acquire(context.Background(), PriorityBatch, Origin{Project: "/p/busy", TaskID: 1})
+
+	// The second busy run waits although a slot is free; another project passes it
+	blocked := enqueue(t, mgr, PriorityInteractive, Origin{Project: "/p/busy", TaskID: 2})
+	other, err := mgr.acquire(context.Background(), PriorityBatch, Origin{Project: "/p/other"})
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	if mgr.QueueLength() != 1 {
+		t.Errorf("Expected quota-blocked entry waiting, got %d", mgr.QueueLength())
+	}
+	mgr.release(other)
+	if mgr.QueueLength() != 1 {
+		t.Errorf("Expected entry still blocked by quota")
+	}
+
+	mgr.release(busy)
+	e := <-blocked
+	if e == nil || e.origin.TaskID != 2 {
+		t.Fatalf("Expected blocked entry to run, got %+v", e)
+	}
+	mgr.release(e)
+}
+
+func TestQueueCancel(t *testing.T) {
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1})
+	first, _ := mgr.acquire(context.Background(), PriorityBatch, Origin{})
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := mgr.acquire(context.Background(), PriorityBatch, Origin{TaskID: 7})
+		errCh <- err
+	}()
+	for mgr.QueueLength() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	id := mgr.Queue().Waiting[0].ID
+
+	if err := mgr.CancelQueued(first.id); err == nil {
+		t.Error("Expected running entry not to be cancellable")
+	}
+	if err := mgr.CancelQueued(id); err != nil {
+		t.Fatalf("CancelQueued failed: %v", err)
+	}
+	if err := <-errCh; !errors.Is(err, ErrQueueCancelled) {
+		t.Errorf("Expected ErrQueueCancelled, got %v", err)
+	}
+	if err := mgr.CancelQueued(id); err == nil {
+		t.Error("Expected cancelled entry to be gone")
+	}
+	mgr.release(first)
+	if mgr.Used() != 0 || mgr.QueueLength() != 0 {
+		t.Errorf("Expected empty queue")
+	}
+}
+
+func TestQueueContextCancel(t *testing.T) {
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1})
+	first, _ := mgr.acquire(context.Background(), PriorityBatch, Origin{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := mgr.acquire(ctx, PriorityBatch, Origin{}); err == nil {
+		t.Error("Expected acquire to fail on context cancel")
+	}
+	if mgr.QueueLength() != 0 {
+		t.Errorf("Expected cancelled entry removed, got %d waiting", mgr.QueueLength())
+	}
+	mgr.release(first)
+	if mgr.Available() != 1 {
+		t.Errorf("Expected slot free, got %d available", mgr.Available())
+	}
+}
+
+func TestOriginString(t *testing.T) {
+	cases := map[string]Origin{
+		"-":               {},
+		"proj task #3":    {Project: "/home/me/proj", TaskID: 3},
+		"proj message #9": {Project: "/home/me/proj", MessageID: 9},
+		"schedule #2":     {ScheduleID: 2},
+	}
+	for want, o := range cases {
+		if got := o.String(); got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+}
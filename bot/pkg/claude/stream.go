@@ -0,0 +1,383 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// StreamAgent is the agent name of the stream-json execution mode
+const StreamAgent = "claude-stream"
+
+// streamExitWait is how long the process may take to exit after the result event
+var streamExitWait = 5 * time.Second
+
+// unsupportedFlagPattern matches the usage error of a CLI without stream-json support
+var unsupportedFlagPattern = regexp.MustCompile(`(?i)(unknown|unrecognized|invalid) (option|argument|flag)|usage:`)
+
+func init() {
+	RegisterAgent(streamAgent{})
+}
+
+// StreamEvent is one line of Claude Code's `--output-format stream-json` output
+type StreamEvent struct {
+	Type      string         `json:"type"`              // system, assistant, user, result
+	Subtype   string         `json:"subtype,omitempty"` // system: init / result: success, error_max_turns, error_during_execution
+	SessionID string         `json:"session_id,omitempty"`
+	Message   *StreamMessage `json:"message,omitempty"` // assistant, user
+
+	// system init
+	Model string `json:"model,omitempty"`
+
+	// result
+	Result       string      `json:"result,omitempty"`
+	IsError      bool        `json:"is_error,omitempty"`
+	NumTurns     int         `json:"num_turns,omitempty"`
+	DurationMs   int64       `json:"duration_ms,omitempty"`
+	TotalCostUSD float64     `json:"total_cost_usd,omitempty"`
+	Usage        *TokenUsage `json:"usage,omitempty"`
+}
+
+// StreamMessage is the API message carried by assistant and user events
+type StreamMessage struct {
+	ID      string         `json:"id,omitempty"`
+	Role    string         `json:"role"`
+	Content []ContentBlock `json:"content"`
+}
+
+// ContentBlock is a text, tool_use or tool_result block of a message
+type ContentBlock struct {
+	Type string `json:"type"`
+
+	// text
+	Text string `json:"text,omitempty"`
+
+	// tool_use
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// tool_result
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// TokenUsage is the token count of a run
+type TokenUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// ToolCall is a tool invoked by Claude during a run
+type ToolCall struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Input   json.RawMessage `json:"input,omitempty"`
+	IsError bool            `json:"is_error,omitempty"` // the tool result was an error
+}
+
+// streamAgent runs Claude Code in print mode with stream-json output.
+// Completion is the final result event instead of PTY idle/report polling;
+// when the CLI rejects the stream-json flags it falls back to the PTY agent.
+type streamAgent struct{}
+
+// Name returns the agent name
+func (streamAgent) Name() string { return StreamAgent }
+
+// Run executes Claude Code and collects the stream events into a Result
+func (streamAgent) Run(ctx context.Context, opts Options) (*Result, error) {
+	if opts.ReportPath != "" {
+		// Remove report file if it exists from previous run
+		os.Remove(opts.ReportPath)
+	}
+
+	cmd := exec.CommandContext(ctx, "claude", buildStreamArgs(opts)...)
+	if opts.WorkDir != "" {
+		cmd.Dir = opts.WorkDir
+	}
+	// Own process group, so tools still running (and holding stdout) die with Claude
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	kill := func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	cmd.Cancel = kill
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open claude stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start claude: %w", err)
+	}
+
+	// Read events line by line (lines can be large: tool inputs, file contents)
+	lines := make(chan []byte, 16)
+	go func() {
+		defer close(lines)
+		r := bufio.NewReaderSize(stdout, 64*1024)
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(line) > 0 {
+				lines <- line
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	c := newStreamCollector()
+	idle := newIdleTimer(opts.Timeout)
+	defer idle.Stop()
+
+	var runErr error
+	var lineCount int
+read:
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				break read
+			}
+			idle.Reset()
+			lineCount++
+			if opts.Transcript != nil {
+				opts.Transcript.Write(line)
+			}
+			c.addLine(line)
+			if c.final != nil {
+				break read
+			}
+		case <-idle.C():
+			runErr = fmt.Errorf("idle timeout: no output for %v", opts.Timeout)
+			break read
+		case <-ctx.Done():
+			runErr = fmt.Errorf("execution cancelled: %w", ctx.Err())
+			break read
+		}
+	}
+
+	// The process exits right after the result event; kill it if it doesn't
+	drained := make(chan struct{})
+	go func() {
+		for range lines {
+		}
+		close(drained)
+	}()
+	if runErr != nil {
+		kill()
+	}
+	select {
+	case <-drained:
+	case <-time.After(streamExitWait):
+		kill()
+		<-drained
+	}
+	waitErr := cmd.Wait()
+	if runErr != nil {
+		return nil, runErr
+	}
+
+	exitCode := 0
+	if waitErr != nil {
+		if exitErr, ok := waitErr.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		} else if ctx.Err() != nil {
+			return nil, fmt.Errorf("execution cancelled: %w", ctx.Err())
+		}
+	}
+
+	if c.events == 0 {
+		errText := strings.TrimSpace(stderr.String())
+		// Older CLI that rejected the flags before doing anything: run the PTY way
+		if exitCode != 0 && lineCount == 0 && unsupportedFlagPattern.MatchString(errText) {
+			log.Printf("[Claude] stream-json not supported (exit: %d), falling back to PTY: %s", exitCode, errText)
+			return ptyAgent{}.Run(ctx, opts)
+		}
+		// The run itself went through (and may have changed files): never repeat it
+		return nil, fmt.Errorf("claude produced no stream-json events (exit: %d, %d output lines): %s", exitCode, lineCount, errText)
+	}
+
+	result := c.result(exitCode)
+	if result.Output == "" && result.ExitCode != 0 {
+		result.Output = strings.TrimSpace(stderr.String())
+	}
+	if opts.ReportPath != "" {
+		if data, err := os.ReadFile(opts.ReportPath); err == nil && len(data) > 0 {
+			log.Printf("[Claude] Report file found: %s (%d bytes)", opts.ReportPath, len(data))
+			result.Output = string(data)
+		}
+	}
+	return result, nil
+}
+
+// StartSession uses the PTY agent (interactive mode has no stream-json output)
+func (streamAgent) StartSession(ctx context.Context, opts Options) (AgentSession, error) {
+	return ptyAgent{}.StartSession(ctx, opts)
+}
+
+// IsAuthError checks if a result indicates an authentication error
+func (streamAgent) IsAuthError(result *Result) bool {
+	return ptyAgent{}.IsAuthError(result)
+}
+
+// streamCollector accumulates stream events into a Result
+type streamCollector struct {
+	events    int
+	sessionID string
+	messages  map[string]bool // assistant message IDs (turns without a result event)
+	text      []string
+	calls     []ToolCall
+	callIndex map[string]int
+	final     *StreamEvent
+}
+
+func newStreamCollector() *streamCollector {
+	return &streamCollector{
+		messages:  make(map[string]bool),
+		callIndex: make(map[string]int),
+	}
+}
+
+// addLine parses one output line; lines that are not stream events are ignored
+func (c *streamCollector) addLine(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return
+	}
+	var ev StreamEvent
+	if err := json.Unmarshal(line, &ev); err != nil || ev.Type == "" {
+		return
+	}
+	c.add(ev)
+}
+
+// add records one event
+func (c *streamCollector) add(ev StreamEvent) {
+	c.events++
+	if ev.SessionID != "" {
+		c.sessionID = ev.SessionID
+	}
+
+	switch ev.Type {
+	case "assistant":
+		if ev.Message == nil {
+			return
+		}
+		if ev.Message.ID != "" {
+			c.messages[ev.Message.ID] = true
+		}
+		for _, b := range ev.Message.Content {
+			switch b.Type {
+			case "text":
+				if b.Text != "" {
+					c.text = append(c.text, b.Text)
+				}
+			case "tool_use":
+				c.callIndex[b.ID] = len(c.calls)
+				c.calls = append(c.calls, ToolCall{ID: b.ID, Name: b.Name, Input: b.Input})
+			}
+		}
+	case "user":
+		if ev.Message == nil {
+			return
+		}
+		for _, b := range ev.Message.Content {
+			if i, ok := c.callIndex[b.ToolUseID]; ok && b.Type == "tool_result" && b.IsError {
+				c.calls[i].IsError = true
+			}
+		}
+	case "result":
+		c.final = &ev
+	}
+}
+
+// result builds the Result. The result event decides success; without one
+// (process died) the assistant text and the process exit code are used.
+func (c *streamCollector) result(exitCode int) *Result {
+	r := &Result{
+		ExitCode:  exitCode,
+		SessionID: c.sessionID,
+		Turns:     len(c.messages),
+		ToolCalls: c.calls,
+	}
+	if c.final == nil {
+		r.Output = strings.Join(c.text, "\n")
+		if r.ExitCode == 0 {
+			r.ExitCode = 1
+		}
+		return r
+	}
+
+	r.Output = c.final.Result
+	if r.Output == "" && len(c.text) > 0 {
+		r.Output = c.text[len(c.text)-1]
+	}
+	if c.final.NumTurns > 0 {
+		r.Turns = c.final.NumTurns
+	}
+	r.Usage = c.final.Usage
+	r.CostUSD = c.final.TotalCostUSD
+	r.DurationMs = c.final.DurationMs
+	switch {
+	case c.final.IsError && r.ExitCode == 0:
+		r.ExitCode = 1
+	case !c.final.IsError:
+		r.ExitCode = 0
+	}
+	return r
+}
+
+// idleTimer fires when no output arrived for the idle timeout (never when 0)
+type idleTimer struct {
+	d time.Duration
+	t *time.Timer
+}
+
+func newIdleTimer(d time.Duration) *idleTimer {
+	it := &idleTimer{d: d}
+	if d > 0 {
+		it.t = time.NewTimer(d)
+	}
+	return it
+}
+
+// C returns the timer channel (nil blocks forever)
+func (it *idleTimer) C() <-chan time.Time {
+	if it.t == nil {
+		return nil
+	}
+	return it.t.C
+}
+
+// Reset restarts the timeout after output
+func (it *idleTimer) Reset() {
+	if it.t != nil {
+		it.t.Reset(it.d)
+	}
+}
+
+// Stop releases the timer
+func (it *idleTimer) Stop() {
+	if it.t != nil {
+		it.t.Stop()
+	}
+}
+
+// buildStreamArgs constructs command line arguments for print mode with stream-json output
+func buildStreamArgs(opts Options) []string {
+	args := buildArgs(opts)
+	// --verbose is required for stream-json in print mode
+	stream := []string{"--output-format", "stream-json", "--verbose"}
+	return append(append(args[:2:2], stream...), args[2:]...)
+}
@@ -0,0 +1,138 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClaudeScript answers stream-json runs according to FAKE_CLAUDE_MODE
+// and prints plain text in PTY mode (no --output-format flag).
+const fakeClaudeScript = `#!/bin/sh
+case "$*" in
+*--output-format*) ;;
+*) echo "pty output"; exit 0 ;;
+esac
+case "$FAKE_CLAUDE_MODE" in
+success)
+  echo '{"type":"system","subtype":"init","session_id":"s-1","model":"sonnet"}'
+  echo 'not json'
+  echo '{"type":"assistant","message":{"id":"m1","role":"assistant","content":[{"type":"text","text":"Reading"},{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"a.go"}}]},"session_id":"s-1"}'
+  echo '{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"no such file","is_error":true}]},"session_id":"s-1"}'
+  echo '{"type":"assistant","message":{"id":"m2","role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Write","input":{}}]},"session_id":"s-1"}'
+  [ -n "$FAKE_REPORT" ] && echo "# Report" > "$FAKE_REPORT"
+  echo '{"type":"result","subtype":"success","is_error":false,"num_turns":2,"duration_ms":1500,"total_cost_usd":0.0125,"result":"Done","session_id":"s-1","usage":{"input_tokens":100,"output_tokens":20,"cache_read_input_tokens":5}}'
+  sleep 30 ;;
+error)
+  echo '{"type":"result","subtype":"success","is_error":true,"result":"Invalid API key. Please login again.","session_id":"s-2"}' ;;
+hang)
+  echo '{"type":"system","subtype":"init","session_id":"s-3"}'
+  sleep 30 ;;
+plain)
+  echo "plain output" ;;
+*)
+  echo "error: unknown option '--output-format'" >&2; exit 1 ;;
+esac
+`
+
+// useFakeClaude puts a scripted claude binary first in PATH
+func useFakeClaude(t *testing.T, mode string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "claude"), []byte(fakeClaudeScript), 0755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_CLAUDE_MODE", mode)
+}
+
+func TestStreamAgentRun(t *testing.T) {
+	useFakeClaude(t, "success")
+	oldWait := streamExitWait
+	defer func() { streamExitWait = oldWait }()
+	streamExitWait = 100 * time.Millisecond
+	reportPath := filepath.Join(t.TempDir(), "report.md")
+	t.Setenv("FAKE_REPORT", reportPath)
+
+	mgr := newManager(Config{Timeout: 5 * time.Second, Max: 1})
+	start := time.Now()
+	result, err := mgr.Run(context.Background(), Options{Agent: StreamAgent, UserPrompt: "go"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Expected the result event to end the run")
+	}
+	if result.Output != "Done" || result.ExitCode != 0 || result.Agent != StreamAgent {
+		t.Errorf("Unexpected result: %q exit %d agent %s", result.Output, result.ExitCode, result.Agent)
+	}
+	if result.SessionID != "s-1" || result.Turns != 2 || result.CostUSD != 0.0125 || result.DurationMs != 1500 {
+		t.Errorf("Unexpected details: %+v", result)
+	}
+	if result.Usage == nil || result.Usage.InputTokens != 100 || result.Usage.OutputTokens != 20 || result.Usage.CacheReadInputTokens != 5 {
+		t.Errorf("Unexpected usage: %+v", result.Usage)
+	}
+	if len(result.ToolCalls) != 2 || result.ToolCalls[0].Name != "Read" || !result.ToolCalls[0].IsError || result.ToolCalls[1].IsError {
+		t.Errorf("Unexpected tool calls: %+v", result.ToolCalls)
+	}
+	if !strings.Contains(string(result.ToolCalls[0].Input), "a.go") {
+		t.Errorf("Expected tool input, got %s", result.ToolCalls[0].Input)
+	}
+
+	// With a report path the report file is the output
+	result, err = mgr.Run(context.Background(), Options{Agent: StreamAgent, ReportPath: reportPath})
+	if err != nil || strings.TrimSpace(result.Output) != "# Report" {
+		t.Errorf("Expected report output, got %+v (%v)", result, err)
+	}
+}
+
+func TestStreamAgentError(t *testing.T) {
+	useFakeClaude(t, "error")
+	result, err := streamAgent{}.Run(context.Background(), Options{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	result.Agent = StreamAgent
+	if result.ExitCode != 1 || !IsAuthError(result) {
+		t.Errorf("Expected auth error result, got %+v", result)
+	}
+}
+
+func TestStreamAgentFallback(t *testing.T) {
+	useFakeClaude(t, "")
+	result, err := streamAgent{}.Run(context.Background(), Options{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(result.Output, "pty output") {
+		t.Errorf("Expected PTY fallback output, got %q", result.Output)
+	}
+}
+
+func TestStreamAgentNoEvents(t *testing.T) {
+	// The flags were accepted but no events came: the run is not repeated in PTY mode
+	useFakeClaude(t, "plain")
+	result, err := streamAgent{}.Run(context.Background(), Options{Timeout: 5 * time.Second})
+	if err == nil || !strings.Contains(err.Error(), "no stream-json events") {
+		t.Errorf("Expected an error without a second run, got %+v (%v)", result, err)
+	}
+}
+
+func TestStreamAgentIdleTimeout(t *testing.T) {
+	useFakeClaude(t, "hang")
+	_, err := streamAgent{}.Run(context.Background(), Options{Timeout: 200 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "idle timeout") {
+		t.Errorf("Expected idle timeout, got %v", err)
+	}
+}
+
+func TestBuildStreamArgs(t *testing.T) {
+	args := buildStreamArgs(Options{Model: "opus", UserPrompt: "hi"})
+	want := "-p --dangerously-skip-permissions --output-format stream-json --verbose --model opus hi"
+	if got := strings.Join(args, " "); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,222 @@
+package claude
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// runDirName is the directory (under project/.claribot) holding run transcripts
+const runDirName = "runs"
+
+// runIDPattern matches run IDs created by NewRunID (time-sortable, safe as a file name)
+var runIDPattern = regexp.MustCompile(`^\d{8}-\d{6}-[0-9a-f]{6}$`)
+
+// NewRunID returns a new run ID like "20250101-120000-a1b2c3"
+func NewRunID() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// ValidRunID reports whether id is a well-formed run ID
+func ValidRunID(id string) bool {
+	return runIDPattern.MatchString(id)
+}
+
+// RunDir returns the transcript directory of a project (project/.claribot/runs)
+func RunDir(projectPath string) string {
+	return filepath.Join(projectPath, ".claribot", runDirName)
+}
+
+// TranscriptPath returns the ANSI-stripped transcript of a run ({id}.log),
+// or the raw terminal output ({id}.raw.log)
+func TranscriptPath(projectPath, runID string, raw bool) string {
+	if raw {
+		return filepath.Join(RunDir(projectPath), runID+".raw.log")
+	}
+	return filepath.Join(RunDir(projectPath), runID+".log")
+}
+
+// transcript records the raw output of one run. Agents write to it while
+// running; finish writes the stripped log with a header and an outcome line.
+type transcript struct {
+	mu      sync.Mutex
+	id      string
+	base    string // project path
+	raw     *os.File
+	max     int64 // raw output cap in bytes
+	written int64
+	dropped int64
+	header  string
+	started time.Time
+}
+
+// openTranscript creates the raw transcript file of a run
+func openTranscript(base, id string, max int64, agent string, opts Options) (*transcript, error) {
+	if err := os.MkdirAll(RunDir(base), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(TranscriptPath(base, id, true), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	started := time.Now()
+	var h strings.Builder
+	h.WriteString(fmt.Sprintf("# run %s\n", id))
+	h.WriteString(fmt.Sprintf("# agent: %s\n", agent))
+	h.WriteString(fmt.Sprintf("# origin: %s (%s)\n", opts.Origin, opts.Priority))
+	if opts.WorkDir != "" {
+		h.WriteString(fmt.Sprintf("# workdir: %s\n", opts.WorkDir))
+	}
+	if opts.Model != "" {
+		h.WriteString(fmt.Sprintf("# model: %s\n", opts.Model))
+	}
+	h.WriteString(fmt.Sprintf("# started: %s\n\n", started.Format(time.RFC3339)))
+	return &transcript{id: id, base: base, raw: f, max: max, header: h.String(), started: started}, nil
+}
+
+// Write appends raw output up to the cap. It never fails, so a full disk
+// or a late write after finish does not break the run.
+func (t *transcript) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.raw == nil {
+		return len(p), nil
+	}
+	n := int64(len(p))
+	if room := t.max - t.written; n > room {
+		if room < 0 {
+			room = 0
+		}
+		t.dropped += n - room
+		p = p[:room]
+	}
+	if len(p) > 0 {
+		if _, err := t.raw.Write(p); err != nil {
+			log.Printf("[Claude] Transcript write failed (run %s): %v", t.id, err)
+			t.raw.Close()
+			t.raw = nil
+			return int(n), nil
+		}
+		t.written += int64(len(p))
+	}
+	return int(n), nil
+}
+
+// finish closes the raw file and writes the stripped transcript
+func (t *transcript) finish(result *Result, runErr error) {
+	t.mu.Lock()
+	if t.raw != nil {
+		t.raw.Close()
+		t.raw = nil
+	}
+	dropped := t.dropped
+	t.mu.Unlock()
+
+	raw, err := os.ReadFile(TranscriptPath(t.base, t.id, true))
+	if err != nil {
+		log.Printf("[Claude] Transcript read failed (run %s): %v", t.id, err)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(t.header)
+	sb.WriteString(stripANSI(string(raw)))
+	if len(raw) > 0 && raw[len(raw)-1] != '\n' {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	if dropped > 0 {
+		sb.WriteString(fmt.Sprintf("# truncated: %d bytes over the %d byte cap dropped\n", dropped, t.max))
+	}
+	sb.WriteString(fmt.Sprintf("# finished: %s (%s)\n", time.Now().Format(time.RFC3339), time.Since(t.started).Round(time.Second)))
+	switch {
+	case runErr != nil:
+		sb.WriteString(fmt.Sprintf("# error: %v\n", runErr))
+	case result != nil:
+		sb.WriteString(fmt.Sprintf("# exit code: %d\n", result.ExitCode))
+	}
+
+	if err := os.WriteFile(TranscriptPath(t.base, t.id, false), []byte(sb.String()), 0644); err != nil {
+		log.Printf("[Claude] Transcript save failed (run %s): %v", t.id, err)
+	}
+}
+
+// ReadTranscript returns the stripped transcript of a run. While the run is
+// still going (or was interrupted) only the raw file exists; it is stripped on the fly.
+func ReadTranscript(projectPath, runID string) ([]byte, time.Time, error) {
+	if !ValidRunID(runID) {
+		return nil, time.Time{}, fmt.Errorf("invalid run id: %s", runID)
+	}
+	path := TranscriptPath(projectPath, runID, false)
+	if info, err := os.Stat(path); err == nil {
+		data, err := os.ReadFile(path)
+		return data, info.ModTime(), err
+	}
+	rawPath := TranscriptPath(projectPath, runID, true)
+	info, err := os.Stat(rawPath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(rawPath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return []byte(stripANSI(string(data))), info.ModTime(), nil
+}
+
+// pruneTranscripts deletes transcripts older than keepDays and all but the
+// newest keepRuns runs of a directory (0 or less disables the respective limit)
+func pruneTranscripts(dir string, keepDays, keepRuns int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	// Group files by run ID (IDs sort by start time)
+	files := make(map[string][]string)
+	for _, e := range entries {
+		id := strings.TrimSuffix(strings.TrimSuffix(e.Name(), ".log"), ".raw")
+		if ValidRunID(id) {
+			files[id] = append(files[id], e.Name())
+		}
+	}
+	ids := make([]string, 0, len(files))
+	for id := range files {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	cutoff := time.Now().AddDate(0, 0, -keepDays)
+	removed := 0
+	for i, id := range ids {
+		expired := false
+		if keepRuns > 0 && i >= keepRuns {
+			expired = true
+		}
+		if keepDays > 0 {
+			if started, err := time.ParseInLocation("20060102-150405", id[:15], time.Local); err == nil && started.Before(cutoff) {
+				expired = true
+			}
+		}
+		if !expired {
+			continue
+		}
+		for _, name := range files[id] {
+			os.Remove(filepath.Join(dir, name))
+		}
+		removed++
+	}
+	if removed > 0 {
+		log.Printf("[Claude] Pruned %d old transcripts in %s", removed, dir)
+	}
+}
@@ -0,0 +1,137 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunTranscript(t *testing.T) {
+	project := t.TempDir()
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1, TranscriptMaxBytes: 1 << 20})
+	RegisterAgent(NewFakeAgent("fake",
+		FakeStep{Output: "\x1b[31mred\x1b[0m output\n"},
+		FakeStep{Output: "partial", Err: errors.New("crashed")},
+	))
+	defer UnregisterAgent("fake")
+
+	result, err := mgr.Run(context.Background(), Options{Agent: "fake", Origin: Origin{Project: project, TaskID: 3}})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !ValidRunID(result.RunID) {
+		t.Fatalf("Expected run ID, got %q", result.RunID)
+	}
+
+	raw, _ := os.ReadFile(TranscriptPath(project, result.RunID, true))
+	if string(raw) != "\x1b[31mred\x1b[0m output\n" {
+		t.Errorf("Unexpected raw transcript: %q", raw)
+	}
+	log, _ := os.ReadFile(TranscriptPath(project, result.RunID, false))
+	for _, want := range []string{"# run " + result.RunID, "# agent: fake", "task #3", "red output", "# exit code: 0"} {
+		if !strings.Contains(string(log), want) {
+			t.Errorf("Expected %q in transcript:\n%s", want, log)
+		}
+	}
+	if strings.Contains(string(log), "\x1b") {
+		t.Error("Expected ANSI sequences stripped")
+	}
+
+	// A failed run keeps its transcript under the caller's run ID
+	runID := NewRunID()
+	if _, err := mgr.Run(context.Background(), Options{Agent: "fake", RunID: runID, Origin: Origin{Project: project}}); err == nil {
+		t.Fatal("Expected scripted error")
+	}
+	log, _ = os.ReadFile(TranscriptPath(project, runID, false))
+	if !strings.Contains(string(log), "partial") || !strings.Contains(string(log), "# error: crashed") {
+		t.Errorf("Expected error transcript:\n%s", log)
+	}
+}
+
+func TestRunTranscriptDisabled(t *testing.T) {
+	project := t.TempDir()
+	mgr := newManager(Config{Timeout: time.Minute, Max: 1})
+	RegisterAgent(NewFakeAgent("fake", FakeStep{Output: "hi"}))
+	defer UnregisterAgent("fake")
+
+	if _, err := mgr.Run(context.Background(), Options{Agent: "fake", WorkDir: project}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if _, err := os.Stat(RunDir(project)); !os.IsNotExist(err) {
+		t.Error("Expected no runs directory")
+	}
+}
+
+func TestTranscriptCap(t *testing.T) {
+	project := t.TempDir()
+	tr, err := openTranscript(project, NewRunID(), 8, "fake", Options{})
+	if err != nil {
+		t.Fatalf("openTranscript failed: %v", err)
+	}
+	tr.Write([]byte("12345"))
+	tr.Write([]byte("67890"))
+	tr.Write([]byte("abc"))
+	tr.finish(&Result{}, nil)
+	tr.Write([]byte("late")) // after finish: ignored
+
+	raw, _ := os.ReadFile(TranscriptPath(project, tr.id, true))
+	if string(raw) != "12345678" {
+		t.Errorf("Expected capped raw output, got %q", raw)
+	}
+	log, _ := os.ReadFile(TranscriptPath(project, tr.id, false))
+	if !strings.Contains(string(log), "# truncated: 5 bytes") {
+		t.Errorf("Expected truncation note:\n%s", log)
+	}
+}
+
+func TestReadTranscript(t *testing.T) {
+	project := t.TempDir()
+	tr, _ := openTranscript(project, NewRunID(), 1<<20, "fake", Options{})
+	tr.Write([]byte("\x1b[1mrunning\x1b[0m"))
+
+	// Still running: only the raw file exists
+	data, _, err := ReadTranscript(project, tr.id)
+	if err != nil || string(data) != "running" {
+		t.Errorf("Expected stripped raw output, got %q (%v)", data, err)
+	}
+
+	tr.finish(&Result{}, nil)
+	data, _, _ = ReadTranscript(project, tr.id)
+	if !strings.HasPrefix(string(data), "# run "+tr.id) {
+		t.Errorf("Expected final transcript, got %q", data)
+	}
+
+	if _, _, err := ReadTranscript(project, "../../etc/passwd"); err == nil {
+		t.Error("Expected invalid run ID to fail")
+	}
+}
+
+func TestPruneTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().AddDate(0, 0, -30).Format("20060102-150405") + "-000001"
+	ids := []string{old}
+	for i := 0; i < 3; i++ {
+		ids = append(ids, time.Now().Add(time.Duration(i)*time.Second).Format("20060102-150405")+"-00000"+string(rune('2'+i)))
+	}
+	for _, id := range ids {
+		os.WriteFile(filepath.Join(dir, id+".log"), []byte("x"), 0644)
+		os.WriteFile(filepath.Join(dir, id+".raw.log"), []byte("x"), 0644)
+	}
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644)
+
+	pruneTranscripts(dir, 14, 2)
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{ids[2] + ".log", ids[2] + ".raw.log", ids[3] + ".log", ids[3] + ".raw.log", "notes.txt"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+}
@@ -211,12 +211,19 @@ func RefreshUsageLive() error {
 	defer cancel()
 
 	// Start interactive session
-	session, err := StartSessionContext(ctx, Options{})
+	session, err := StartSessionContext(ctx, Options{Agent: DefaultAgent, Priority: PriorityInteractive})
 	if err != nil {
 		return fmt.Errorf("failed to start session: %w", err)
 	}
 	defer session.Close()
 
+	// /usage is a Claude Code command read straight from the PTY
+	ps, ok := session.conn.(*ptySession)
+	if !ok {
+		return fmt.Errorf("usage refresh requires the %s PTY agent", DefaultAgent)
+	}
+	ptmx := ps.pty
+
 	buf := make([]byte, 8192)
 
 	// Wait for Claude to initialize and read initial output
@@ -225,13 +232,13 @@ func RefreshUsageLive() error {
 
 	// Drain initial output
 	for i := 0; i < 5; i++ {
-		session.pty.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
-		session.pty.Read(buf)
+		ptmx.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		ptmx.Read(buf)
 	}
 
 	// Send /usage command
 	log.Printf("[Claude] Sending /usage command")
-	_, err = session.pty.Write([]byte("/usage\n"))
+	_, err = ptmx.Write([]byte("/usage\n"))
 	if err != nil {
 		return fmt.Errorf("failed to send /usage: %w", err)
 	}
@@ -242,8 +249,8 @@ func RefreshUsageLive() error {
 	maxTimeouts := 3
 
 	for consecutiveTimeouts < maxTimeouts {
-		session.pty.SetReadDeadline(time.Now().Add(3 * time.Second))
-		n, err := session.pty.Read(buf)
+		ptmx.SetReadDeadline(time.Now().Add(3 * time.Second))
+		n, err := ptmx.Read(buf)
 		if n > 0 {
 			output.Write(buf[:n])
 			consecutiveTimeouts = 0 // Reset on data received
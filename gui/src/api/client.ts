@@ -1,4 +1,4 @@
-import type { ClaribotResponse, StatusResponse } from '@/types'
+import type { ClaribotResponse, StatusResponse, TaskFilter } from '@/types'
 
 const API_BASE = '/api'
 
@@ -89,6 +89,43 @@ export const usageAPI = {
   refresh: () => apiPost('/usage/refresh'),
 }
 
+// --- Run Transcript API ---
+
+export const runAPI = {
+  // ANSI-stripped transcript of a Claude run (raw: terminal output as received)
+  log: async (id: string, raw = false): Promise<string> => {
+    const res = await fetch(`${API_BASE}/runs/${id}/log${raw ? '?raw=true' : ''}`, { credentials: 'include' })
+    if (!res.ok) {
+      throw new Error(`API error: ${res.status} ${res.statusText}`)
+    }
+    return res.text()
+  },
+  live: () => apiGet('/runs/live'),
+  // Server-sent events of a live run: "output" (LiveChunk) until "done" (LiveRun).
+  // Pass the last offset received to resume after a reconnect.
+  stream: (target: 'runs' | 'tasks' | 'messages', id: string | number, offset = 0, raw = false): EventSource => {
+    const params = new URLSearchParams()
+    if (offset > 0) params.set('offset', String(offset))
+    if (raw) params.set('raw', 'true')
+    const query = params.toString()
+    return new EventSource(`${API_BASE}/${target}/${id}/stream${query ? `?${query}` : ''}`, { withCredentials: true })
+  },
+  // HTTP status of a stream request, read without following the stream (404: no live run)
+  streamStatus: async (target: 'runs' | 'tasks' | 'messages', id: string | number): Promise<number> => {
+    const controller = new AbortController()
+    const res = await fetch(`${API_BASE}/${target}/${id}/stream`, { credentials: 'include', signal: controller.signal })
+    controller.abort()
+    return res.status
+  },
+}
+
+// --- Claude Queue API ---
+
+export const claudeQueueAPI = {
+  list: () => apiGet('/claude/queue'),
+  cancel: (id: number) => apiDelete(`/claude/queue/${id}`),
+}
+
 // --- Project API ---
 
 export const projectAPI = {
@@ -127,6 +164,8 @@ export const taskAPI = {
   },
   get: (id: number | string) =>
     apiGet(`/tasks/${id}`),
+  events: (id: number | string) =>
+    apiGet(`/tasks/${id}/events`),
   add: (spec: string, parentId?: number) =>
     apiPost('/tasks', { spec, parent_id: parentId }),
   set: (id: number | string, field: string, value: string) =>
@@ -145,6 +184,39 @@ export const taskAPI = {
     apiPost('/tasks/cycle', projectId ? { project_id: projectId } : undefined),
   stop: () =>
     apiPost('/tasks/stop'),
+  cancel: (id: number | string) =>
+    apiPost(`/tasks/${id}/cancel`),
+  filter: (filter: TaskFilter) => {
+    const params = new URLSearchParams()
+    for (const [key, value] of Object.entries(filter)) {
+      if (value) params.set(key, value)
+    }
+    return apiGet(`/tasks?${params.toString()}`)
+  },
+  query: (name: string) =>
+    apiGet(`/tasks?query=${encodeURIComponent(name)}`),
+  search: (q: string, page = 1) =>
+    apiGet(`/tasks/search?q=${encodeURIComponent(q)}&page=${page}`),
+}
+
+// --- Saved Query API ---
+
+export const queryAPI = {
+  list: () =>
+    apiGet('/queries'),
+  save: (name: string, filter: TaskFilter) =>
+    apiPut(`/queries/${encodeURIComponent(name)}`, filter),
+  delete: (name: string) =>
+    apiDelete(`/queries/${encodeURIComponent(name)}`),
+}
+
+// --- Traversal API ---
+
+export const traversalAPI = {
+  list: () =>
+    apiGet('/traversals'),
+  get: (id: number | string) =>
+    apiGet(`/traversals/${id}`),
 }
 
 // --- Message API ---
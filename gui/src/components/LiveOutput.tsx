@@ -0,0 +1,42 @@
+import { useEffect, useRef } from 'react'
+import { Loader2, Check, AlertCircle } from 'lucide-react'
+import { useLiveOutput } from '@/hooks/useLiveOutput'
+
+interface LiveOutputProps {
+  target: 'runs' | 'tasks' | 'messages'
+  id: string | number
+}
+
+// Output of a running Claude run, followed as it is produced
+export function LiveOutput({ target, id }: LiveOutputProps) {
+  const { output, run, missing } = useLiveOutput(target, id)
+  const preRef = useRef<HTMLPreElement>(null)
+
+  // Keep the latest output in view
+  useEffect(() => {
+    const el = preRef.current
+    if (el) el.scrollTop = el.scrollHeight
+  }, [output])
+
+  const failed = run !== null && (run.exit_code !== 0 || !!run.error)
+
+  return (
+    <div className="rounded-md border bg-muted/40">
+      <div className="flex items-center gap-1.5 px-2 py-1 text-xs text-muted-foreground border-b">
+        {missing ? (
+          <AlertCircle className="h-3 w-3" />
+        ) : run === null ? (
+          <Loader2 className="h-3 w-3 animate-spin" />
+        ) : failed ? (
+          <AlertCircle className="h-3 w-3 text-destructive" />
+        ) : (
+          <Check className="h-3 w-3 text-green-600" />
+        )}
+        {missing ? '실행 정보 없음' : run === null ? '실행 중' : failed ? `실행 종료 (exit ${run.exit_code})` : '실행 종료'}
+      </div>
+      <pre ref={preRef} className="max-h-64 overflow-auto p-2 text-xs font-mono whitespace-pre-wrap break-words">
+        {output || '출력 대기 중...'}
+      </pre>
+    </div>
+  )
+}
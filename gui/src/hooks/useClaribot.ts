@@ -1,6 +1,6 @@
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
-import { projectAPI, taskAPI, specAPI, messageAPI, scheduleAPI, statusAPI, fileAPI, health } from '@/api/client'
-import type { StatusResponse } from '@/types'
+import { projectAPI, taskAPI, specAPI, messageAPI, scheduleAPI, statusAPI, fileAPI, runAPI, health } from '@/api/client'
+import type { StatusResponse, LiveRun } from '@/types'
 
 // --- Health ---
 export function useHealth() {
@@ -25,6 +25,17 @@ export function useStatus() {
   })
 }
 
+// --- Live runs ---
+// Claude runs with output to follow (ended runs stay listed for a while)
+export function useLiveRuns() {
+  return useQuery({
+    queryKey: ['liveRuns'],
+    queryFn: runAPI.live,
+    select: (data) => (data?.data ?? []) as LiveRun[],
+    refetchInterval: 5_000,
+  })
+}
+
 // --- Projects ---
 export function useProjects(all = true) {
   return useQuery({
@@ -0,0 +1,65 @@
+import { useEffect, useState } from 'react'
+import { runAPI } from '@/api/client'
+import type { LiveChunk, LiveRun } from '@/types'
+
+// Output kept on the client (same as the server's live buffer)
+const LIVE_MAX_CHARS = 256 * 1024
+
+// Follows the output stream of a live run until its "done" event.
+// After a dropped connection it resumes from the last offset received
+// (EventSource alone would reconnect from the start and repeat the output),
+// unless the server no longer has the run (404: not started here or expired).
+export function useLiveOutput(target: 'runs' | 'tasks' | 'messages', id?: string | number, enabled = true) {
+  const [output, setOutput] = useState('')
+  const [run, setRun] = useState<LiveRun | null>(null)
+  const [missing, setMissing] = useState(false)
+
+  useEffect(() => {
+    setOutput('')
+    setRun(null)
+    setMissing(false)
+    if (!enabled || id === undefined) return
+
+    let offset = 0
+    let source: EventSource | null = null
+    let retry: ReturnType<typeof setTimeout> | undefined
+    let finished = false
+
+    const connect = () => {
+      source = runAPI.stream(target, id, offset)
+      source.addEventListener('output', (e) => {
+        const chunk: LiveChunk = JSON.parse((e as MessageEvent).data)
+        offset = chunk.offset
+        if (chunk.data) setOutput(prev => (prev + chunk.data).slice(-LIVE_MAX_CHARS))
+      })
+      source.addEventListener('done', (e) => {
+        finished = true
+        source?.close()
+        setRun(JSON.parse((e as MessageEvent).data))
+      })
+      source.onerror = () => {
+        source?.close()
+        if (finished) return
+        // EventSource does not expose the status code, so ask once before retrying
+        runAPI.streamStatus(target, id).catch(() => 0).then(status => {
+          if (finished) return
+          if (status === 404) {
+            finished = true
+            setMissing(true)
+            return
+          }
+          retry = setTimeout(connect, 2_000)
+        })
+      }
+    }
+    connect()
+
+    return () => {
+      finished = true
+      clearTimeout(retry)
+      source?.close()
+    }
+  }, [target, id, enabled])
+
+  return { output, run, missing, done: run !== null || missing }
+}
@@ -5,10 +5,11 @@ import { Badge } from '@/components/ui/badge'
 import { Textarea } from '@/components/ui/textarea'
 import { ScrollArea } from '@/components/ui/scroll-area'
 import { Separator } from '@/components/ui/separator'
-import { useMessages, useMessage, useSendMessage } from '@/hooks/useClaribot'
+import { useMessages, useMessage, useSendMessage, useLiveRuns } from '@/hooks/useClaribot'
 import { Send, MessageSquare, ArrowLeft } from 'lucide-react'
 import { MarkdownRenderer } from '@/components/MarkdownRenderer'
 import { ChatBubble } from '@/components/ChatBubble'
+import { LiveOutput } from '@/components/LiveOutput'
 
 type MobileView = 'chat' | 'detail'
 
@@ -21,6 +22,7 @@ export default function Messages() {
   // When global: show all messages; when project selected: filter by project
   const { data: messagesData } = useMessages(isGlobal, isGlobal ? undefined : currentProject)
   const sendMessage = useSendMessage()
+  const { data: liveRuns } = useLiveRuns()
   const [input, setInput] = useState('')
   const [searchParams, setSearchParams] = useSearchParams()
   const initialId = searchParams.get('id')
@@ -182,6 +184,13 @@ export default function Messages() {
                         isSelected={isSelected}
                         onDetailClick={() => handleDetailClick(id)}
                       />
+
+                      {/* Output while Claude works on it */}
+                      {status === 'processing' && liveRuns?.some(r => r.origin.message_id === id) && (
+                        <div className="max-w-[80%] mb-3">
+                          <LiveOutput target="messages" id={id} />
+                        </div>
+                      )}
                     </div>
                   )
                 })}
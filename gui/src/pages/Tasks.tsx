@@ -9,13 +9,14 @@ import { Textarea } from '@/components/ui/textarea'
 import { ScrollArea } from '@/components/ui/scroll-area'
 import { Tabs, TabsList, TabsTrigger, TabsContent } from '@/components/ui/tabs'
 import {
-  useTasks, useTask, useAddTask, useDeleteTask, useTaskCycle, useTaskStop, useSetTask, useStatus
+  useTasks, useTask, useAddTask, useDeleteTask, useTaskCycle, useTaskStop, useSetTask, useStatus, useLiveRuns, useProject
 } from '@/hooks/useClaribot'
-import type { StatusResponse } from '@/types'
+import type { Project, StatusResponse } from '@/types'
 import {
   Plus, Play, RefreshCw, ChevronRight, ChevronDown, X, TreePine, List, FileText, Square
 } from 'lucide-react'
 import { MarkdownRenderer } from '@/components/MarkdownRenderer'
+import { LiveOutput } from '@/components/LiveOutput'
 
 function useMediaQuery(query: string): boolean {
   const [matches, setMatches] = useState(() =>
@@ -62,6 +63,12 @@ export default function Tasks() {
 
   const { data: taskDetail } = useTask(selectedTaskId ?? undefined)
   const selectedTask = taskDetail?.data ?? null
+  const { data: liveRuns } = useLiveRuns()
+  // Task IDs are per project: match the run's project path too
+  const { data: projectData } = useProject(projectId)
+  const projectPath = (projectData?.data as Project | undefined)?.path
+  const isSelectedTaskLive = selectedTaskId !== null && !!projectPath &&
+    !!liveRuns?.some(r => r.origin.project === projectPath && r.origin.task_id === selectedTaskId)
   const [expandedNodes, setExpandedNodes] = useState<Set<number>>(new Set())
   const [editField, setEditField] = useState<string | null>(null)
   const [editValue, setEditValue] = useState('')
@@ -135,6 +142,9 @@ export default function Tasks() {
             </div>
           </div>
 
+          {/* Output of the running plan/run */}
+          {isSelectedTaskLive && <LiveOutput target="tasks" id={selectedTask.id || selectedTask.ID} />}
+
           {/* Actions */}
           <div className="flex gap-2 flex-wrap">
             <Button
@@ -508,11 +518,12 @@ function TaskStatusBar({ items, statusFilter, onFilterChange, currentProject }:
   // Only show cycle status if it matches current project
   const cycleStatus = status?.cycle_status?.project_id === currentProject ? status.cycle_status : undefined
 
-  const statuses = ['todo', 'split', 'planned', 'done', 'failed'] as const
+  const statuses = ['todo', 'split', 'planned', 'review', 'done', 'failed'] as const
   const colors: Record<string, string> = {
     todo: 'bg-gray-400',
     split: 'bg-blue-400',
     planned: 'bg-yellow-400',
+    review: 'bg-purple-400',
     done: 'bg-green-400',
     failed: 'bg-red-400',
   }
@@ -608,6 +619,7 @@ function StatusDot({ status }: { status: string }) {
     todo: 'bg-gray-400',
     split: 'bg-blue-400',
     planned: 'bg-yellow-400',
+    review: 'bg-purple-400',
     done: 'bg-green-400',
     failed: 'bg-red-400',
   }
@@ -32,14 +32,90 @@ export interface Task {
   spec: string
   plan: string
   report: string
-  status: 'todo' | 'split' | 'planned' | 'done' | 'failed'
+  status: 'todo' | 'split' | 'planned' | 'review' | 'done' | 'failed'
   error: string
   is_leaf: boolean
   depth: number
+  labels?: string[]
+  events?: TaskEvent[]
   created_at: string
   updated_at: string
 }
 
+// Task status change (task_events)
+export interface TaskEvent {
+  id: number
+  task_id: number
+  from_status: string
+  to_status: string
+  actor: string // cli, gui, telegram:<id>, cycle, rollup, sync, watch, rebuild
+  traversal_id?: number
+  created_at: string
+}
+
+// Task list filter (all conditions combine with AND)
+export interface TaskFilter {
+  label?: string
+  status?: string
+  depth?: string
+  root?: string
+  text?: string
+}
+
+// Saved Query
+export interface SavedQuery {
+  name: string
+  filter: {
+    labels?: string[]
+    status?: string[]
+    depth?: number
+    root?: number
+    text?: string
+  }
+  query: string
+  created_at: string
+  updated_at: string
+}
+
+// Search Hit
+export interface SearchHit {
+  task_id: number
+  title: string
+  status: Task['status']
+  kind: 'spec' | 'plan' | 'report' | 'error'
+  snippet: string
+  rank: number
+}
+
+// Traversal
+export interface Traversal {
+  id: number
+  type: 'plan' | 'run' | 'cycle'
+  target_id?: number
+  trigger?: string
+  status: 'running' | 'done' | 'failed' | 'cancelled' | 'interrupted'
+  total: number
+  success: number
+  failed: number
+  started_at: string
+  finished_at?: string
+  items?: TraversalItem[]
+}
+
+// Traversal Item
+export interface TraversalItem {
+  id: number
+  task_id: number
+  title: string
+  phase: 'plan' | 'run'
+  result: 'planned' | 'review' | 'split' | 'done' | 'failed' | 'cancelled'
+  error_type?: string
+  exit_code?: number
+  duration_ms: number
+  started_at: string
+  finished_at: string
+}
+
 // Message
 export interface Message {
   id: number
@@ -49,6 +125,7 @@ export interface Message {
   status: 'pending' | 'processing' | 'done' | 'failed'
   result: string
   error: string
+  run_id?: string
   created_at: string
   completed_at: string | null
 }
@@ -75,6 +152,7 @@ export interface ScheduleRun {
   status: 'running' | 'done' | 'failed'
   result: string
   error: string
+  run_id?: string
   started_at: string
   completed_at: string | null
 }
@@ -95,6 +173,47 @@ export interface ClaudeStatus {
   used: number
   max: number
   available: number
+  queued?: number
+}
+
+// Claude Queue (from /api/claude/queue)
+export interface QueueEntry {
+  id: number
+  state: 'running' | 'waiting'
+  position: number
+  priority: 'interactive' | 'schedule' | 'batch'
+  origin: {
+    project?: string
+    task_id?: number
+    message_id?: number
+    schedule_id?: number
+  }
+  enqueued_at: string
+  started_at?: string
+}
+
+export interface QueueStatus {
+  max: number
+  running: QueueEntry[]
+  waiting: QueueEntry[]
+}
+
+// Live Claude run (GET /api/runs/live, "done" event of the output streams)
+export interface LiveRun {
+  run_id: string
+  agent: string
+  origin: QueueEntry['origin']
+  started_at: string
+  offset: number
+  done: boolean
+  exit_code: number
+  error?: string
+}
+
+// "output" event of the output streams
+export interface LiveChunk {
+  offset: number
+  data: string
 }
 
 // Task Stats
@@ -103,6 +222,7 @@ export interface TaskStats {
   leaf: number
   todo: number
   planned: number
+  review?: number
   split: number
   done: number
   failed: number
@@ -121,6 +241,7 @@ export interface CycleStatus {
   status: 'idle' | 'running' | 'interrupted'
   type?: string
   project_id?: string
+  root_id?: number
   started_at?: string
   current_task_id?: number
   active_workers?: number